@@ -0,0 +1,90 @@
+// Command pbxproj-merge is a git merge driver for project.pbxproj files,
+// it merges the projects object by object and only marks the conflicting objects.
+//
+// Register it in the repository's git config:
+//
+//	git config merge.pbxproj.name "project.pbxproj merge driver"
+//	git config merge.pbxproj.driver "pbxproj-merge %O %A %B %P"
+//
+// and assign it to the project files in .gitattributes:
+//
+//	*.pbxproj merge=pbxproj
+//
+// The merged project is written to the %A file. On conflicts the conflicting objects are written
+// between git's conflict markers (our and their definition of the object), the conflicts, including the references
+// to the objects removed on one side, are printed to the standard error and the command exits with 1,
+// so git marks the file as conflicted.
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/bitrise-io/xcode-project/xcodeproj"
+)
+
+func projectName(pth string) string {
+	dir := filepath.Base(filepath.Dir(pth))
+	if filepath.Ext(dir) != ".xcodeproj" {
+		return ""
+	}
+	return strings.TrimSuffix(dir, ".xcodeproj")
+}
+
+func run(args []string) (int, error) {
+	if len(args) < 3 || len(args) > 4 {
+		return 2, fmt.Errorf("usage: pbxproj-merge BASE OURS THEIRS [PATH]")
+	}
+	basePth, oursPth, theirsPth := args[0], args[1], args[2]
+
+	var name string
+	if len(args) == 4 {
+		name = projectName(args[3])
+	}
+
+	base, err := ioutil.ReadFile(basePth)
+	if err != nil {
+		return 2, fmt.Errorf("failed to read base (%s): %s", basePth, err)
+	}
+	ours, err := ioutil.ReadFile(oursPth)
+	if err != nil {
+		return 2, fmt.Errorf("failed to read ours (%s): %s", oursPth, err)
+	}
+	theirs, err := ioutil.ReadFile(theirsPth)
+	if err != nil {
+		return 2, fmt.Errorf("failed to read theirs (%s): %s", theirsPth, err)
+	}
+
+	merged, conflicts, err := xcodeproj.MergePBXProj(name, base, ours, theirs)
+	if err != nil {
+		return 2, err
+	}
+
+	if len(conflicts) > 0 {
+		merged = xcodeproj.MarkMergeConflicts(merged, theirs, conflicts)
+	}
+
+	if err := ioutil.WriteFile(oursPth, merged, 0644); err != nil {
+		return 2, fmt.Errorf("failed to write merged project (%s): %s", oursPth, err)
+	}
+
+	if len(conflicts) > 0 {
+		fmt.Fprintf(os.Stderr, "%d conflict(s), the conflicting objects are marked in the project:\n", len(conflicts))
+		for _, conflict := range conflicts {
+			fmt.Fprintf(os.Stderr, "  %s\n", conflict)
+		}
+		return 1, nil
+	}
+	return 0, nil
+}
+
+func main() {
+	code, err := run(os.Args[1:])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "pbxproj-merge: %s\n", err)
+	}
+	os.Exit(code)
+}
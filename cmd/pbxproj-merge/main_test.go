@@ -0,0 +1,79 @@
+package main
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	uiTestsBundleID        = "PRODUCT_BUNDLE_IDENTIFIER = com.bitrise.XcodeProjUITests;"
+	todayExtensionBundleID = "PRODUCT_BUNDLE_IDENTIFIER = com.bitrise.XcodeProj.TodayExtension;"
+)
+
+// mergeFiles writes the base, ours and theirs files (like git does for a merge driver)
+// and returns the arguments of the merge driver: their paths followed by the project file's path.
+func mergeFiles(t *testing.T, ours, theirs string) []string {
+	dir, err := pathutil.NormalizedOSTempDirPath("__pbxproj-merge__")
+	require.NoError(t, err)
+
+	var args []string
+	for i, content := range []string{testhelper.XcodeProjectTest, ours, theirs} {
+		pth := filepath.Join(dir, []string{"base", "ours", "theirs"}[i])
+		require.NoError(t, fileutil.WriteStringToFile(pth, content))
+		args = append(args, pth)
+	}
+	return append(args, "XcodeProj.xcodeproj/project.pbxproj")
+}
+
+func TestRun(t *testing.T) {
+	t.Log("independent changes")
+	{
+		ours := strings.Replace(testhelper.XcodeProjectTest, uiTestsBundleID, "PRODUCT_BUNDLE_IDENTIFIER = com.bitrise.XcodeProjUITests.Ours;", -1)
+		theirs := strings.Replace(testhelper.XcodeProjectTest, todayExtensionBundleID, "PRODUCT_BUNDLE_IDENTIFIER = com.bitrise.XcodeProj.TodayExtension.Theirs;", -1)
+		args := mergeFiles(t, ours, theirs)
+
+		code, err := run(args)
+		require.NoError(t, err)
+		require.Equal(t, 0, code)
+
+		merged, err := ioutil.ReadFile(args[1])
+		require.NoError(t, err)
+		require.Contains(t, string(merged), "com.bitrise.XcodeProjUITests.Ours;")
+		require.Contains(t, string(merged), "com.bitrise.XcodeProj.TodayExtension.Theirs;")
+		require.NotContains(t, string(merged), "<<<<<<<")
+	}
+
+	t.Log("conflicting changes")
+	{
+		ours := strings.Replace(testhelper.XcodeProjectTest, uiTestsBundleID, "PRODUCT_BUNDLE_IDENTIFIER = com.bitrise.XcodeProjUITests.Ours;", -1)
+		theirs := strings.Replace(testhelper.XcodeProjectTest, uiTestsBundleID, "PRODUCT_BUNDLE_IDENTIFIER = com.bitrise.XcodeProjUITests.Theirs;", -1)
+		args := mergeFiles(t, ours, theirs)
+
+		code, err := run(args)
+		require.NoError(t, err)
+		require.Equal(t, 1, code)
+
+		merged, err := ioutil.ReadFile(args[1])
+		require.NoError(t, err)
+		// both build configurations of the UI test target conflict
+		require.Equal(t, 2, strings.Count(string(merged), "<<<<<<< ours\n"))
+		require.Equal(t, 2, strings.Count(string(merged), "\n=======\n"))
+		require.Equal(t, 2, strings.Count(string(merged), ">>>>>>> theirs\n"))
+		require.Equal(t, 2, strings.Count(string(merged), "com.bitrise.XcodeProjUITests.Ours;"))
+		require.Equal(t, 2, strings.Count(string(merged), "com.bitrise.XcodeProjUITests.Theirs;"))
+	}
+
+	t.Log("invalid arguments")
+	{
+		code, err := run([]string{"base"})
+		require.EqualError(t, err, "usage: pbxproj-merge BASE OURS THEIRS [PATH]")
+		require.Equal(t, 2, code)
+	}
+}
@@ -0,0 +1,89 @@
+package swiftpm
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+)
+
+// ManifestFileName is the name of the Swift package manifest file.
+const ManifestFileName = "Package.swift"
+
+// packageDependencyPattern matches the remote package dependencies of a manifest:
+// `.package(url: "https://github.com/apple/swift-log.git", from: "1.0.0")` or `.package(name: "Log", url: "...", ...)`.
+var packageDependencyPattern = regexp.MustCompile(`\.package\s*\(\s*(?:name\s*:\s*"[^"]*"\s*,\s*)?url\s*:\s*"([^"]+)"`)
+
+// Dependencies are the identities of the packages' direct remote dependencies by the package identities.
+type Dependencies map[string][]string
+
+// ManifestDependencies returns the identities of the remote packages the manifest (Package.swift) depends on.
+// Local (path based) dependencies are not pinned, they are skipped.
+func ManifestDependencies(content []byte) []string {
+	var identities []string
+	for _, match := range packageDependencyPattern.FindAllSubmatch(content, -1) {
+		identities = append(identities, Identity(string(match[1])))
+	}
+	return identities
+}
+
+// OpenCheckoutDependencies returns the dependencies of the packages checked out in the source packages directory
+// (like DerivedData/<project>/SourcePackages or the -clonedSourcePackagesDirPath of xcodebuild),
+// read from the manifests of the checkouts: <sourcePackagesDir>/checkouts/<package>/Package.swift.
+// The packages without a checkout or a manifest are missing from the returned dependencies.
+func OpenCheckoutDependencies(sourcePackagesDir string) (Dependencies, error) {
+	dependencies := Dependencies{}
+
+	checkoutsDir := filepath.Join(sourcePackagesDir, "checkouts")
+	entries, err := ioutil.ReadDir(checkoutsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return dependencies, nil
+		}
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		manifestPth := filepath.Join(checkoutsDir, entry.Name(), ManifestFileName)
+		if exist, err := pathutil.IsPathExists(manifestPth); err != nil {
+			return nil, err
+		} else if !exist {
+			continue
+		}
+
+		content, err := fileutil.ReadBytesFromFile(manifestPth)
+		if err != nil {
+			return nil, err
+		}
+		dependencies[Identity(entry.Name())] = ManifestDependencies(content)
+	}
+
+	return dependencies, nil
+}
+
+// Transitive returns the given packages and the packages they depend on, directly or indirectly, by their identities.
+func (d Dependencies) Transitive(identities ...string) map[string]bool {
+	reached := map[string]bool{}
+	var visit func(identity string)
+	visit = func(identity string) {
+		if reached[identity] {
+			return
+		}
+		reached[identity] = true
+		for _, dependency := range d[identity] {
+			visit(dependency)
+		}
+	}
+
+	for _, identity := range identities {
+		visit(identity)
+	}
+	return reached
+}
@@ -0,0 +1,49 @@
+package swiftpm
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/stretchr/testify/require"
+)
+
+const manifest = `// swift-tools-version:5.5
+import PackageDescription
+
+let package = Package(
+    name: "SwiftLint",
+    dependencies: [
+        .package(url: "https://github.com/apple/swift-argument-parser.git", .upToNextMinor(from: "1.2.0")),
+        .package(name: "SwiftSyntax", url: "https://github.com/apple/swift-syntax.git", .exact("0.50700.0")),
+        .package(path: "../Local"),
+    ],
+    targets: []
+)
+`
+
+func TestManifestDependencies(t *testing.T) {
+	require.Equal(t, []string{"swift-argument-parser", "swift-syntax"}, ManifestDependencies([]byte(manifest)))
+	require.Equal(t, 0, len(ManifestDependencies([]byte(`let package = Package(name: "Empty")`))))
+}
+
+func TestOpenCheckoutDependencies(t *testing.T) {
+	dir, err := pathutil.NormalizedOSTempDirPath("__swiftpm__")
+	require.NoError(t, err)
+
+	dependencies, err := OpenCheckoutDependencies(dir)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(dependencies))
+
+	pth := filepath.Join(dir, "checkouts", "SwiftLint", ManifestFileName)
+	require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0755))
+	require.NoError(t, fileutil.WriteStringToFile(pth, manifest))
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "checkouts", "swift-syntax"), 0755))
+
+	dependencies, err = OpenCheckoutDependencies(dir)
+	require.NoError(t, err)
+	require.Equal(t, Dependencies{"swiftlint": {"swift-argument-parser", "swift-syntax"}}, dependencies)
+	require.Equal(t, map[string]bool{"swiftlint": true, "swift-argument-parser": true, "swift-syntax": true, "kingfisher": true}, dependencies.Transitive("swiftlint", "kingfisher"))
+}
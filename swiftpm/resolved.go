@@ -0,0 +1,141 @@
+package swiftpm
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/fileutil"
+)
+
+// ResolvedFileName is the name of the file storing the resolved Swift package versions.
+const ResolvedFileName = "Package.resolved"
+
+// Pin is a resolved Swift package.
+type Pin struct {
+	// Identity is the lowercased package name derived from the location, like: alamofire
+	Identity string
+	// Kind is the kind of the package location (remoteSourceControl, localSourceControl), empty in v1 files.
+	Kind     string
+	Location string
+	Version  string
+	Branch   string
+	Revision string
+}
+
+// Resolved is a parsed Package.resolved file.
+type Resolved struct {
+	// Version is the version of the file format: 1, 2 or 3.
+	Version int
+	Pins    []Pin
+}
+
+// Pin returns the pin of the package with the given identity.
+func (r Resolved) Pin(identity string) (Pin, bool) {
+	for _, pin := range r.Pins {
+		if pin.Identity == identity {
+			return pin, true
+		}
+	}
+	return Pin{}, false
+}
+
+type pinState struct {
+	Version  *string `json:"version"`
+	Branch   *string `json:"branch"`
+	Revision string  `json:"revision"`
+}
+
+type pinV1 struct {
+	Package       string   `json:"package"`
+	RepositoryURL string   `json:"repositoryURL"`
+	State         pinState `json:"state"`
+}
+
+type pinV2 struct {
+	Identity string   `json:"identity"`
+	Kind     string   `json:"kind"`
+	Location string   `json:"location"`
+	State    pinState `json:"state"`
+}
+
+type resolvedModel struct {
+	Version int `json:"version"`
+	// v1
+	Object *struct {
+		Pins []pinV1 `json:"pins"`
+	} `json:"object"`
+	// v2, v3
+	Pins []pinV2 `json:"pins"`
+}
+
+// Open parses the Package.resolved file at pth.
+func Open(pth string) (Resolved, error) {
+	b, err := fileutil.ReadBytesFromFile(pth)
+	if err != nil {
+		return Resolved{}, err
+	}
+
+	resolved, err := Parse(b)
+	if err != nil {
+		return Resolved{}, fmt.Errorf("failed to parse %s: %s", pth, err)
+	}
+	return resolved, nil
+}
+
+// Parse parses the content of a Package.resolved file.
+func Parse(content []byte) (Resolved, error) {
+	var model resolvedModel
+	if err := json.Unmarshal(content, &model); err != nil {
+		return Resolved{}, err
+	}
+
+	resolved := Resolved{Version: model.Version}
+	switch model.Version {
+	case 1:
+		if model.Object == nil {
+			return Resolved{}, fmt.Errorf("missing object")
+		}
+
+		for _, p := range model.Object.Pins {
+			resolved.Pins = append(resolved.Pins, newPin(Identity(p.RepositoryURL), "", p.RepositoryURL, p.State))
+		}
+	case 2, 3:
+		for _, p := range model.Pins {
+			identity := p.Identity
+			if identity == "" {
+				identity = Identity(p.Location)
+			}
+			resolved.Pins = append(resolved.Pins, newPin(identity, p.Kind, p.Location, p.State))
+		}
+	default:
+		return Resolved{}, fmt.Errorf("unsupported Package.resolved version: %d", model.Version)
+	}
+
+	return resolved, nil
+}
+
+func newPin(identity, kind, location string, state pinState) Pin {
+	pin := Pin{
+		Identity: identity,
+		Kind:     kind,
+		Location: location,
+		Revision: state.Revision,
+	}
+	if state.Version != nil {
+		pin.Version = *state.Version
+	}
+	if state.Branch != nil {
+		pin.Branch = *state.Branch
+	}
+	return pin
+}
+
+// Identity returns the package identity of a package location,
+// the lowercased last path component without the .git extension:
+// https://github.com/Alamofire/Alamofire.git -> alamofire
+func Identity(location string) string {
+	location = strings.TrimSuffix(strings.TrimRight(location, "/"), ".git")
+	return strings.ToLower(path.Base(location))
+}
@@ -0,0 +1,151 @@
+package swiftpm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const resolvedV1 = `{
+  "object": {
+    "pins": [
+      {
+        "package": "Alamofire",
+        "repositoryURL": "https://github.com/Alamofire/Alamofire.git",
+        "state": {
+          "branch": null,
+          "revision": "f96b619bcb2383b43d898402283924b80e2c4bae",
+          "version": "5.4.3"
+        }
+      },
+      {
+        "package": "SnapshotTesting",
+        "repositoryURL": "https://github.com/pointfreeco/swift-snapshot-testing",
+        "state": {
+          "branch": "main",
+          "revision": "f8a9c997c3c1dab4e216a8ec9014e23144cbab37",
+          "version": null
+        }
+      }
+    ]
+  },
+  "version": 1
+}
+`
+
+const resolvedV2 = `{
+  "pins" : [
+    {
+      "identity" : "alamofire",
+      "kind" : "remoteSourceControl",
+      "location" : "https://github.com/Alamofire/Alamofire.git",
+      "state" : {
+        "revision" : "f96b619bcb2383b43d898402283924b80e2c4bae",
+        "version" : "5.4.3"
+      }
+    },
+    {
+      "identity" : "swift-snapshot-testing",
+      "kind" : "remoteSourceControl",
+      "location" : "https://github.com/pointfreeco/swift-snapshot-testing",
+      "state" : {
+        "branch" : "main",
+        "revision" : "f8a9c997c3c1dab4e216a8ec9014e23144cbab37"
+      }
+    }
+  ],
+  "version" : 2
+}
+`
+
+const resolvedV3 = `{
+  "originHash" : "6b2bc1bbb3d2f5b4ffef3e3b4b1b3e1a3b1c7ea6b4a2e6f2d2a5c8f0e1b3c4d5",
+  "pins" : [
+    {
+      "identity" : "alamofire",
+      "kind" : "remoteSourceControl",
+      "location" : "https://github.com/Alamofire/Alamofire.git",
+      "state" : {
+        "revision" : "f96b619bcb2383b43d898402283924b80e2c4bae",
+        "version" : "5.4.3"
+      }
+    },
+    {
+      "identity" : "swift-snapshot-testing",
+      "kind" : "remoteSourceControl",
+      "location" : "https://github.com/pointfreeco/swift-snapshot-testing",
+      "state" : {
+        "branch" : "main",
+        "revision" : "f8a9c997c3c1dab4e216a8ec9014e23144cbab37"
+      }
+    }
+  ],
+  "version" : 3
+}
+`
+
+func TestParse(t *testing.T) {
+	alamofire := Pin{
+		Identity: "alamofire",
+		Kind:     "remoteSourceControl",
+		Location: "https://github.com/Alamofire/Alamofire.git",
+		Version:  "5.4.3",
+		Revision: "f96b619bcb2383b43d898402283924b80e2c4bae",
+	}
+	snapshotTesting := Pin{
+		Identity: "swift-snapshot-testing",
+		Kind:     "remoteSourceControl",
+		Location: "https://github.com/pointfreeco/swift-snapshot-testing",
+		Branch:   "main",
+		Revision: "f8a9c997c3c1dab4e216a8ec9014e23144cbab37",
+	}
+	alamofireV1 := alamofire
+	alamofireV1.Kind = ""
+	snapshotTestingV1 := snapshotTesting
+	snapshotTestingV1.Kind = ""
+
+	tests := []struct {
+		name    string
+		content string
+		want    Resolved
+		wantErr string
+	}{
+		{
+			name:    "v1",
+			content: resolvedV1,
+			want:    Resolved{Version: 1, Pins: []Pin{alamofireV1, snapshotTestingV1}},
+		},
+		{
+			name:    "v2",
+			content: resolvedV2,
+			want:    Resolved{Version: 2, Pins: []Pin{alamofire, snapshotTesting}},
+		},
+		{
+			name:    "v3",
+			content: resolvedV3,
+			want:    Resolved{Version: 3, Pins: []Pin{alamofire, snapshotTesting}},
+		},
+		{
+			name:    "unsupported version",
+			content: `{"pins": [], "version": 4}`,
+			wantErr: "unsupported Package.resolved version: 4",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse([]byte(tt.content))
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIdentity(t *testing.T) {
+	require.Equal(t, "alamofire", Identity("https://github.com/Alamofire/Alamofire.git"))
+	require.Equal(t, "swift-log", Identity("https://github.com/apple/swift-log/"))
+	require.Equal(t, "kingfisher", Identity("git@github.com:onevcat/Kingfisher.git"))
+}
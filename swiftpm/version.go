@@ -0,0 +1,115 @@
+package swiftpm
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Version is a semantic version, as used by the Swift package manager.
+type Version struct {
+	Major      int
+	Minor      int
+	Patch      int
+	PreRelease []string
+}
+
+// ParseVersion parses a semantic version, the minor and patch components are optional: 5, 5.4, 5.4.0-beta.1+build
+func ParseVersion(s string) (Version, error) {
+	original := s
+	s = strings.TrimPrefix(s, "v")
+
+	if idx := strings.Index(s, "+"); idx != -1 {
+		s = s[:idx]
+	}
+
+	var preRelease []string
+	if idx := strings.Index(s, "-"); idx != -1 {
+		preRelease = strings.Split(s[idx+1:], ".")
+		s = s[:idx]
+	}
+
+	components := strings.Split(s, ".")
+	if len(components) > 3 {
+		return Version{}, fmt.Errorf("invalid version: %s", original)
+	}
+
+	var numbers [3]int
+	for i, component := range components {
+		n, err := strconv.Atoi(component)
+		if err != nil || n < 0 {
+			return Version{}, fmt.Errorf("invalid version: %s", original)
+		}
+		numbers[i] = n
+	}
+
+	return Version{
+		Major:      numbers[0],
+		Minor:      numbers[1],
+		Patch:      numbers[2],
+		PreRelease: preRelease,
+	}, nil
+}
+
+// String ...
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if len(v.PreRelease) > 0 {
+		s += "-" + strings.Join(v.PreRelease, ".")
+	}
+	return s
+}
+
+// Compare returns -1 if v is lower than other, 1 if v is greater than other and 0 if they are equal.
+// Pre-release versions have lower precedence than the related release version.
+func (v Version) Compare(other Version) int {
+	for _, pair := range [][2]int{{v.Major, other.Major}, {v.Minor, other.Minor}, {v.Patch, other.Patch}} {
+		if c := compareInt(pair[0], pair[1]); c != 0 {
+			return c
+		}
+	}
+
+	switch {
+	case len(v.PreRelease) == 0 && len(other.PreRelease) == 0:
+		return 0
+	case len(v.PreRelease) == 0:
+		return 1
+	case len(other.PreRelease) == 0:
+		return -1
+	}
+
+	for i := 0; i < len(v.PreRelease) && i < len(other.PreRelease); i++ {
+		if c := comparePreReleaseIdentifier(v.PreRelease[i], other.PreRelease[i]); c != 0 {
+			return c
+		}
+	}
+	return compareInt(len(v.PreRelease), len(other.PreRelease))
+}
+
+// comparePreReleaseIdentifier compares numeric identifiers numerically, the others lexically,
+// numeric identifiers have lower precedence than alphanumeric ones.
+func comparePreReleaseIdentifier(a, b string) int {
+	aNum, aErr := strconv.Atoi(a)
+	bNum, bErr := strconv.Atoi(b)
+	switch {
+	case aErr == nil && bErr == nil:
+		return compareInt(aNum, bNum)
+	case aErr == nil:
+		return -1
+	case bErr == nil:
+		return 1
+	default:
+		return strings.Compare(a, b)
+	}
+}
+
+func compareInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
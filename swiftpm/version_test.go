@@ -0,0 +1,52 @@
+package swiftpm
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseVersion(t *testing.T) {
+	version, err := ParseVersion("5.4.0-beta.1+build.7")
+	require.NoError(t, err)
+	require.Equal(t, Version{Major: 5, Minor: 4, PreRelease: []string{"beta", "1"}}, version)
+	require.Equal(t, "5.4.0-beta.1", version.String())
+
+	version, err = ParseVersion("5.4")
+	require.NoError(t, err)
+	require.Equal(t, Version{Major: 5, Minor: 4}, version)
+
+	_, err = ParseVersion("5.4.x")
+	require.EqualError(t, err, "invalid version: 5.4.x")
+
+	_, err = ParseVersion("1.2.3.4")
+	require.EqualError(t, err, "invalid version: 1.2.3.4")
+}
+
+func TestVersion_Compare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "1.0.0", b: "1.0.0", want: 0},
+		{a: "1.0.0", b: "1.0.1", want: -1},
+		{a: "1.10.0", b: "1.9.0", want: 1},
+		{a: "2.0.0", b: "1.99.99", want: 1},
+		{a: "1.0.0-alpha", b: "1.0.0", want: -1},
+		{a: "1.0.0-alpha", b: "1.0.0-alpha.1", want: -1},
+		{a: "1.0.0-alpha.2", b: "1.0.0-alpha.10", want: -1},
+		{a: "1.0.0-beta", b: "1.0.0-alpha.beta", want: 1},
+		{a: "1.0.0-rc.1", b: "1.0.0-1", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+" vs "+tt.b, func(t *testing.T) {
+			a, err := ParseVersion(tt.a)
+			require.NoError(t, err)
+			b, err := ParseVersion(tt.b)
+			require.NoError(t, err)
+
+			require.Equal(t, tt.want, a.Compare(b))
+			require.Equal(t, -tt.want, b.Compare(a))
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package testhelper
+
+import "strings"
+
+// XcodeProjectTestWithProjectDependency returns the XcodeProjectTest project.pbxproj, where the XcodeProj target
+// depends on the TodayExtension target of another project (a copy of XcodeProjectTest) at projectPath, relative to the main group.
+func XcodeProjectTestWithProjectDependency(projectPath string) string {
+	replacer := strings.NewReplacer(
+		"/* Begin PBXContainerItemProxy section */\n", `/* Begin PBXContainerItemProxy section */
+		0000000000000000000000A1 /* PBXContainerItemProxy */ = {
+			isa = PBXContainerItemProxy;
+			containerPortal = 0000000000000000000000A3 /* `+projectPath+` */;
+			proxyType = 1;
+			remoteGlobalIDString = 7D03430C20F4BB070050B6A6;
+			remoteInfo = TodayExtension;
+		};
+`,
+		"/* Begin PBXFileReference section */\n", `/* Begin PBXFileReference section */
+		0000000000000000000000A3 /* `+projectPath+` */ = {isa = PBXFileReference; lastKnownFileType = "wrapper.pb-project"; path = "`+projectPath+`"; sourceTree = "<group>"; };
+`,
+		"/* Begin PBXTargetDependency section */\n", `/* Begin PBXTargetDependency section */
+		0000000000000000000000A2 /* PBXTargetDependency */ = {
+			isa = PBXTargetDependency;
+			name = TodayExtension;
+			targetProxy = 0000000000000000000000A1 /* PBXContainerItemProxy */;
+		};
+`,
+		"\t\t\tchildren = (\n\t\t\t\t7D5B35FE20E28EE80022BAE6 /* XcodeProj */,\n", "\t\t\tchildren = (\n\t\t\t\t0000000000000000000000A3 /* "+projectPath+" */,\n\t\t\t\t7D5B35FE20E28EE80022BAE6 /* XcodeProj */,\n",
+		"\t\t\t\t7D03431920F4BB070050B6A6 /* PBXTargetDependency */,\n", "\t\t\t\t7D03431920F4BB070050B6A6 /* PBXTargetDependency */,\n\t\t\t\t0000000000000000000000A2 /* PBXTargetDependency */,\n",
+	)
+	return replacer.Replace(XcodeProjectTest)
+}
@@ -0,0 +1,246 @@
+package xcconfig
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/xcode-project/serialized"
+)
+
+// LineType ...
+type LineType string
+
+// LineTypes
+const (
+	// OtherLine is an empty, comment or not recognised line, it is kept as it is.
+	OtherLine LineType = "other"
+	// AssignmentLine is a build setting assignment: `KEY[sdk=iphoneos*] = VALUE // comment`
+	AssignmentLine LineType = "assignment"
+	// IncludeLine is an include directive: `#include "Shared.xcconfig"` or `#include? "Optional.xcconfig"`
+	IncludeLine LineType = "include"
+)
+
+// Line is a single line of an xcconfig file.
+type Line struct {
+	Type LineType
+	// Raw is the original content of the line, it is written back if the line is not modified.
+	Raw string
+
+	// Key is the assigned build setting's name including the conditions, like: CODE_SIGN_IDENTITY[sdk=iphoneos*]
+	Key   string
+	Value string
+	// Comment is the trailing comment of the assignment, including the leading `//`.
+	Comment string
+
+	// IncludePath is the path as written in the include directive.
+	IncludePath string
+	// Optional is true for the `#include?` directives, which do not fail if the file does not exist.
+	Optional bool
+	// Included is the parsed included file, nil if an optional include does not exist.
+	Included *Config
+}
+
+// Config is a parsed xcconfig file.
+type Config struct {
+	Path  string
+	Lines []Line
+}
+
+var (
+	includePattern    = regexp.MustCompile(`^\s*#include(\?)?\s*"(.*)"\s*$`)
+	assignmentPattern = regexp.MustCompile(`^\s*([A-Za-z_][A-Za-z0-9_]*(?:\[[^\]]*\])*)\s*=(.*)$`)
+	inheritedPattern  = regexp.MustCompile(`[$][({]inherited[)}]`)
+)
+
+// Open parses the xcconfig file at pth, the included files are parsed too.
+func Open(pth string) (Config, error) {
+	absPth, err := pathutil.AbsPath(pth)
+	if err != nil {
+		return Config{}, err
+	}
+
+	return open(absPth, nil)
+}
+
+func open(pth string, includeStack []string) (Config, error) {
+	for _, p := range includeStack {
+		if p == pth {
+			return Config{}, fmt.Errorf("xcconfig include cycle found: %s", strings.Join(append(includeStack, pth), " -> "))
+		}
+	}
+
+	content, err := fileutil.ReadStringFromFile(pth)
+	if err != nil {
+		return Config{}, err
+	}
+
+	config := Config{Path: pth}
+	for _, raw := range strings.Split(content, "\n") {
+		line := parseLine(raw)
+
+		if line.Type == IncludeLine {
+			includePth := line.IncludePath
+			if !filepath.IsAbs(includePth) {
+				includePth = filepath.Join(filepath.Dir(pth), includePth)
+			}
+
+			exist, err := pathutil.IsPathExists(includePth)
+			if err != nil {
+				return Config{}, err
+			}
+
+			if exist {
+				included, err := open(includePth, append(includeStack, pth))
+				if err != nil {
+					return Config{}, err
+				}
+				line.Included = &included
+			} else if !line.Optional {
+				return Config{}, fmt.Errorf("included xcconfig does not exist: %s (included from: %s)", includePth, pth)
+			}
+		}
+
+		config.Lines = append(config.Lines, line)
+	}
+
+	return config, nil
+}
+
+func parseLine(raw string) Line {
+	content := strings.TrimSuffix(raw, "\r")
+
+	if match := includePattern.FindStringSubmatch(content); match != nil {
+		return Line{
+			Type:        IncludeLine,
+			Raw:         raw,
+			IncludePath: match[2],
+			Optional:    match[1] == "?",
+		}
+	}
+
+	match := assignmentPattern.FindStringSubmatch(content)
+	if match == nil {
+		return Line{Type: OtherLine, Raw: raw}
+	}
+
+	value := match[2]
+	comment := ""
+	if idx := strings.Index(value, "//"); idx != -1 {
+		comment = strings.TrimSpace(value[idx:])
+		value = value[:idx]
+	}
+	value = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), ";"))
+
+	return Line{
+		Type:    AssignmentLine,
+		Raw:     raw,
+		Key:     match[1],
+		Value:   value,
+		Comment: comment,
+	}
+}
+
+// Settings returns the build settings defined in the file and in the included files.
+// Later assignments override the earlier ones, and `$(inherited)` refers to the earlier assigned value of the same key.
+// If the key was not assigned earlier, `$(inherited)` is kept, so that it can be replaced with the value of the lower layers.
+// Conditional assignments are returned with their conditions in the key, like: CODE_SIGN_IDENTITY[sdk=iphoneos*].
+func (c Config) Settings() serialized.Object {
+	settings := serialized.Object{}
+	c.collectSettings(settings)
+	return settings
+}
+
+func (c Config) collectSettings(settings serialized.Object) {
+	for _, line := range c.Lines {
+		switch line.Type {
+		case IncludeLine:
+			if line.Included != nil {
+				line.Included.collectSettings(settings)
+			}
+		case AssignmentLine:
+			value := line.Value
+			if inherited, ok := settings[line.Key].(string); ok {
+				value = inheritedPattern.ReplaceAllLiteralString(value, inherited)
+			}
+			settings[line.Key] = strings.TrimSpace(value)
+		}
+	}
+}
+
+// Value returns the value of the given key as defined in the file and in the included files, see Settings.
+func (c Config) Value(key string) (string, bool) {
+	value, ok := c.Settings()[key].(string)
+	return value, ok
+}
+
+// Set sets the value of the given key in this file (the included files are not modified).
+// The last assignment of the key is updated, or a new assignment is appended to the end of the file.
+func (c *Config) Set(key, value string) {
+	for i := len(c.Lines) - 1; i >= 0; i-- {
+		line := &c.Lines[i]
+		if line.Type != AssignmentLine || line.Key != key {
+			continue
+		}
+
+		line.Value = value
+		line.Raw = assignment(key, value, line.Comment)
+		return
+	}
+
+	newLine := Line{
+		Type:  AssignmentLine,
+		Raw:   assignment(key, value, ""),
+		Key:   key,
+		Value: value,
+	}
+
+	// keep the trailing newline of the file
+	if len(c.Lines) > 0 && c.Lines[len(c.Lines)-1].Raw == "" {
+		c.Lines = append(c.Lines[:len(c.Lines)-1], newLine, c.Lines[len(c.Lines)-1])
+	} else {
+		c.Lines = append(c.Lines, newLine)
+	}
+}
+
+// Delete removes all the assignments of the given key from this file (the included files are not modified),
+// and reports whether the key was found.
+func (c *Config) Delete(key string) bool {
+	var lines []Line
+	found := false
+	for _, line := range c.Lines {
+		if line.Type == AssignmentLine && line.Key == key {
+			found = true
+			continue
+		}
+		lines = append(lines, line)
+	}
+	c.Lines = lines
+	return found
+}
+
+func assignment(key, value, comment string) string {
+	line := key + " = " + value
+	if comment != "" {
+		line += " " + comment
+	}
+	return line
+}
+
+// Bytes returns the content of the file, the not modified lines are kept as they were.
+func (c Config) Bytes() []byte {
+	var lines []string
+	for _, line := range c.Lines {
+		lines = append(lines, line.Raw)
+	}
+	return []byte(strings.Join(lines, "\n"))
+}
+
+// Save writes the file to its Path (the included files are not written).
+func (c Config) Save() error {
+	return ioutil.WriteFile(c.Path, c.Bytes(), 0644)
+}
@@ -0,0 +1,126 @@
+package xcconfig
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/xcode-project/serialized"
+	"github.com/stretchr/testify/require"
+)
+
+func writeFiles(t *testing.T, files map[string]string) string {
+	dir, err := pathutil.NormalizedOSTempDirPath("__xcconfig__")
+	require.NoError(t, err)
+
+	for name, content := range files {
+		require.NoError(t, ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0644))
+	}
+	return dir
+}
+
+const sharedXcconfig = `// Shared settings
+OTHER_LDFLAGS = -ObjC
+SWIFT_VERSION = 5.0;
+`
+
+const appXcconfig = `#include "Shared.xcconfig"
+#include? "Pods.xcconfig"
+
+// Signing
+CODE_SIGN_IDENTITY = Apple Development // default identity
+CODE_SIGN_IDENTITY[sdk=iphoneos*] = iPhone Developer
+OTHER_LDFLAGS = $(inherited) -framework UIKit
+HEADER_SEARCH_PATHS = $(inherited) Headers
+`
+
+func TestOpen(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"Shared.xcconfig": sharedXcconfig,
+		"App.xcconfig":    appXcconfig,
+	})
+
+	config, err := Open(filepath.Join(dir, "App.xcconfig"))
+	require.NoError(t, err)
+	require.Equal(t, 9, len(config.Lines))
+
+	require.Equal(t, Line{Type: IncludeLine, Raw: `#include "Shared.xcconfig"`, IncludePath: "Shared.xcconfig", Included: config.Lines[0].Included}, config.Lines[0])
+	require.NotNil(t, config.Lines[0].Included)
+	require.Equal(t, filepath.Join(dir, "Shared.xcconfig"), config.Lines[0].Included.Path)
+
+	require.Equal(t, Line{Type: IncludeLine, Raw: `#include? "Pods.xcconfig"`, IncludePath: "Pods.xcconfig", Optional: true}, config.Lines[1])
+	require.Equal(t, Line{Type: OtherLine, Raw: "// Signing"}, config.Lines[3])
+	require.Equal(t, Line{
+		Type:    AssignmentLine,
+		Raw:     "CODE_SIGN_IDENTITY = Apple Development // default identity",
+		Key:     "CODE_SIGN_IDENTITY",
+		Value:   "Apple Development",
+		Comment: "// default identity",
+	}, config.Lines[4])
+	require.Equal(t, "CODE_SIGN_IDENTITY[sdk=iphoneos*]", config.Lines[5].Key)
+	require.Equal(t, "iPhone Developer", config.Lines[5].Value)
+
+	require.Equal(t, serialized.Object{
+		"OTHER_LDFLAGS":                     "-ObjC -framework UIKit",
+		"SWIFT_VERSION":                     "5.0",
+		"CODE_SIGN_IDENTITY":                "Apple Development",
+		"CODE_SIGN_IDENTITY[sdk=iphoneos*]": "iPhone Developer",
+		"HEADER_SEARCH_PATHS":               "$(inherited) Headers",
+	}, config.Settings())
+
+	value, ok := config.Value("OTHER_LDFLAGS")
+	require.True(t, ok)
+	require.Equal(t, "-ObjC -framework UIKit", value)
+
+	_, ok = config.Value("NOT_DEFINED")
+	require.False(t, ok)
+}
+
+func TestOpen_Errors(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"Missing.xcconfig": `#include "NotExisting.xcconfig"`,
+		"A.xcconfig":       `#include "B.xcconfig"`,
+		"B.xcconfig":       `#include "A.xcconfig"`,
+	})
+
+	_, err := Open(filepath.Join(dir, "Missing.xcconfig"))
+	require.EqualError(t, err, "included xcconfig does not exist: "+filepath.Join(dir, "NotExisting.xcconfig")+" (included from: "+filepath.Join(dir, "Missing.xcconfig")+")")
+
+	_, err = Open(filepath.Join(dir, "A.xcconfig"))
+	require.EqualError(t, err, "xcconfig include cycle found: "+filepath.Join(dir, "A.xcconfig")+" -> "+filepath.Join(dir, "B.xcconfig")+" -> "+filepath.Join(dir, "A.xcconfig"))
+}
+
+func TestConfig_Bytes(t *testing.T) {
+	dir := writeFiles(t, map[string]string{
+		"Shared.xcconfig": sharedXcconfig,
+		"App.xcconfig":    appXcconfig,
+	})
+	pth := filepath.Join(dir, "App.xcconfig")
+
+	config, err := Open(pth)
+	require.NoError(t, err)
+	require.Equal(t, appXcconfig, string(config.Bytes()))
+
+	config.Set("CODE_SIGN_IDENTITY", "Apple Distribution")
+	config.Set("DEVELOPMENT_TEAM", "ABCD1234")
+	require.True(t, config.Delete("HEADER_SEARCH_PATHS"))
+	require.False(t, config.Delete("NOT_DEFINED"))
+	require.NoError(t, config.Save())
+
+	content, err := ioutil.ReadFile(pth)
+	require.NoError(t, err)
+	require.Equal(t, `#include "Shared.xcconfig"
+#include? "Pods.xcconfig"
+
+// Signing
+CODE_SIGN_IDENTITY = Apple Distribution // default identity
+CODE_SIGN_IDENTITY[sdk=iphoneos*] = iPhone Developer
+OTHER_LDFLAGS = $(inherited) -framework UIKit
+DEVELOPMENT_TEAM = ABCD1234
+`, string(content))
+
+	value, ok := config.Value("CODE_SIGN_IDENTITY")
+	require.True(t, ok)
+	require.Equal(t, "Apple Distribution", value)
+}
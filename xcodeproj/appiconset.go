@@ -92,7 +92,12 @@ func lookupAppIconPaths(projectPath string, assetCatalogs []fileReference, appIc
 
 func assetCatalogs(target Target, projectID string, objects serialized.Object) ([]fileReference, error) {
 	if target.Type == NativeTargetType { // Ignoring PBXAggregateTarget and PBXLegacyTarget as may not contain buildPhases key
-		resourcesBuildPhase, err := filterResourcesBuildPhase(target.buildPhaseIDs, objects)
+		var buildPhaseIDs []string
+		for _, buildPhase := range target.BuildPhasesOfType(ResourcesBuildPhaseType) {
+			buildPhaseIDs = append(buildPhaseIDs, buildPhase.ID)
+		}
+
+		resourcesBuildPhase, err := filterResourcesBuildPhase(buildPhaseIDs, objects)
 		if err != nil {
 			return nil, fmt.Errorf("getting resource build phases failed, error: %s", err)
 		}
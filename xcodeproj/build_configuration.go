@@ -1,12 +1,22 @@
 package xcodeproj
 
-import "github.com/bitrise-io/xcode-project/serialized"
+import (
+	"github.com/bitrise-io/go-utils/log"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/xcode-project/serialized"
+	"github.com/bitrise-io/xcode-project/xcconfig"
+)
 
 // BuildConfiguration ..
 type BuildConfiguration struct {
 	ID            string
 	Name          string
 	BuildSettings serialized.Object
+	// BaseConfigurationReference is the ID of the base configuration (.xcconfig) file reference, empty if not set.
+	BaseConfigurationReference string
+	// BaseConfiguration is the parsed base configuration file, loaded by Open.
+	// It is nil if the configuration has no base configuration or the file could not be read.
+	BaseConfiguration *xcconfig.Config
 }
 
 func parseBuildConfiguration(id string, objects serialized.Object) (BuildConfiguration, error) {
@@ -25,9 +35,86 @@ func parseBuildConfiguration(id string, objects serialized.Object) (BuildConfigu
 		return BuildConfiguration{}, err
 	}
 
+	baseConfigurationReference, err := raw.String("baseConfigurationReference")
+	if err != nil && !serialized.IsKeyNotFoundError(err) {
+		return BuildConfiguration{}, err
+	}
+
 	return BuildConfiguration{
-		ID:            id,
-		Name:          name,
-		BuildSettings: buildSettings,
+		ID:                         id,
+		Name:                       name,
+		BuildSettings:              buildSettings,
+		BaseConfigurationReference: baseConfigurationReference,
 	}, nil
 }
+
+// loadBaseConfigurations parses the base configuration files of the project's and the targets' build configurations.
+// Files which can not be read (like not yet installed CocoaPods configs) are skipped,
+// as xcodebuild would fall back to the rest of the build settings too.
+func (p *XcodeProj) loadBaseConfigurations() {
+	paths, err := p.SourceTreePaths()
+	if err != nil {
+		log.Warnf("failed to read project source tree paths: %s", err)
+		return
+	}
+
+	configByReference := map[string]*xcconfig.Config{}
+	loadConfigurationList := func(list *ConfigurationList) {
+		for i := range list.BuildConfigurations {
+			buildConfiguration := &list.BuildConfigurations[i]
+			reference := buildConfiguration.BaseConfigurationReference
+			if reference == "" {
+				continue
+			}
+
+			config, ok := configByReference[reference]
+			if !ok {
+				config = p.openBaseConfiguration(reference, paths)
+				configByReference[reference] = config
+			}
+			buildConfiguration.BaseConfiguration = config
+		}
+	}
+
+	var loadTarget func(target *Target)
+	loadTarget = func(target *Target) {
+		loadConfigurationList(&target.BuildConfigurationList)
+		for i := range target.Dependencies {
+			loadTarget(&target.Dependencies[i].Target)
+		}
+	}
+
+	loadConfigurationList(&p.Proj.BuildConfigurationList)
+	for i := range p.Proj.Targets {
+		loadTarget(&p.Proj.Targets[i])
+	}
+}
+
+func (p XcodeProj) openBaseConfiguration(fileReferenceID string, paths SourceTreePaths) *xcconfig.Config {
+	element, ok := p.Proj.Element(fileReferenceID)
+	if !ok {
+		log.Warnf("base configuration (%s) not found in the project's file tree", fileReferenceID)
+		return nil
+	}
+
+	pth, err := element.AbsPath(paths)
+	if err != nil {
+		log.Warnf("failed to resolve base configuration (%s) path: %s", fileReferenceID, err)
+		return nil
+	}
+
+	if exist, err := pathutil.IsPathExists(pth); err != nil {
+		log.Warnf("failed to check if base configuration exists: %s", err)
+		return nil
+	} else if !exist {
+		log.Debugf("base configuration does not exist: %s", pth)
+		return nil
+	}
+
+	config, err := xcconfig.Open(pth)
+	if err != nil {
+		log.Warnf("failed to parse base configuration (%s): %s", pth, err)
+		return nil
+	}
+	return &config
+}
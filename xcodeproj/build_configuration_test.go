@@ -1,6 +1,7 @@
 package xcodeproj
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/bitrise-io/go-plist"
@@ -132,5 +133,35 @@ const expectedBuildConfiguration = `{
 		"MTL_ENABLE_DEBUG_INFO": "YES",
 		"ONLY_ACTIVE_ARCH": "YES",
 		"SDKROOT": "iphoneos"
-	}
+	},
+	"BaseConfigurationReference": "",
+	"BaseConfiguration": null
 }`
+
+func TestXcodeProj_BaseConfigurations(t *testing.T) {
+	pth := createProjectInTmpDir(t, "BaseConfig", pbxprojWithBaseConfigurations, map[string]string{
+		"Configs/App.xcconfig": `PRODUCT_BUNDLE_IDENTIFIER = io.bitrise.App
+`,
+	})
+
+	project, err := Open(pth)
+	require.NoError(t, err)
+
+	// Project.xcconfig does not exist
+	for _, buildConfiguration := range project.Proj.BuildConfigurationList.BuildConfigurations {
+		require.Equal(t, "13A1F110258B7E5000A6A4B1", buildConfiguration.BaseConfigurationReference)
+		require.Nil(t, buildConfiguration.BaseConfiguration)
+	}
+
+	target, ok := project.Proj.TargetByName("App")
+	require.True(t, ok)
+	for _, buildConfiguration := range target.BuildConfigurationList.BuildConfigurations {
+		require.Equal(t, "13A1F111258B7E5000A6A4B1", buildConfiguration.BaseConfigurationReference)
+		require.NotNil(t, buildConfiguration.BaseConfiguration)
+		require.Equal(t, filepath.Join(filepath.Dir(pth), "Configs", "App.xcconfig"), buildConfiguration.BaseConfiguration.Path)
+
+		bundleID, ok := buildConfiguration.BaseConfiguration.Value("PRODUCT_BUNDLE_IDENTIFIER")
+		require.True(t, ok)
+		require.Equal(t, "io.bitrise.App", bundleID)
+	}
+}
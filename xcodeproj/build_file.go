@@ -0,0 +1,56 @@
+package xcodeproj
+
+import "github.com/bitrise-io/xcode-project/serialized"
+
+// BuildFile represents a PBXBuildFile element, an entry of a build phase
+// 47C11A4A21FF63970084FD7F /* Assets.xcassets in Resources */ = {isa = PBXBuildFile; fileRef = 47C11A4921FF63970084FD7F /* Assets.xcassets */; };
+type BuildFile struct {
+	ID string
+	// FileRef is the ID of the referenced file tree element (PBXFileReference, PBXVariantGroup, ...), empty if not set.
+	FileRef string
+	// ProductRef is the ID of the referenced Swift package product (XCSwiftPackageProductDependency), empty if not set.
+	ProductRef string
+	Settings   serialized.Object
+}
+
+func newBuildFile(id string, raw serialized.Object) (BuildFile, error) {
+	settings, err := raw.Object("settings")
+	if err != nil && !serialized.IsKeyNotFoundError(err) {
+		return BuildFile{}, err
+	}
+
+	return BuildFile{
+		ID:         id,
+		FileRef:    optionalString(raw, "fileRef"),
+		ProductRef: optionalString(raw, "productRef"),
+		Settings:   settings,
+	}, nil
+}
+
+// Attributes returns the ATTRIBUTES setting of the build file, like: Public (headers), CodeSignOnCopy, RemoveHeadersOnCopy (embedded frameworks) or Weak (linked frameworks).
+func (f BuildFile) Attributes() []string {
+	attributes, err := f.Settings.StringSlice("ATTRIBUTES")
+	if err != nil {
+		return nil
+	}
+	return attributes
+}
+
+// HasAttribute reports whether the build file has the given attribute.
+func (f BuildFile) HasAttribute(attribute string) bool {
+	for _, a := range f.Attributes() {
+		if a == attribute {
+			return true
+		}
+	}
+	return false
+}
+
+// CompilerFlags returns the per-file compiler flags (COMPILER_FLAGS setting) of the build file.
+func (f BuildFile) CompilerFlags() string {
+	flags, err := f.Settings.String("COMPILER_FLAGS")
+	if err != nil {
+		return ""
+	}
+	return flags
+}
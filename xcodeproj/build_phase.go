@@ -0,0 +1,163 @@
+package xcodeproj
+
+import (
+	"strconv"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+)
+
+// BuildPhaseType ...
+type BuildPhaseType string
+
+// BuildPhaseTypes
+const (
+	SourcesBuildPhaseType     BuildPhaseType = "PBXSourcesBuildPhase"
+	FrameworksBuildPhaseType  BuildPhaseType = "PBXFrameworksBuildPhase"
+	ResourcesBuildPhaseType   BuildPhaseType = "PBXResourcesBuildPhase"
+	HeadersBuildPhaseType     BuildPhaseType = "PBXHeadersBuildPhase"
+	CopyFilesBuildPhaseType   BuildPhaseType = "PBXCopyFilesBuildPhase"
+	ShellScriptBuildPhaseType BuildPhaseType = "PBXShellScriptBuildPhase"
+	RezBuildPhaseType         BuildPhaseType = "PBXRezBuildPhase"
+)
+
+// DstSubfolderSpec is the destination of a PBXCopyFilesBuildPhase.
+type DstSubfolderSpec int
+
+// DstSubfolderSpecs
+const (
+	AbsolutePathDstSubfolderSpec      DstSubfolderSpec = 0
+	WrapperDstSubfolderSpec           DstSubfolderSpec = 1
+	ExecutablesDstSubfolderSpec       DstSubfolderSpec = 6
+	ResourcesDstSubfolderSpec         DstSubfolderSpec = 7
+	FrameworksDstSubfolderSpec        DstSubfolderSpec = 10
+	SharedFrameworksDstSubfolderSpec  DstSubfolderSpec = 11
+	SharedSupportDstSubfolderSpec     DstSubfolderSpec = 12
+	PlugInsDstSubfolderSpec           DstSubfolderSpec = 13
+	JavaResourcesDstSubfolderSpec     DstSubfolderSpec = 15
+	ProductsDirectoryDstSubfolderSpec DstSubfolderSpec = 16
+)
+
+// BuildPhase represents a build phase element of a target (PBXSourcesBuildPhase, PBXShellScriptBuildPhase, ...).
+// The type specific fields are only set for the given build phase type.
+type BuildPhase struct {
+	Type  BuildPhaseType
+	ID    string
+	Name  string
+	Files []BuildFile
+	// RunOnlyForDeploymentPostprocessing is true if the phase runs only when installing (archiving).
+	RunOnlyForDeploymentPostprocessing bool
+
+	// PBXCopyFilesBuildPhase
+	DstSubfolderSpec DstSubfolderSpec
+	DstPath          string
+
+	// PBXShellScriptBuildPhase
+	ShellPath           string
+	ShellScript         string
+	InputPaths          []string
+	OutputPaths         []string
+	InputFileListPaths  []string
+	OutputFileListPaths []string
+	ShowEnvVarsInLog    bool
+	AlwaysOutOfDate     bool
+}
+
+// DisplayName returns the name of the build phase, or the default name of its type if the phase has no name.
+func (p BuildPhase) DisplayName() string {
+	if p.Name != "" {
+		return p.Name
+	}
+
+	switch p.Type {
+	case SourcesBuildPhaseType:
+		return "Sources"
+	case FrameworksBuildPhaseType:
+		return "Frameworks"
+	case ResourcesBuildPhaseType:
+		return "Resources"
+	case HeadersBuildPhaseType:
+		return "Headers"
+	case CopyFilesBuildPhaseType:
+		return "CopyFiles"
+	case ShellScriptBuildPhaseType:
+		return "ShellScript"
+	case RezBuildPhaseType:
+		return "Rez"
+	default:
+		return string(p.Type)
+	}
+}
+
+func parseBuildPhase(id string, objects serialized.Object) (BuildPhase, error) {
+	raw, err := objects.Object(id)
+	if err != nil {
+		return BuildPhase{}, err
+	}
+
+	isa, err := raw.String("isa")
+	if err != nil {
+		return BuildPhase{}, err
+	}
+
+	fileIDs, err := raw.StringSlice("files")
+	if err != nil && !serialized.IsKeyNotFoundError(err) {
+		return BuildPhase{}, err
+	}
+
+	var files []BuildFile
+	for _, fileID := range fileIDs {
+		rawFile, err := objects.Object(fileID)
+		if err != nil {
+			if serialized.IsKeyNotFoundError(err) {
+				continue
+			}
+			return BuildPhase{}, err
+		}
+
+		buildFile, err := newBuildFile(fileID, rawFile)
+		if err != nil {
+			return BuildPhase{}, err
+		}
+		files = append(files, buildFile)
+	}
+
+	buildPhase := BuildPhase{
+		Type:                               BuildPhaseType(isa),
+		ID:                                 id,
+		Name:                               optionalString(raw, "name"),
+		Files:                              files,
+		RunOnlyForDeploymentPostprocessing: optionalString(raw, "runOnlyForDeploymentPostprocessing") == "1",
+	}
+
+	switch buildPhase.Type {
+	case CopyFilesBuildPhaseType:
+		if spec := optionalString(raw, "dstSubfolderSpec"); spec != "" {
+			value, err := strconv.Atoi(spec)
+			if err != nil {
+				return BuildPhase{}, serialized.NewTypeCastError("dstSubfolderSpec", spec, 0)
+			}
+			buildPhase.DstSubfolderSpec = DstSubfolderSpec(value)
+		}
+		buildPhase.DstPath = optionalString(raw, "dstPath")
+	case ShellScriptBuildPhaseType:
+		buildPhase.ShellPath = optionalString(raw, "shellPath")
+		buildPhase.ShellScript = optionalString(raw, "shellScript")
+		buildPhase.ShowEnvVarsInLog = optionalString(raw, "showEnvVarsInLog") != "0"
+		buildPhase.AlwaysOutOfDate = optionalString(raw, "alwaysOutOfDate") == "1"
+
+		for key, value := range map[string]*[]string{
+			"inputPaths":          &buildPhase.InputPaths,
+			"outputPaths":         &buildPhase.OutputPaths,
+			"inputFileListPaths":  &buildPhase.InputFileListPaths,
+			"outputFileListPaths": &buildPhase.OutputFileListPaths,
+		} {
+			paths, err := raw.StringSlice(key)
+			if err != nil && !serialized.IsKeyNotFoundError(err) {
+				return BuildPhase{}, err
+			}
+			*value = paths
+		}
+	}
+
+	return buildPhase, nil
+}
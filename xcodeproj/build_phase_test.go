@@ -0,0 +1,135 @@
+package xcodeproj
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/go-plist"
+	"github.com/bitrise-io/xcode-project/serialized"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBuildPhase(t *testing.T) {
+	var raw serialized.Object
+	_, err := plist.Unmarshal([]byte(rawBuildPhases), &raw)
+	require.NoError(t, err)
+
+	t.Log("PBXCopyFilesBuildPhase")
+	{
+		buildPhase, err := parseBuildPhase("13E76E561F4AC94F0028096E", raw)
+		require.NoError(t, err)
+		require.Equal(t, BuildPhase{
+			Type: CopyFilesBuildPhaseType,
+			ID:   "13E76E561F4AC94F0028096E",
+			Name: "Embed Frameworks",
+			Files: []BuildFile{
+				{
+					ID:       "13E76E541F4AC94F0028096E",
+					FileRef:  "13E76E471F4AC94F0028096E",
+					Settings: serialized.Object{"ATTRIBUTES": []interface{}{"CodeSignOnCopy", "RemoveHeadersOnCopy"}},
+				},
+			},
+			DstSubfolderSpec: FrameworksDstSubfolderSpec,
+		}, buildPhase)
+		require.Equal(t, []string{"CodeSignOnCopy", "RemoveHeadersOnCopy"}, buildPhase.Files[0].Attributes())
+		require.True(t, buildPhase.Files[0].HasAttribute("CodeSignOnCopy"))
+		require.False(t, buildPhase.Files[0].HasAttribute("Weak"))
+	}
+
+	t.Log("PBXShellScriptBuildPhase")
+	{
+		buildPhase, err := parseBuildPhase("13E76E9A1F4AC9800028096E", raw)
+		require.NoError(t, err)
+		require.Equal(t, BuildPhase{
+			Type:                "PBXShellScriptBuildPhase",
+			ID:                  "13E76E9A1F4AC9800028096E",
+			Name:                "SwiftLint",
+			ShellPath:           "/bin/sh",
+			ShellScript:         "if which swiftlint >/dev/null; then\n  swiftlint\nfi\n",
+			InputPaths:          []string{"$(SRCROOT)/.swiftlint.yml"},
+			OutputPaths:         []string{},
+			InputFileListPaths:  []string{"$(SRCROOT)/Sources.xcfilelist"},
+			OutputFileListPaths: nil,
+			ShowEnvVarsInLog:    false,
+			AlwaysOutOfDate:     true,
+		}, buildPhase)
+	}
+
+	t.Log("PBXSourcesBuildPhase")
+	{
+		buildPhase, err := parseBuildPhase("13E76E0A1F4AC90A0028096E", raw)
+		require.NoError(t, err)
+		require.Equal(t, "Sources", buildPhase.DisplayName())
+		require.Equal(t, 2, len(buildPhase.Files))
+		require.Equal(t, "-fno-objc-arc", buildPhase.Files[0].CompilerFlags())
+		require.Equal(t, "", buildPhase.Files[1].CompilerFlags())
+		require.Equal(t, "", buildPhase.Files[1].FileRef)
+	}
+}
+
+const rawBuildPhases = `{
+	13E76E0A1F4AC90A0028096E /* Sources */ = {
+		isa = PBXSourcesBuildPhase;
+		buildActionMask = 2147483647;
+		files = (
+			13E76E131F4AC90A0028096E /* Legacy.m in Sources */,
+			13E76E141F4AC90A0028096E /* (null) in Sources */,
+			13E76E151F4AC90A0028096E /* missing */,
+		);
+		runOnlyForDeploymentPostprocessing = 0;
+	};
+
+	13E76E131F4AC90A0028096E /* Legacy.m in Sources */ = {isa = PBXBuildFile; fileRef = 13E76E121F4AC90A0028096E /* Legacy.m */; settings = {COMPILER_FLAGS = "-fno-objc-arc"; }; };
+	13E76E141F4AC90A0028096E /* (null) in Sources */ = {isa = PBXBuildFile; };
+
+	13E76E561F4AC94F0028096E /* Embed Frameworks */ = {
+		isa = PBXCopyFilesBuildPhase;
+		buildActionMask = 2147483647;
+		dstPath = "";
+		dstSubfolderSpec = 10;
+		files = (
+			13E76E541F4AC94F0028096E /* Extension.framework in Embed Frameworks */,
+		);
+		name = "Embed Frameworks";
+		runOnlyForDeploymentPostprocessing = 0;
+	};
+
+	13E76E541F4AC94F0028096E /* Extension.framework in Embed Frameworks */ = {isa = PBXBuildFile; fileRef = 13E76E471F4AC94F0028096E /* Extension.framework */; settings = {ATTRIBUTES = (CodeSignOnCopy, RemoveHeadersOnCopy, ); }; };
+
+	13E76E9A1F4AC9800028096E /* SwiftLint */ = {
+		isa = PBXShellScriptBuildPhase;
+		alwaysOutOfDate = 1;
+		buildActionMask = 2147483647;
+		files = (
+		);
+		inputFileListPaths = (
+			"$(SRCROOT)/Sources.xcfilelist",
+		);
+		inputPaths = (
+			"$(SRCROOT)/.swiftlint.yml",
+		);
+		name = SwiftLint;
+		outputPaths = (
+		);
+		runOnlyForDeploymentPostprocessing = 0;
+		shellPath = /bin/sh;
+		shellScript = "if which swiftlint >/dev/null; then\n  swiftlint\nfi\n";
+		showEnvVarsInLog = 0;
+	};
+}`
+
+func TestTarget_BuildPhasesOfType(t *testing.T) {
+	var raw serialized.Object
+	_, err := plist.Unmarshal([]byte(rawProj), &raw)
+	require.NoError(t, err)
+
+	target, err := parseTarget("BA3CBE7419F7A93800CED4D5", raw)
+	require.NoError(t, err)
+	require.Equal(t, 3, len(target.BuildPhases))
+
+	resourcesBuildPhases := target.BuildPhasesOfType(ResourcesBuildPhaseType)
+	require.Equal(t, 1, len(resourcesBuildPhases))
+	require.Equal(t, "BA3CBE7319F7A93800CED4D5", resourcesBuildPhases[0].ID)
+	require.Equal(t, 3, len(resourcesBuildPhases[0].Files))
+
+	require.Equal(t, 0, len(target.BuildPhasesOfType(ShellScriptBuildPhaseType)))
+}
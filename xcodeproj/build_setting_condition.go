@@ -0,0 +1,147 @@
+package xcodeproj
+
+import (
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+)
+
+// BuildSettingConditions describes the build context in which conditional build settings
+// (like `CODE_SIGN_IDENTITY[sdk=iphoneos*]`) are evaluated.
+// Empty fields do not match any condition of the given kind.
+type BuildSettingConditions struct {
+	// SDK is matched against the sdk conditions, like: iphoneos14.2, iphonesimulator
+	SDK string
+	// Arch is matched against the arch conditions, like: arm64, x86_64
+	Arch string
+	// Variant is matched against the variant conditions, like: normal, debug
+	Variant string
+	// Configuration is matched against the config conditions, like: Release
+	Configuration string
+}
+
+func (c BuildSettingConditions) value(condition string) string {
+	switch condition {
+	case "sdk":
+		return c.SDK
+	case "arch":
+		return c.Arch
+	case "variant":
+		return c.Variant
+	case "config":
+		return c.Configuration
+	default:
+		return ""
+	}
+}
+
+// Match reports whether all the conditions of the build setting key (`KEY[sdk=iphoneos*][arch=arm64]`)
+// are fulfilled in this context, condition values are glob patterns.
+// A key without conditions always matches.
+func (c BuildSettingConditions) Match(key string) bool {
+	_, conditions := SplitBuildSettingKey(key)
+	return c.match(conditions)
+}
+
+func (c BuildSettingConditions) match(conditions map[string]string) bool {
+	for condition, pattern := range conditions {
+		value := c.value(condition)
+		if value == "" {
+			return false
+		}
+		if ok, err := path.Match(pattern, value); err != nil || !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// SplitBuildSettingKey splits a (possibly conditional) build setting key into
+// the build setting name and its conditions: `CODE_SIGN_IDENTITY[sdk=iphoneos*]` => `CODE_SIGN_IDENTITY`, {sdk: iphoneos*}.
+func SplitBuildSettingKey(key string) (string, map[string]string) {
+	idx := strings.Index(key, "[")
+	if idx == -1 {
+		return key, nil
+	}
+
+	name := key[:idx]
+	conditions := map[string]string{}
+	for _, part := range strings.Split(key[idx:], "[") {
+		part = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(part), "]"))
+		if part == "" {
+			continue
+		}
+
+		for _, condition := range strings.Split(part, ",") {
+			split := strings.SplitN(condition, "=", 2)
+			if len(split) != 2 {
+				continue
+			}
+			conditions[strings.TrimSpace(split[0])] = strings.TrimSpace(split[1])
+		}
+	}
+
+	return name, conditions
+}
+
+// ConditionalBuildSettingValue returns the value of the build setting named name, which Xcode would use
+// in the given context: the most specific matching conditional variant (the one with the most conditions),
+// or the unconditional value if no conditional variant matches.
+func ConditionalBuildSettingValue(buildSettings serialized.Object, name string, conditions BuildSettingConditions) (interface{}, bool) {
+	var keys []string
+	for key := range buildSettings {
+		if key == name || strings.HasPrefix(key, name+"[") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	bestKey := ""
+	bestConditionCount := -1
+	for _, key := range keys {
+		keyName, keyConditions := SplitBuildSettingKey(key)
+		if keyName != name || !conditions.match(keyConditions) {
+			continue
+		}
+
+		if len(keyConditions) > bestConditionCount {
+			bestKey = key
+			bestConditionCount = len(keyConditions)
+		}
+	}
+
+	if bestConditionCount == -1 {
+		return nil, false
+	}
+	return buildSettings[bestKey], true
+}
+
+// BuildSetting returns the value of the build setting named name, which Xcode would use for this
+// configuration in the given context, see ConditionalBuildSettingValue.
+// If the Configuration of the context is empty, the configuration's name is used.
+func (c BuildConfiguration) BuildSetting(name string, conditions BuildSettingConditions) (interface{}, bool) {
+	if conditions.Configuration == "" {
+		conditions.Configuration = c.Name
+	}
+	return ConditionalBuildSettingValue(c.BuildSettings, name, conditions)
+}
+
+// applyBuildSettingConditions returns the build setting values of the layer which apply in the given context,
+// keyed by the unconditional build setting names.
+func applyBuildSettingConditions(layer serialized.Object, conditions BuildSettingConditions) serialized.Object {
+	names := map[string]bool{}
+	for key := range layer {
+		name, _ := SplitBuildSettingKey(key)
+		names[name] = true
+	}
+
+	applied := serialized.Object{}
+	for name := range names {
+		if value, ok := ConditionalBuildSettingValue(layer, name, conditions); ok {
+			applied[name] = value
+		}
+	}
+	return applied
+}
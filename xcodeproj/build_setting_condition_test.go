@@ -0,0 +1,143 @@
+package xcodeproj
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitBuildSettingKey(t *testing.T) {
+	tests := []struct {
+		key            string
+		wantName       string
+		wantConditions map[string]string
+	}{
+		{key: "CODE_SIGN_IDENTITY", wantName: "CODE_SIGN_IDENTITY", wantConditions: nil},
+		{key: "CODE_SIGN_IDENTITY[sdk=iphoneos*]", wantName: "CODE_SIGN_IDENTITY", wantConditions: map[string]string{"sdk": "iphoneos*"}},
+		{key: "OTHER_LDFLAGS[sdk=iphoneos*][arch=arm64]", wantName: "OTHER_LDFLAGS", wantConditions: map[string]string{"sdk": "iphoneos*", "arch": "arm64"}},
+		{key: "OTHER_LDFLAGS[sdk=iphoneos*, arch=arm64]", wantName: "OTHER_LDFLAGS", wantConditions: map[string]string{"sdk": "iphoneos*", "arch": "arm64"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			name, conditions := SplitBuildSettingKey(tt.key)
+			require.Equal(t, tt.wantName, name)
+			require.Equal(t, tt.wantConditions, conditions)
+		})
+	}
+}
+
+func TestConditionalBuildSettingValue(t *testing.T) {
+	buildSettings := serialized.Object{
+		"CODE_SIGN_IDENTITY":                             "Apple Development",
+		"CODE_SIGN_IDENTITY[sdk=iphoneos*]":              "iPhone Developer",
+		"CODE_SIGN_IDENTITY[sdk=iphoneos*][config=Rel*]": "iPhone Distribution",
+		"CODE_SIGN_IDENTITY[sdk=macosx*]":                "-",
+		"EXCLUDED_ARCHS[sdk=iphonesimulator*]":           "arm64",
+		"OTHER_LDFLAGS[variant=debug]":                   "-lz",
+	}
+
+	tests := []struct {
+		name       string
+		key        string
+		conditions BuildSettingConditions
+		want       interface{}
+		wantOK     bool
+	}{
+		{name: "no context", key: "CODE_SIGN_IDENTITY", conditions: BuildSettingConditions{}, want: "Apple Development", wantOK: true},
+		{name: "simulator", key: "CODE_SIGN_IDENTITY", conditions: BuildSettingConditions{SDK: "iphonesimulator14.2"}, want: "Apple Development", wantOK: true},
+		{name: "device", key: "CODE_SIGN_IDENTITY", conditions: BuildSettingConditions{SDK: "iphoneos14.2", Configuration: "Debug"}, want: "iPhone Developer", wantOK: true},
+		{name: "device release", key: "CODE_SIGN_IDENTITY", conditions: BuildSettingConditions{SDK: "iphoneos", Configuration: "Release"}, want: "iPhone Distribution", wantOK: true},
+		{name: "macOS", key: "CODE_SIGN_IDENTITY", conditions: BuildSettingConditions{SDK: "macosx11.1", Configuration: "Release"}, want: "-", wantOK: true},
+		{name: "only conditional variant matches", key: "EXCLUDED_ARCHS", conditions: BuildSettingConditions{SDK: "iphonesimulator"}, want: "arm64", wantOK: true},
+		{name: "no variant matches", key: "EXCLUDED_ARCHS", conditions: BuildSettingConditions{SDK: "iphoneos"}, want: nil, wantOK: false},
+		{name: "variant", key: "OTHER_LDFLAGS", conditions: BuildSettingConditions{Variant: "debug"}, want: "-lz", wantOK: true},
+		{name: "not defined", key: "CODE_SIGN", conditions: BuildSettingConditions{SDK: "iphoneos"}, want: nil, wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ConditionalBuildSettingValue(buildSettings, tt.key, tt.conditions)
+			require.Equal(t, tt.wantOK, ok)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBuildConfiguration_BuildSetting(t *testing.T) {
+	configuration := BuildConfiguration{
+		Name: "Release",
+		BuildSettings: serialized.Object{
+			"CODE_SIGN_IDENTITY[sdk=iphoneos*]":                 "iPhone Developer",
+			"CODE_SIGN_IDENTITY[sdk=iphoneos*][config=Release]": "iPhone Distribution",
+		},
+	}
+
+	got, ok := configuration.BuildSetting("CODE_SIGN_IDENTITY", BuildSettingConditions{SDK: "iphoneos"})
+	require.True(t, ok)
+	require.Equal(t, "iPhone Distribution", got)
+
+	got, ok = configuration.BuildSetting("CODE_SIGN_IDENTITY", BuildSettingConditions{SDK: "iphoneos", Configuration: "Debug"})
+	require.True(t, ok)
+	require.Equal(t, "iPhone Developer", got)
+}
+
+func TestXcodeProj_ResolveTargetBuildSettingsForConditions(t *testing.T) {
+	pth := createProjectInTmpDir(t, "BaseConfig", pbxprojWithBaseConfigurations, map[string]string{
+		"Configs/Project.xcconfig": `CODE_SIGN_IDENTITY = Apple Development
+OTHER_LDFLAGS = -ObjC
+OTHER_LDFLAGS[sdk=iphonesimulator*] = -ObjC -framework XCTest
+`,
+		"Configs/App.xcconfig": `OTHER_LDFLAGS[arch=arm64] = $(inherited) -lc++
+`,
+	})
+
+	project, err := Open(pth)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		configuration string
+		conditions    BuildSettingConditions
+		want          map[string]string
+	}{
+		{
+			name:          "SDK from SDKROOT",
+			configuration: "Release",
+			conditions:    BuildSettingConditions{},
+			want: map[string]string{
+				"CODE_SIGN_IDENTITY": "iPhone Distribution",
+				"OTHER_LDFLAGS":      "-ObjC",
+			},
+		},
+		{
+			name:          "device debug build",
+			configuration: "Debug",
+			conditions:    BuildSettingConditions{SDK: "iphoneos14.2", Arch: "arm64"},
+			want: map[string]string{
+				"CODE_SIGN_IDENTITY": "iPhone Developer",
+				"OTHER_LDFLAGS":      "-ObjC -lc++ -lz",
+			},
+		},
+		{
+			name:          "simulator build",
+			configuration: "Release",
+			conditions:    BuildSettingConditions{SDK: "iphonesimulator14.2", Arch: "x86_64"},
+			want: map[string]string{
+				"CODE_SIGN_IDENTITY": "Apple Development",
+				"OTHER_LDFLAGS":      "-ObjC -framework XCTest",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buildSettings, err := project.ResolveTargetBuildSettingsForConditions("App", tt.configuration, tt.conditions)
+			require.NoError(t, err)
+
+			for key, want := range tt.want {
+				got, err := buildSettings.String(key)
+				require.NoError(t, err, key)
+				require.Equal(t, want, got, key)
+			}
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package xcodeproj
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/bitrise-io/go-utils/sliceutil"
+	"github.com/bitrise-io/xcode-project/serialized"
+)
+
+// InheritedBuildSettingValue refers to the value of the build setting on the lower level (like the project level for a target).
+const InheritedBuildSettingValue = "$(inherited)"
+
+// BuildSettingValue is the value of a build setting as Xcode writes it:
+// a string (`SWIFT_VERSION = 5.0;`) or a list of strings (`OTHER_LDFLAGS = ("$(inherited)", "-ObjC");`).
+type BuildSettingValue struct {
+	IsList bool
+	String string
+	List   []string
+}
+
+// NewStringBuildSettingValue ...
+func NewStringBuildSettingValue(value string) BuildSettingValue {
+	return BuildSettingValue{String: value}
+}
+
+// NewListBuildSettingValue ...
+func NewListBuildSettingValue(values ...string) BuildSettingValue {
+	return BuildSettingValue{IsList: true, List: append([]string{}, values...)}
+}
+
+func parseBuildSettingValue(key string, raw interface{}) (BuildSettingValue, error) {
+	switch raw := raw.(type) {
+	case string:
+		return NewStringBuildSettingValue(raw), nil
+	case []interface{}:
+		var values []string
+		for _, item := range raw {
+			value, ok := item.(string)
+			if !ok {
+				return BuildSettingValue{}, serialized.NewTypeCastError(key, raw, []string{})
+			}
+			values = append(values, value)
+		}
+		return NewListBuildSettingValue(values...), nil
+	default:
+		return BuildSettingValue{}, serialized.NewTypeCastError(key, raw, "")
+	}
+}
+
+func (v BuildSettingValue) raw() interface{} {
+	if !v.IsList {
+		return v.String
+	}
+
+	values := []interface{}{}
+	for _, value := range v.List {
+		values = append(values, value)
+	}
+	return values
+}
+
+// BuildSetting returns the value of the build setting defined on the given level:
+// on the target's configuration, or on the project's configuration if target is empty.
+// The key can be a conditional variant, like `CODE_SIGN_IDENTITY[sdk=iphoneos*]`. Values are not inherited or expanded.
+func (p XcodeProj) BuildSetting(target, configuration, key string) (BuildSettingValue, bool, error) {
+	if configuration == "" {
+		return BuildSettingValue{}, false, fmt.Errorf("build configuration is not set")
+	}
+
+	buildSettingsList, err := p.rawBuildSettingsOf(target, configuration)
+	if err != nil {
+		return BuildSettingValue{}, false, err
+	}
+
+	raw, ok := buildSettingsList[0][key]
+	if !ok {
+		return BuildSettingValue{}, false, nil
+	}
+
+	value, err := parseBuildSettingValue(key, raw)
+	if err != nil {
+		return BuildSettingValue{}, false, err
+	}
+	return value, true, nil
+}
+
+// SetBuildSetting sets the build setting on the target's configuration (on the project's configuration if target is empty),
+// on every configuration if configuration is empty.
+// The key can be a conditional variant, like `CODE_SIGN_IDENTITY[sdk=iphoneos*]`.
+func (p *XcodeProj) SetBuildSetting(target, configuration, key string, value BuildSettingValue) error {
+	if err := validateBuildSettingKey(key); err != nil {
+		return err
+	}
+
+	buildSettingsList, err := p.rawBuildSettingsOf(target, configuration)
+	if err != nil {
+		return err
+	}
+
+	for _, buildSettings := range buildSettingsList {
+		buildSettings[key] = value.raw()
+	}
+	return p.reloadProj()
+}
+
+// AppendBuildSetting appends the values to a list build setting (like OTHER_LDFLAGS or HEADER_SEARCH_PATHS),
+// values already in the list are not added again.
+// If the build setting is not set, it is created with `$(inherited)` as its first value, to keep the lower level's value.
+// A string build setting is converted to a list, its value is split into the list items (see splitBuildSettingString).
+func (p *XcodeProj) AppendBuildSetting(target, configuration, key string, values ...string) error {
+	if err := validateBuildSettingKey(key); err != nil {
+		return err
+	}
+
+	buildSettingsList, err := p.rawBuildSettingsOf(target, configuration)
+	if err != nil {
+		return err
+	}
+
+	for _, buildSettings := range buildSettingsList {
+		var current BuildSettingValue
+		if raw, ok := buildSettings[key]; ok {
+			current, err = parseBuildSettingValue(key, raw)
+			if err != nil {
+				return err
+			}
+		}
+
+		var list []string
+		switch {
+		case current.IsList:
+			list = current.List
+		case current.String != "":
+			list = splitBuildSettingString(current.String)
+		default:
+			list = []string{InheritedBuildSettingValue}
+		}
+
+		for _, value := range values {
+			if !sliceutil.IsStringInSlice(value, list) {
+				list = append(list, value)
+			}
+		}
+
+		buildSettings[key] = NewListBuildSettingValue(list...).raw()
+	}
+	return p.reloadProj()
+}
+
+// splitBuildSettingString splits a string build setting into its items the way Xcode does for a list build setting:
+// by whitespace, keeping the quoted parts (like `"$(SRCROOT)/My Folder"`) and the escaped characters together.
+// The quotes and the escape characters are kept in the items.
+func splitBuildSettingString(value string) []string {
+	var items []string
+	var item strings.Builder
+	var quote rune
+	escaped := false
+	for _, r := range value {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case unicode.IsSpace(r):
+			if item.Len() > 0 {
+				items = append(items, item.String())
+				item.Reset()
+			}
+			continue
+		}
+		item.WriteRune(r)
+	}
+	if item.Len() > 0 {
+		items = append(items, item.String())
+	}
+	return items
+}
+
+// DeleteBuildSetting deletes the build setting from the target's configuration (from the project's configuration if target is empty),
+// from every configuration if configuration is empty.
+// Only the given key is deleted, conditional variants (like `CODE_SIGN_IDENTITY[sdk=iphoneos*]`) have to be deleted by their own key.
+func (p *XcodeProj) DeleteBuildSetting(target, configuration, key string) error {
+	buildSettingsList, err := p.rawBuildSettingsOf(target, configuration)
+	if err != nil {
+		return err
+	}
+
+	for _, buildSettings := range buildSettingsList {
+		delete(buildSettings, key)
+	}
+	return p.reloadProj()
+}
+
+// rawBuildSettingsOf returns the raw build settings of the target's (or the project's if target is empty) configuration,
+// of every configuration if configuration is empty.
+func (p XcodeProj) rawBuildSettingsOf(target, configuration string) ([]serialized.Object, error) {
+	configurationList := p.Proj.BuildConfigurationList
+	if target != "" {
+		t, ok := p.Proj.TargetByName(target)
+		if !ok {
+			return nil, fmt.Errorf("target not found: %s", target)
+		}
+		configurationList = t.BuildConfigurationList
+	}
+
+	objects, err := p.RawProj.Object("objects")
+	if err != nil {
+		return nil, fmt.Errorf("failed to access objects: %s", err)
+	}
+
+	var buildSettingsList []serialized.Object
+	for _, buildConfiguration := range configurationList.BuildConfigurations {
+		if configuration != "" && buildConfiguration.Name != configuration {
+			continue
+		}
+
+		buildSettings, err := rawBuildSettings(objects, buildConfiguration.ID)
+		if err != nil {
+			return nil, err
+		}
+		buildSettingsList = append(buildSettingsList, buildSettings)
+	}
+
+	if len(buildSettingsList) == 0 {
+		if configuration == "" {
+			return nil, fmt.Errorf("no build configuration found")
+		}
+		return nil, fmt.Errorf("build configuration not found: %s", configuration)
+	}
+	return buildSettingsList, nil
+}
+
+func validateBuildSettingKey(key string) error {
+	name, _ := SplitBuildSettingKey(key)
+	if name == "" {
+		return fmt.Errorf("invalid build setting key: %s", key)
+	}
+	return nil
+}
@@ -0,0 +1,115 @@
+package xcodeproj
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXcodeProj_EditBuildSettings(t *testing.T) {
+	pth := createProjectInTmpDir(t, "XcodeProj", testhelper.XcodeProjectTest, nil)
+	project, err := Open(pth)
+	require.NoError(t, err)
+
+	t.Log("get")
+	{
+		value, ok, err := project.BuildSetting("XcodeProj", "Debug", "SWIFT_VERSION")
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, NewStringBuildSettingValue("4.0"), value)
+
+		value, ok, err = project.BuildSetting("", "Debug", "CODE_SIGN_IDENTITY")
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, NewStringBuildSettingValue("iPhone Developer"), value)
+
+		_, ok, err = project.BuildSetting("XcodeProj", "Debug", "OTHER_LDFLAGS")
+		require.NoError(t, err)
+		require.False(t, ok)
+
+		_, _, err = project.BuildSetting("XcodeProj", "", "SWIFT_VERSION")
+		require.EqualError(t, err, "build configuration is not set")
+		_, _, err = project.BuildSetting("NotExisting", "Debug", "SWIFT_VERSION")
+		require.EqualError(t, err, "target not found: NotExisting")
+		_, _, err = project.BuildSetting("XcodeProj", "Staging", "SWIFT_VERSION")
+		require.EqualError(t, err, "build configuration not found: Staging")
+	}
+
+	t.Log("set for every configuration")
+	{
+		require.NoError(t, project.SetBuildSetting("XcodeProj", "", "SWIFT_VERSION", NewStringBuildSettingValue("5.0")))
+		require.NoError(t, project.SetBuildSetting("XcodeProj", "Release", "CODE_SIGN_IDENTITY[sdk=iphoneos*]", NewStringBuildSettingValue("iPhone Distribution")))
+
+		target, ok := project.Proj.TargetByName("XcodeProj")
+		require.True(t, ok)
+		for _, buildConfiguration := range target.BuildConfigurationList.BuildConfigurations {
+			require.Equal(t, "5.0", buildConfiguration.BuildSettings["SWIFT_VERSION"])
+		}
+
+		value, ok, err := project.BuildSetting("XcodeProj", "Release", "CODE_SIGN_IDENTITY[sdk=iphoneos*]")
+		require.NoError(t, err)
+		require.True(t, ok)
+		require.Equal(t, "iPhone Distribution", value.String)
+
+		require.EqualError(t, project.SetBuildSetting("XcodeProj", "", "[sdk=iphoneos*]", NewStringBuildSettingValue("")), "invalid build setting key: [sdk=iphoneos*]")
+	}
+
+	t.Log("append")
+	{
+		require.NoError(t, project.AppendBuildSetting("XcodeProj", "", "OTHER_LDFLAGS", "-ObjC"))
+		require.NoError(t, project.AppendBuildSetting("XcodeProj", "Debug", "OTHER_LDFLAGS", "-ObjC", "-lz"))
+
+		value, _, err := project.BuildSetting("XcodeProj", "Debug", "OTHER_LDFLAGS")
+		require.NoError(t, err)
+		require.Equal(t, NewListBuildSettingValue("$(inherited)", "-ObjC", "-lz"), value)
+
+		value, _, err = project.BuildSetting("XcodeProj", "Release", "OTHER_LDFLAGS")
+		require.NoError(t, err)
+		require.Equal(t, NewListBuildSettingValue("$(inherited)", "-ObjC"), value)
+
+		require.NoError(t, project.SetBuildSetting("", "Debug", "HEADER_SEARCH_PATHS", NewStringBuildSettingValue("Vendor/include")))
+		require.NoError(t, project.AppendBuildSetting("", "Debug", "HEADER_SEARCH_PATHS", "Generated"))
+
+		value, _, err = project.BuildSetting("", "Debug", "HEADER_SEARCH_PATHS")
+		require.NoError(t, err)
+		require.Equal(t, NewListBuildSettingValue("Vendor/include", "Generated"), value)
+
+		// the string value is split into items, the existing items are not duplicated
+		require.NoError(t, project.SetBuildSetting("", "Release", "OTHER_LDFLAGS", NewStringBuildSettingValue(`$(inherited) -ObjC "$(SRCROOT)/My Libs/libA.a"`)))
+		require.NoError(t, project.AppendBuildSetting("", "Release", "OTHER_LDFLAGS", "-ObjC", "-lz"))
+
+		value, _, err = project.BuildSetting("", "Release", "OTHER_LDFLAGS")
+		require.NoError(t, err)
+		require.Equal(t, NewListBuildSettingValue("$(inherited)", "-ObjC", `"$(SRCROOT)/My Libs/libA.a"`, "-lz"), value)
+	}
+
+	t.Log("delete")
+	{
+		require.NoError(t, project.DeleteBuildSetting("XcodeProj", "Release", "CODE_SIGN_IDENTITY[sdk=iphoneos*]"))
+		_, ok, err := project.BuildSetting("XcodeProj", "Release", "CODE_SIGN_IDENTITY[sdk=iphoneos*]")
+		require.NoError(t, err)
+		require.False(t, ok)
+	}
+
+	require.NoError(t, project.Save())
+
+	content, err := ioutil.ReadFile(filepath.Join(pth, "project.pbxproj"))
+	require.NoError(t, err)
+	require.Contains(t, string(content), "\t\t\t\tOTHER_LDFLAGS = (\n\t\t\t\t\t\"$(inherited)\",\n\t\t\t\t\t\"-ObjC\",\n\t\t\t\t\t\"-lz\",\n\t\t\t\t);\n")
+	require.Contains(t, string(content), "\t\t\t\tSWIFT_VERSION = 5.0;\n")
+
+	reopened, err := Open(pth)
+	require.NoError(t, err)
+	value, _, err := reopened.BuildSetting("XcodeProj", "Debug", "OTHER_LDFLAGS")
+	require.NoError(t, err)
+	require.Equal(t, NewListBuildSettingValue("$(inherited)", "-ObjC", "-lz"), value)
+}
+
+func Test_splitBuildSettingString(t *testing.T) {
+	require.Equal(t, []string{"$(inherited)", "-ObjC"}, splitBuildSettingString(" $(inherited)\t -ObjC "))
+	require.Equal(t, []string{"-framework", "A", `"$(SRCROOT)/My Libs"`, `'single quoted'`, `My\ Folder`}, splitBuildSettingString(`-framework A "$(SRCROOT)/My Libs" 'single quoted' My\ Folder`))
+	require.Equal(t, 0, len(splitBuildSettingString("  ")))
+}
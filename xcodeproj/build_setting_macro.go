@@ -0,0 +1,325 @@
+package xcodeproj
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+)
+
+// ReferenceCycleError is returned if build settings reference each other in a cycle.
+type ReferenceCycleError struct {
+	Keys []string
+}
+
+// Error implements the error interface
+func (e ReferenceCycleError) Error() string {
+	return fmt.Sprintf("build setting reference cycle found: %s", strings.Join(e.Keys, " -> "))
+}
+
+// IsReferenceCycleError reports whatever the given error is an instance of ReferenceCycleError
+func IsReferenceCycleError(err error) bool {
+	if err == nil {
+		return false
+	}
+	_, ok := err.(ReferenceCycleError)
+	return ok
+}
+
+// Expand evaluates the build setting macros in value using buildSettings.
+//
+// Supported reference formats: `$(KEY)`, `${KEY}` and `$KEY`, references can be nested (`$(FOO_$(CONFIGURATION))`)
+// and can be followed by operators: `$(PRODUCT_NAME:rfc1034identifier:lower)`.
+// Supported operators: rfc1034identifier, c99extidentifier, identifier, lower, upper, default=VALUE,
+// base, dir, file, suffix, standardizepath and quote.
+// References to undefined build settings are evaluated to an empty string, like Xcode does.
+func Expand(value string, buildSettings serialized.Object) (string, error) {
+	return newMacroExpander(buildSettings).expand(value)
+}
+
+// ExpandStrict works like Expand, but returns an error if value references an undefined build setting,
+// unless the reference has a default operator (like: `$(SUFFIX:default=beta)`).
+func ExpandStrict(value string, buildSettings serialized.Object) (string, error) {
+	e := newMacroExpander(buildSettings)
+	e.strict = true
+	return e.expand(value)
+}
+
+// ExpandBuildSetting returns the evaluated value of the build setting named key.
+func ExpandBuildSetting(key string, buildSettings serialized.Object) (string, error) {
+	return newMacroExpander(buildSettings).setting(key)
+}
+
+type macroExpander struct {
+	buildSettings serialized.Object
+	// strict makes the references to undefined build settings fail
+	strict    bool
+	evaluated map[string]string
+	stack     []string
+}
+
+func newMacroExpander(buildSettings serialized.Object) *macroExpander {
+	return &macroExpander{
+		buildSettings: buildSettings,
+		evaluated:     map[string]string{},
+	}
+}
+
+func (e *macroExpander) setting(key string) (string, error) {
+	return e.lookup(key, !e.strict)
+}
+
+// lookup returns the evaluated value of the build setting,
+// undefined build settings are evaluated to an empty string if allowUndefined is set.
+func (e *macroExpander) lookup(key string, allowUndefined bool) (string, error) {
+	if value, ok := e.evaluated[key]; ok {
+		return value, nil
+	}
+
+	for i, k := range e.stack {
+		if k == key {
+			keys := append([]string{}, e.stack[i:]...)
+			return "", ReferenceCycleError{Keys: append(keys, key)}
+		}
+	}
+
+	raw, ok := e.buildSettings[key]
+	if !ok {
+		if allowUndefined {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to find env in build settings: %s", key)
+	}
+
+	e.stack = append(e.stack, key)
+	value, err := e.expand(buildSettingString(raw))
+	e.stack = e.stack[:len(e.stack)-1]
+	if err != nil {
+		return "", err
+	}
+
+	e.evaluated[key] = value
+	return value, nil
+}
+
+func (e *macroExpander) expand(value string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(value); {
+		if value[i] != '$' || i+1 == len(value) {
+			b.WriteByte(value[i])
+			i++
+			continue
+		}
+
+		switch next := value[i+1]; {
+		case next == '$':
+			b.WriteByte('$')
+			i += 2
+		case next == '(' || next == '{':
+			end := closingBracketIndex(value, i+1)
+			if end == -1 {
+				b.WriteString(value[i:])
+				return b.String(), nil
+			}
+
+			evaluated, err := e.evaluate(value[i+2 : end])
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(evaluated)
+			i = end + 1
+		case isIdentifierStart(next):
+			// $KEY format: the reference ends with the longest defined build setting name (like: $PRODUCT_NAMEsuffix)
+			end := i + 1
+			for end < len(value) && isIdentifierChar(value[end]) {
+				end++
+			}
+			name := value[i+1 : end]
+
+			keyLen := len(name)
+			for keyLen > 0 {
+				if _, ok := e.buildSettings[name[:keyLen]]; ok {
+					break
+				}
+				keyLen--
+			}
+			if keyLen == 0 {
+				keyLen = len(name)
+			}
+
+			evaluated, err := e.setting(name[:keyLen])
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(evaluated)
+			i += 1 + keyLen
+		default:
+			b.WriteByte('$')
+			i++
+		}
+	}
+	return b.String(), nil
+}
+
+// evaluate evaluates the content of a `$(...)` reference: the (possibly nested) build setting name
+// followed by the colon separated operators.
+func (e *macroExpander) evaluate(reference string) (string, error) {
+	parts := splitOperators(reference)
+
+	name, err := e.expand(parts[0])
+	if err != nil {
+		return "", err
+	}
+
+	allowUndefined := !e.strict
+	for _, operator := range parts[1:] {
+		if strings.HasPrefix(operator, "default=") {
+			allowUndefined = true
+		}
+	}
+
+	var value string
+	if name != "inherited" {
+		value, err = e.lookup(name, allowUndefined)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	for _, operator := range parts[1:] {
+		var argument string
+		if split := strings.SplitN(operator, "=", 2); len(split) == 2 {
+			operator = split[0]
+			argument, err = e.expand(split[1])
+			if err != nil {
+				return "", err
+			}
+		}
+
+		value, err = applyBuildSettingOperator(operator, argument, value)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	return value, nil
+}
+
+func applyBuildSettingOperator(operator, argument, value string) (string, error) {
+	switch operator {
+	case "rfc1034identifier":
+		return strings.Map(func(c rune) rune {
+			if isAlphanumeric(c) || c == '-' || c == '.' {
+				return c
+			}
+			return '-'
+		}, value), nil
+	case "c99extidentifier", "identifier":
+		identifier := strings.Map(func(c rune) rune {
+			if isAlphanumeric(c) || c == '_' {
+				return c
+			}
+			return '_'
+		}, value)
+		if identifier != "" && identifier[0] >= '0' && identifier[0] <= '9' {
+			identifier = "_" + identifier
+		}
+		return identifier, nil
+	case "lower":
+		return strings.ToLower(value), nil
+	case "upper":
+		return strings.ToUpper(value), nil
+	case "default":
+		if value == "" {
+			return argument, nil
+		}
+		return value, nil
+	case "base":
+		base := path.Base(value)
+		return strings.TrimSuffix(base, path.Ext(base)), nil
+	case "dir":
+		return value[:strings.LastIndex(value, "/")+1], nil
+	case "file":
+		return path.Base(value), nil
+	case "suffix":
+		return path.Ext(value), nil
+	case "standardizepath":
+		if value == "" {
+			return "", nil
+		}
+		return path.Clean(value), nil
+	case "quote":
+		return quoteBuildSettingValue(value), nil
+	default:
+		return "", fmt.Errorf("unknown build setting operator: %s", operator)
+	}
+}
+
+func quoteBuildSettingValue(value string) string {
+	var b strings.Builder
+	for _, c := range value {
+		if strings.ContainsRune(" \t\\\"'", c) {
+			b.WriteRune('\\')
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+// closingBracketIndex returns the index of the bracket closing the one at the given start index,
+// or -1 if the bracket is not closed.
+func closingBracketIndex(value string, start int) int {
+	open := value[start]
+	closing := byte(')')
+	if open == '{' {
+		closing = '}'
+	}
+
+	depth := 0
+	for i := start; i < len(value); i++ {
+		switch value[i] {
+		case open:
+			depth++
+		case closing:
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// splitOperators splits the reference at the colons which are not part of a nested reference.
+func splitOperators(reference string) []string {
+	var parts []string
+	depth := 0
+	start := 0
+	for i := 0; i < len(reference); i++ {
+		switch reference[i] {
+		case '(', '{':
+			depth++
+		case ')', '}':
+			depth--
+		case ':':
+			if depth == 0 {
+				parts = append(parts, reference[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, reference[start:])
+}
+
+func isAlphanumeric(c rune) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+func isIdentifierStart(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || c == '_'
+}
+
+func isIdentifierChar(c byte) bool {
+	return isIdentifierStart(c) || (c >= '0' && c <= '9')
+}
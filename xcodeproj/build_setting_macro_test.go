@@ -0,0 +1,113 @@
+package xcodeproj
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpand(t *testing.T) {
+	buildSettings := serialized.Object{
+		"PRODUCT_NAME":          "ios-simple-objc",
+		"DISPLAY_NAME":          "My App 2",
+		"CONFIGURATION":         "Release",
+		"BUNDLE_ID_Release":     "com.bitrise.release",
+		"BUNDLE_ID_Debug":       "com.bitrise.debug",
+		"INFOPLIST_FILE":        "App/Supporting Files/../Info.plist",
+		"EMPTY":                 "",
+		"BUNDLE_ID_PREFIX":      "com.$(COMPANY:lower)",
+		"COMPANY":               "Bitrise",
+		"LIST":                  []interface{}{"-ObjC", "-lz"},
+		"PRODUCT_NAME_PREFIXED": "$PRODUCT_NAME",
+	}
+
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: `auto_provision.$(PRODUCT_NAME:rfc1034identifier).suffix.$(PRODUCT_NAME:rfc1034identifier)`, want: "auto_provision.ios-simple-objc.suffix.ios-simple-objc"},
+		{value: `auto_provision.$(PRODUCT_NAME:rfc1034identifier).suffix`, want: "auto_provision.ios-simple-objc.suffix"},
+		{value: `prefix.{text.${PRODUCT_NAME}.text}`, want: "prefix.{text.ios-simple-objc.text}"},
+		{value: `auto_provision.$PRODUCT_NAME`, want: "auto_provision.ios-simple-objc"},
+		{value: `auto_provision.$PRODUCT_NAMEsuffix`, want: "auto_provision.ios-simple-objcsuffix"},
+		{value: `$(DISPLAY_NAME:rfc1034identifier)`, want: "My-App-2"},
+		{value: `$(DISPLAY_NAME:c99extidentifier)`, want: "My_App_2"},
+		{value: `$(EMPTY:default=2App:c99extidentifier)`, want: "_2App"},
+		{value: `$(DISPLAY_NAME:lower)`, want: "my app 2"},
+		{value: `$(DISPLAY_NAME:upper)`, want: "MY APP 2"},
+		{value: `$(EMPTY:default=$(PRODUCT_NAME))`, want: "ios-simple-objc"},
+		{value: `$(NOT_DEFINED:default=fallback)`, want: "fallback"},
+		{value: `$(PRODUCT_NAME:default=fallback)`, want: "ios-simple-objc"},
+		{value: `$(INFOPLIST_FILE:base)`, want: "Info"},
+		{value: `$(INFOPLIST_FILE:file)`, want: "Info.plist"},
+		{value: `$(INFOPLIST_FILE:suffix)`, want: ".plist"},
+		{value: `$(INFOPLIST_FILE:dir)`, want: "App/Supporting Files/../"},
+		{value: `$(INFOPLIST_FILE:standardizepath)`, want: "App/Info.plist"},
+		{value: `$(INFOPLIST_FILE:quote)`, want: `App/Supporting\ Files/../Info.plist`},
+		{value: `$(BUNDLE_ID_$(CONFIGURATION))`, want: "com.bitrise.release"},
+		{value: `${BUNDLE_ID_${CONFIGURATION}:upper}`, want: "COM.BITRISE.RELEASE"},
+		{value: `$(BUNDLE_ID_PREFIX).app`, want: "com.bitrise.app"},
+		{value: `$(NOT_DEFINED)suffix`, want: "suffix"},
+		{value: `$(LIST) -framework UIKit`, want: "-ObjC -lz -framework UIKit"},
+		{value: `$(inherited) -ObjC`, want: " -ObjC"},
+		{value: `price: $$5`, want: "price: $5"},
+		{value: `unterminated $(PRODUCT_NAME`, want: "unterminated $(PRODUCT_NAME"},
+		{value: `$(PRODUCT_NAME_PREFIXED)`, want: "ios-simple-objc"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := Expand(tt.value, buildSettings)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestExpand_Errors(t *testing.T) {
+	buildSettings := serialized.Object{
+		"PRODUCT_NAME": "$(BUNDLE_ID)",
+		"BUNDLE_ID":    "$(PREFIX).$(PRODUCT_NAME)",
+		"PREFIX":       "com.bitrise",
+		"SELF":         "$(SELF)",
+	}
+
+	{
+		_, err := Expand("$(BUNDLE_ID)", buildSettings)
+		require.EqualError(t, err, "build setting reference cycle found: BUNDLE_ID -> PRODUCT_NAME -> BUNDLE_ID")
+		require.True(t, IsReferenceCycleError(err))
+		require.Equal(t, []string{"BUNDLE_ID", "PRODUCT_NAME", "BUNDLE_ID"}, err.(ReferenceCycleError).Keys)
+	}
+
+	{
+		_, err := ExpandBuildSetting("SELF", buildSettings)
+		require.EqualError(t, err, "build setting reference cycle found: SELF -> SELF")
+	}
+
+	{
+		_, err := Expand("$(PREFIX:unknown)", buildSettings)
+		require.EqualError(t, err, "unknown build setting operator: unknown")
+		require.False(t, IsReferenceCycleError(err))
+	}
+}
+
+func TestExpandBuildSetting(t *testing.T) {
+	buildSettings := serialized.Object{
+		"TARGET_NAME":               "My App",
+		"PRODUCT_NAME":              "$(TARGET_NAME)",
+		"PRODUCT_MODULE_NAME":       "$(PRODUCT_NAME:c99extidentifier)",
+		"PRODUCT_BUNDLE_IDENTIFIER": "io.bitrise.$(PRODUCT_NAME:rfc1034identifier)",
+	}
+
+	got, err := ExpandBuildSetting("PRODUCT_BUNDLE_IDENTIFIER", buildSettings)
+	require.NoError(t, err)
+	require.Equal(t, "io.bitrise.My-App", got)
+
+	got, err = ExpandBuildSetting("PRODUCT_MODULE_NAME", buildSettings)
+	require.NoError(t, err)
+	require.Equal(t, "My_App", got)
+
+	got, err = ExpandBuildSetting("NOT_DEFINED", buildSettings)
+	require.NoError(t, err)
+	require.Equal(t, "", got)
+}
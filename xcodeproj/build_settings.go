@@ -0,0 +1,232 @@
+package xcodeproj
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+)
+
+// defaultBuildSettings is the lowest layer of the offline build settings resolution,
+// it holds the Xcode provided defaults which are not written to the project file.
+var defaultBuildSettings = map[string]string{
+	"ACTION":                    "build",
+	"SDKROOT":                   "macosx",
+	"DEVELOPER_DIR":             "/Applications/Xcode.app/Contents/Developer",
+	"SRCROOT":                   "$(PROJECT_DIR)",
+	"SOURCE_ROOT":               "$(SRCROOT)",
+	"SYMROOT":                   "$(PROJECT_DIR)/build",
+	"OBJROOT":                   "$(SYMROOT)",
+	"BUILD_DIR":                 "$(SYMROOT)",
+	"BUILD_ROOT":                "$(SYMROOT)",
+	"CONFIGURATION_BUILD_DIR":   "$(BUILD_DIR)/$(CONFIGURATION)",
+	"BUILT_PRODUCTS_DIR":        "$(CONFIGURATION_BUILD_DIR)",
+	"TARGETNAME":                "$(TARGET_NAME)",
+	"PROJECT":                   "$(PROJECT_NAME)",
+	"PRODUCT_NAME":              "$(TARGET_NAME)",
+	"PRODUCT_MODULE_NAME":       "$(PRODUCT_NAME:c99extidentifier)",
+	"EXECUTABLE_PREFIX":         "",
+	"EXECUTABLE_SUFFIX":         "",
+	"EXECUTABLE_VARIANT_SUFFIX": "",
+	"EXECUTABLE_NAME":           "$(EXECUTABLE_PREFIX)$(PRODUCT_NAME)$(EXECUTABLE_VARIANT_SUFFIX)$(EXECUTABLE_SUFFIX)",
+	"WRAPPER_SUFFIX":            "",
+	"WRAPPER_NAME":              "$(PRODUCT_NAME)$(WRAPPER_SUFFIX)",
+	"FULL_PRODUCT_NAME":         "$(WRAPPER_NAME)",
+	"CONTENTS_FOLDER_PATH":      "$(WRAPPER_NAME)",
+	"INFOPLIST_PATH":            "$(CONTENTS_FOLDER_PATH)/Info.plist",
+}
+
+// wrapperExtensionByProductType maps the target product types to their bundle extension.
+var wrapperExtensionByProductType = map[string]string{
+	"com.apple.product-type.application":                           "app",
+	"com.apple.product-type.application.watchapp":                  "app",
+	"com.apple.product-type.application.watchapp2":                 "app",
+	"com.apple.product-type.application.messages":                  "app",
+	"com.apple.product-type.app-extension":                         "appex",
+	"com.apple.product-type.app-extension.messages":                "appex",
+	"com.apple.product-type.watchkit-extension":                    "appex",
+	"com.apple.product-type.watchkit2-extension":                   "appex",
+	"com.apple.product-type.tv-app-extension":                      "appex",
+	"com.apple.product-type.extensionkit-extension":                "appex",
+	"com.apple.product-type.framework":                             "framework",
+	"com.apple.product-type.framework.static":                      "framework",
+	"com.apple.product-type.bundle":                                "bundle",
+	"com.apple.product-type.bundle.unit-test":                      "xctest",
+	"com.apple.product-type.bundle.ui-testing":                     "xctest",
+	"com.apple.product-type.application.on-demand-install-capable": "app",
+}
+
+// ResolveTargetBuildSettings evaluates the build settings of the given target and configuration
+// without calling xcodebuild.
+//
+// The settings are layered the same way as Xcode does (lowest priority first):
+// Xcode defaults, the project level base configuration (.xcconfig), the project level build settings,
+// the target level base configuration (.xcconfig) and the target level build settings.
+// `$(inherited)` references are replaced with the value of the lower layers and
+// the build setting references are expanded in the returned settings.
+// If the configuration is empty the project's default configuration is used.
+// Conditional build settings are evaluated for the SDK set in SDKROOT, see ResolveTargetBuildSettingsForConditions.
+func (p XcodeProj) ResolveTargetBuildSettings(target, configuration string) (serialized.Object, error) {
+	return p.ResolveTargetBuildSettingsForConditions(target, configuration, BuildSettingConditions{})
+}
+
+// ResolveTargetBuildSettingsForConditions works like ResolveTargetBuildSettings, but conditional build settings
+// (like `CODE_SIGN_IDENTITY[sdk=iphoneos*]`) are evaluated in the given context:
+// in every layer the most specific matching variant of a setting overrides the unconditional one.
+// If the SDK of the context is empty, the SDK set in SDKROOT is used,
+// if the Variant is empty `normal` is used and the Configuration is always the resolved configuration.
+func (p XcodeProj) ResolveTargetBuildSettingsForConditions(target, configuration string, conditions BuildSettingConditions) (serialized.Object, error) {
+	t, ok := p.Proj.TargetByName(target)
+	if !ok {
+		return nil, fmt.Errorf("could not find target (%s)", target)
+	}
+
+	if configuration == "" {
+		configuration = p.Proj.BuildConfigurationList.DefaultConfigurationName
+	}
+
+	projectConfiguration, ok := buildConfigurationByName(p.Proj.BuildConfigurationList, configuration)
+	if !ok {
+		return nil, fmt.Errorf("could not find configuration (%s) for project (%s)", configuration, p.Name)
+	}
+
+	targetConfiguration, ok := buildConfigurationByName(t.BuildConfigurationList, configuration)
+	if !ok {
+		return nil, fmt.Errorf("could not find configuration (%s) for target (%s)", configuration, target)
+	}
+
+	layers := []serialized.Object{
+		p.defaultBuildSettings(t, configuration),
+		baseConfigurationBuildSettings(projectConfiguration),
+		projectConfiguration.BuildSettings,
+		baseConfigurationBuildSettings(targetConfiguration),
+		targetConfiguration.BuildSettings,
+	}
+
+	conditions.Configuration = configuration
+	if conditions.Variant == "" {
+		conditions.Variant = "normal"
+	}
+	if conditions.SDK == "" {
+		withoutSDK := serialized.Object{}
+		for _, layer := range layers {
+			mergeBuildSettingsLayer(withoutSDK, layer, conditions)
+		}
+
+		sdk, err := ExpandBuildSetting("SDKROOT", withoutSDK)
+		if err != nil {
+			return nil, fmt.Errorf("failed to evaluate SDKROOT: %s", err)
+		}
+		conditions.SDK = sdk
+	}
+
+	buildSettings := serialized.Object{}
+	for _, layer := range layers {
+		mergeBuildSettingsLayer(buildSettings, layer, conditions)
+	}
+
+	return expandBuildSettings(buildSettings), nil
+}
+
+func buildConfigurationByName(list ConfigurationList, name string) (BuildConfiguration, bool) {
+	for _, configuration := range list.BuildConfigurations {
+		if configuration.Name == name {
+			return configuration, true
+		}
+	}
+	return BuildConfiguration{}, false
+}
+
+func (p XcodeProj) defaultBuildSettings(target Target, configuration string) serialized.Object {
+	buildSettings := serialized.Object{}
+	for key, value := range defaultBuildSettings {
+		buildSettings[key] = value
+	}
+
+	projectDir := filepath.Dir(p.Path)
+	buildSettings["PROJECT_DIR"] = projectDir
+	buildSettings["PROJECT_FILE_PATH"] = p.Path
+	buildSettings["PROJECT_NAME"] = p.Name
+	buildSettings["TARGET_NAME"] = target.Name
+	buildSettings["CONFIGURATION"] = configuration
+	buildSettings["PRODUCT_TYPE"] = target.ProductType
+
+	if extension, ok := wrapperExtensionByProductType[target.ProductType]; ok {
+		buildSettings["WRAPPER_EXTENSION"] = extension
+		buildSettings["WRAPPER_SUFFIX"] = "." + extension
+	}
+
+	return buildSettings
+}
+
+// baseConfigurationBuildSettings returns the build settings defined in the base configuration (.xcconfig)
+// of the build configuration, the base configurations are loaded by Open.
+func baseConfigurationBuildSettings(buildConfiguration BuildConfiguration) serialized.Object {
+	if buildConfiguration.BaseConfiguration == nil {
+		return serialized.Object{}
+	}
+	return buildConfiguration.BaseConfiguration.Settings()
+}
+
+var inheritedPattern = regexp.MustCompile(`[$][({]inherited[)}]`)
+
+// mergeBuildSettingsLayer writes the layer's settings, which apply in the given context, over buildSettings,
+// `$(inherited)` is replaced with the value from the lower layers.
+func mergeBuildSettingsLayer(buildSettings, layer serialized.Object, conditions BuildSettingConditions) {
+	for key, value := range applyBuildSettingConditions(layer, conditions) {
+		inherited, _ := buildSettings[key].(string)
+		resolved := inheritedPattern.ReplaceAllLiteralString(buildSettingString(value), inherited)
+		buildSettings[key] = strings.TrimSpace(resolved)
+	}
+}
+
+// buildSettingString returns the string representation of a build setting value,
+// list values are joined the same way as xcodebuild prints them.
+func buildSettingString(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case []interface{}:
+		var items []string
+		for _, item := range v {
+			s := fmt.Sprintf("%v", item)
+			if strings.ContainsAny(s, " \t") && !strings.HasPrefix(s, `"`) {
+				s = `"` + s + `"`
+			}
+			items = append(items, s)
+		}
+		return strings.Join(items, " ")
+	case []string:
+		var items []interface{}
+		for _, item := range v {
+			items = append(items, item)
+		}
+		return buildSettingString(items)
+	default:
+		return fmt.Sprintf("%v", v)
+	}
+}
+
+// expandBuildSettings returns a copy of buildSettings with the build setting references expanded,
+// values which can not be evaluated (like reference cycles) are kept as they are.
+func expandBuildSettings(buildSettings serialized.Object) serialized.Object {
+	expander := newMacroExpander(buildSettings)
+	expanded := serialized.Object{}
+	for key, value := range buildSettings {
+		s, ok := value.(string)
+		if !ok || !strings.Contains(s, "$") {
+			expanded[key] = value
+			continue
+		}
+
+		resolved, err := expander.setting(key)
+		if err != nil {
+			expanded[key] = value
+			continue
+		}
+		expanded[key] = resolved
+	}
+	return expanded
+}
@@ -0,0 +1,307 @@
+package xcodeproj
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/xcode-project/serialized"
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func createProjectInTmpDir(t *testing.T, name, pbxproj string, files map[string]string) string {
+	tmpDir, err := pathutil.NormalizedOSTempDirPath("__xcode-proj__")
+	require.NoError(t, err)
+
+	projectPth := filepath.Join(tmpDir, name+".xcodeproj")
+	require.NoError(t, os.MkdirAll(projectPth, 0755))
+	require.NoError(t, ioutil.WriteFile(filepath.Join(projectPth, "project.pbxproj"), []byte(pbxproj), 0644))
+
+	for pth, content := range files {
+		pth = filepath.Join(tmpDir, pth)
+		require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0755))
+		require.NoError(t, ioutil.WriteFile(pth, []byte(content), 0644))
+	}
+
+	return projectPth
+}
+
+func TestXcodeProj_ResolveTargetBuildSettings(t *testing.T) {
+	pth := createProjectInTmpDir(t, "XcodeProj", testhelper.XcodeProjectTest, nil)
+	project, err := Open(pth)
+	require.NoError(t, err)
+
+	buildSettings, err := project.ResolveTargetBuildSettings("TodayExtension", "Release")
+	require.NoError(t, err)
+
+	projectDir := filepath.Dir(pth)
+	for key, want := range map[string]string{
+		"TARGET_NAME":               "TodayExtension",
+		"PRODUCT_NAME":              "TodayExtension",
+		"PRODUCT_BUNDLE_IDENTIFIER": "com.bitrise.XcodeProj.TodayExtension",
+		"CODE_SIGN_ENTITLEMENTS":    "TodayExtension/TodayExtension.entitlements",
+		"INFOPLIST_FILE":            "TodayExtension/Info.plist",
+		"SDKROOT":                   "iphoneos",
+		"CONFIGURATION":             "Release",
+		"SRCROOT":                   projectDir,
+		"WRAPPER_NAME":              "TodayExtension.appex",
+		"INFOPLIST_PATH":            "TodayExtension.appex/Info.plist",
+		"VALIDATE_PRODUCT":          "YES",
+	} {
+		got, err := buildSettings.String(key)
+		require.NoError(t, err, key)
+		require.Equal(t, want, got, key)
+	}
+
+	_, err = buildSettings.String("CODE_SIGN_IDENTITY[sdk=iphoneos*]")
+	require.True(t, serialized.IsKeyNotFoundError(err))
+
+	_, err = project.ResolveTargetBuildSettings("NotExisting", "Release")
+	require.EqualError(t, err, "could not find target (NotExisting)")
+
+	_, err = project.ResolveTargetBuildSettings("TodayExtension", "NotExisting")
+	require.EqualError(t, err, "could not find configuration (NotExisting) for project (XcodeProj)")
+}
+
+func TestXcodeProj_ResolveTargetBuildSettings_BaseConfigurations(t *testing.T) {
+	pth := createProjectInTmpDir(t, "BaseConfig", pbxprojWithBaseConfigurations, map[string]string{
+		"Configs/Shared.xcconfig": `// Shared settings
+OTHER_LDFLAGS = -ObjC
+SWIFT_VERSION = 4.2
+`,
+		"Configs/Project.xcconfig": `#include "Shared.xcconfig"
+#include? "Missing.xcconfig"
+BUNDLE_ID_PREFIX = io.bitrise // company prefix
+`,
+		"Configs/App.xcconfig": `OTHER_LDFLAGS = $(inherited) -framework UIKit
+PRODUCT_BUNDLE_IDENTIFIER = $(BUNDLE_ID_PREFIX).$(PRODUCT_NAME:rfc1034identifier)
+`,
+	})
+
+	project, err := Open(pth)
+	require.NoError(t, err)
+	project.OfflineBuildSettings = true
+
+	buildSettings, err := project.TargetBuildSettings("App", "Debug")
+	require.NoError(t, err)
+
+	for key, want := range map[string]string{
+		"OTHER_LDFLAGS":             "-ObjC -framework UIKit -lz",
+		"SWIFT_VERSION":             "5.0",
+		"PRODUCT_BUNDLE_IDENTIFIER": "io.bitrise.App",
+		"INFOPLIST_FILE":            "App/Info.plist",
+	} {
+		got, err := buildSettings.String(key)
+		require.NoError(t, err, key)
+		require.Equal(t, want, got, key)
+	}
+
+	bundleID, err := project.TargetBundleID("App", "Debug")
+	require.NoError(t, err)
+	require.Equal(t, "io.bitrise.App", bundleID)
+
+	infoPlistPth, err := project.TargetInformationPropertyListPath("App", "")
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(filepath.Dir(pth), "App/Info.plist"), infoPlistPth)
+}
+
+func Test_mergeBuildSettingsLayer(t *testing.T) {
+	buildSettings := serialized.Object{}
+	mergeBuildSettingsLayer(buildSettings, serialized.Object{
+		"OTHER_LDFLAGS":       "$(inherited) -ObjC",
+		"HEADER_SEARCH_PATHS": []interface{}{"$(inherited)", "Headers/Public"},
+	}, BuildSettingConditions{})
+	mergeBuildSettingsLayer(buildSettings, serialized.Object{
+		"OTHER_LDFLAGS":                     []interface{}{"$(inherited)", "-framework", "My Framework"},
+		"HEADER_SEARCH_PATHS":               "${inherited} Vendor",
+		"CODE_SIGN_IDENTITY[sdk=iphoneos*]": "iPhone Developer",
+	}, BuildSettingConditions{})
+
+	require.Equal(t, serialized.Object{
+		"OTHER_LDFLAGS":       `-ObjC -framework "My Framework"`,
+		"HEADER_SEARCH_PATHS": "Headers/Public Vendor",
+	}, buildSettings)
+}
+
+const pbxprojWithBaseConfigurations = `// !$*UTF8*$!
+{
+	archiveVersion = 1;
+	classes = {
+	};
+	objectVersion = 50;
+	objects = {
+
+/* Begin PBXFileReference section */
+		13A1F0F2258B7E2100A6A4B1 /* App.app */ = {isa = PBXFileReference; explicitFileType = wrapper.application; includeInIndex = 0; path = App.app; sourceTree = BUILT_PRODUCTS_DIR; };
+		13A1F0FA258B7E2100A6A4B1 /* Info.plist */ = {isa = PBXFileReference; lastKnownFileType = text.plist.xml; path = Info.plist; sourceTree = "<group>"; };
+		13A1F110258B7E5000A6A4B1 /* Project.xcconfig */ = {isa = PBXFileReference; lastKnownFileType = text.xcconfig; path = Project.xcconfig; sourceTree = "<group>"; };
+		13A1F111258B7E5000A6A4B1 /* App.xcconfig */ = {isa = PBXFileReference; lastKnownFileType = text.xcconfig; path = App.xcconfig; sourceTree = "<group>"; };
+/* End PBXFileReference section */
+
+/* Begin PBXGroup section */
+		13A1F0E9258B7E2100A6A4B1 = {
+			isa = PBXGroup;
+			children = (
+				13A1F0F4258B7E2100A6A4B1 /* App */,
+				13A1F112258B7E5000A6A4B1 /* Configs */,
+				13A1F0F3258B7E2100A6A4B1 /* Products */,
+			);
+			sourceTree = "<group>";
+		};
+		13A1F0F3258B7E2100A6A4B1 /* Products */ = {
+			isa = PBXGroup;
+			children = (
+				13A1F0F2258B7E2100A6A4B1 /* App.app */,
+			);
+			name = Products;
+			sourceTree = "<group>";
+		};
+		13A1F0F4258B7E2100A6A4B1 /* App */ = {
+			isa = PBXGroup;
+			children = (
+				13A1F0FA258B7E2100A6A4B1 /* Info.plist */,
+			);
+			path = App;
+			sourceTree = "<group>";
+		};
+		13A1F112258B7E5000A6A4B1 /* Configs */ = {
+			isa = PBXGroup;
+			children = (
+				13A1F110258B7E5000A6A4B1 /* Project.xcconfig */,
+				13A1F111258B7E5000A6A4B1 /* App.xcconfig */,
+			);
+			path = Configs;
+			sourceTree = "<group>";
+		};
+/* End PBXGroup section */
+
+/* Begin PBXNativeTarget section */
+		13A1F0F1258B7E2100A6A4B1 /* App */ = {
+			isa = PBXNativeTarget;
+			buildConfigurationList = 13A1F10A258B7E2200A6A4B1 /* Build configuration list for PBXNativeTarget "App" */;
+			buildPhases = (
+				13A1F0EE258B7E2100A6A4B1 /* Sources */,
+			);
+			buildRules = (
+			);
+			dependencies = (
+			);
+			name = App;
+			productName = App;
+			productReference = 13A1F0F2258B7E2100A6A4B1 /* App.app */;
+			productType = "com.apple.product-type.application";
+		};
+/* End PBXNativeTarget section */
+
+/* Begin PBXProject section */
+		13A1F0EA258B7E2100A6A4B1 /* Project object */ = {
+			isa = PBXProject;
+			attributes = {
+				LastUpgradeCheck = 1220;
+				TargetAttributes = {
+					13A1F0F1258B7E2100A6A4B1 = {
+						CreatedOnToolsVersion = 12.2;
+					};
+				};
+			};
+			buildConfigurationList = 13A1F0ED258B7E2100A6A4B1 /* Build configuration list for PBXProject "BaseConfig" */;
+			compatibilityVersion = "Xcode 9.3";
+			developmentRegion = en;
+			hasScannedForEncodings = 0;
+			knownRegions = (
+				en,
+				Base,
+			);
+			mainGroup = 13A1F0E9258B7E2100A6A4B1;
+			productRefGroup = 13A1F0F3258B7E2100A6A4B1 /* Products */;
+			projectDirPath = "";
+			projectRoot = "";
+			targets = (
+				13A1F0F1258B7E2100A6A4B1 /* App */,
+			);
+		};
+/* End PBXProject section */
+
+/* Begin PBXSourcesBuildPhase section */
+		13A1F0EE258B7E2100A6A4B1 /* Sources */ = {
+			isa = PBXSourcesBuildPhase;
+			buildActionMask = 2147483647;
+			files = (
+			);
+			runOnlyForDeploymentPostprocessing = 0;
+		};
+/* End PBXSourcesBuildPhase section */
+
+/* Begin XCBuildConfiguration section */
+		13A1F108258B7E2200A6A4B1 /* Debug */ = {
+			isa = XCBuildConfiguration;
+			baseConfigurationReference = 13A1F110258B7E5000A6A4B1 /* Project.xcconfig */;
+			buildSettings = {
+				IPHONEOS_DEPLOYMENT_TARGET = 14.2;
+				SDKROOT = iphoneos;
+				SWIFT_VERSION = 5.0;
+			};
+			name = Debug;
+		};
+		13A1F109258B7E2200A6A4B1 /* Release */ = {
+			isa = XCBuildConfiguration;
+			baseConfigurationReference = 13A1F110258B7E5000A6A4B1 /* Project.xcconfig */;
+			buildSettings = {
+				IPHONEOS_DEPLOYMENT_TARGET = 14.2;
+				SDKROOT = iphoneos;
+				SWIFT_VERSION = 5.0;
+				VALIDATE_PRODUCT = YES;
+			};
+			name = Release;
+		};
+		13A1F10B258B7E2200A6A4B1 /* Debug */ = {
+			isa = XCBuildConfiguration;
+			baseConfigurationReference = 13A1F111258B7E5000A6A4B1 /* App.xcconfig */;
+			buildSettings = {
+				"CODE_SIGN_IDENTITY[sdk=iphoneos*]" = "iPhone Developer";
+				INFOPLIST_FILE = App/Info.plist;
+				OTHER_LDFLAGS = (
+					"$(inherited)",
+					"-lz",
+				);
+			};
+			name = Debug;
+		};
+		13A1F10C258B7E2200A6A4B1 /* Release */ = {
+			isa = XCBuildConfiguration;
+			baseConfigurationReference = 13A1F111258B7E5000A6A4B1 /* App.xcconfig */;
+			buildSettings = {
+				"CODE_SIGN_IDENTITY[sdk=iphoneos*]" = "iPhone Distribution";
+				INFOPLIST_FILE = App/Info.plist;
+			};
+			name = Release;
+		};
+/* End XCBuildConfiguration section */
+
+/* Begin XCConfigurationList section */
+		13A1F0ED258B7E2100A6A4B1 /* Build configuration list for PBXProject "BaseConfig" */ = {
+			isa = XCConfigurationList;
+			buildConfigurations = (
+				13A1F108258B7E2200A6A4B1 /* Debug */,
+				13A1F109258B7E2200A6A4B1 /* Release */,
+			);
+			defaultConfigurationIsVisible = 0;
+			defaultConfigurationName = Release;
+		};
+		13A1F10A258B7E2200A6A4B1 /* Build configuration list for PBXNativeTarget "App" */ = {
+			isa = XCConfigurationList;
+			buildConfigurations = (
+				13A1F10B258B7E2200A6A4B1 /* Debug */,
+				13A1F10C258B7E2200A6A4B1 /* Release */,
+			);
+			defaultConfigurationIsVisible = 0;
+			defaultConfigurationName = Release;
+		};
+/* End XCConfigurationList section */
+	};
+	rootObject = 13A1F0EA258B7E2100A6A4B1 /* Project object */;
+}
+`
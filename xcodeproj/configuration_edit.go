@@ -0,0 +1,246 @@
+package xcodeproj
+
+import (
+	"fmt"
+
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/xcode-project/xcscheme"
+)
+
+// DuplicateBuildConfiguration adds a copy of the build configuration (like Release -> Staging)
+// to the project's and every target's build configuration list, which contains the configuration.
+// The copies keep the build settings and the base configuration (.xcconfig) of the original configurations.
+//
+// The project is not saved, call Save to write the changes.
+func (p *XcodeProj) DuplicateBuildConfiguration(name, newName string) error {
+	if err := p.checkNewBuildConfigurationName(name, newName); err != nil {
+		return err
+	}
+
+	objects, _, err := p.rawObjects()
+	if err != nil {
+		return err
+	}
+
+	for _, configurationList := range p.configurationLists() {
+		rawConfigurationList, err := objects.Object(configurationList.ID)
+		if err != nil {
+			return fmt.Errorf("failed to access build configuration list (%s): %s", configurationList.ID, err)
+		}
+
+		buildConfiguration, ok := buildConfigurationByName(configurationList, name)
+		if !ok {
+			continue
+		}
+
+		rawBuildConfiguration, err := objects.Object(buildConfiguration.ID)
+		if err != nil {
+			return fmt.Errorf("failed to access build configuration (%s): %s", buildConfiguration.ID, err)
+		}
+
+		duplicate := deepCopyObject(rawBuildConfiguration)
+		duplicate["name"] = newName
+		id, err := addObject(objects, duplicate)
+		if err != nil {
+			return err
+		}
+
+		if err := appendToArray(rawConfigurationList, "buildConfigurations", id); err != nil {
+			return fmt.Errorf("failed to add build configuration to list (%s): %s", configurationList.ID, err)
+		}
+	}
+
+	return p.reloadProj()
+}
+
+// RenameBuildConfiguration renames the build configuration in the project's and every target's build configuration list,
+// the default configuration names are updated too.
+// If updateSchemes is true, the scheme actions using the configuration are updated and written immediately.
+//
+// The project is not saved, call Save to write the changes.
+func (p *XcodeProj) RenameBuildConfiguration(oldName, newName string, updateSchemes bool) error {
+	if err := p.checkNewBuildConfigurationName(oldName, newName); err != nil {
+		return err
+	}
+
+	objects, _, err := p.rawObjects()
+	if err != nil {
+		return err
+	}
+
+	for _, configurationList := range p.configurationLists() {
+		rawConfigurationList, err := objects.Object(configurationList.ID)
+		if err != nil {
+			return fmt.Errorf("failed to access build configuration list (%s): %s", configurationList.ID, err)
+		}
+
+		if buildConfiguration, ok := buildConfigurationByName(configurationList, oldName); ok {
+			rawBuildConfiguration, err := objects.Object(buildConfiguration.ID)
+			if err != nil {
+				return fmt.Errorf("failed to access build configuration (%s): %s", buildConfiguration.ID, err)
+			}
+			rawBuildConfiguration["name"] = newName
+		}
+
+		if configurationList.DefaultConfigurationName == oldName {
+			rawConfigurationList["defaultConfigurationName"] = newName
+		}
+	}
+
+	if err := p.reloadProj(); err != nil {
+		return err
+	}
+
+	if updateSchemes {
+		return p.renameBuildConfigurationInSchemes(oldName, newName)
+	}
+	return nil
+}
+
+// RemoveBuildConfiguration removes the build configuration from the project's and every target's build configuration list.
+// The lists using the removed configuration as default configuration fall back to their first remaining configuration.
+// If updateSchemes is true, the scheme actions using the removed configuration are switched to the project's default configuration
+// and written immediately.
+//
+// The project is not saved, call Save to write the changes.
+func (p *XcodeProj) RemoveBuildConfiguration(name string, updateSchemes bool) error {
+	if _, ok := buildConfigurationByName(p.Proj.BuildConfigurationList, name); !ok {
+		return fmt.Errorf("build configuration not found: %s", name)
+	}
+	if len(p.Proj.BuildConfigurationList.BuildConfigurations) == 1 {
+		return fmt.Errorf("the last build configuration (%s) can not be removed", name)
+	}
+
+	objects, _, err := p.rawObjects()
+	if err != nil {
+		return err
+	}
+
+	for _, configurationList := range p.configurationLists() {
+		rawConfigurationList, err := objects.Object(configurationList.ID)
+		if err != nil {
+			return fmt.Errorf("failed to access build configuration list (%s): %s", configurationList.ID, err)
+		}
+
+		buildConfiguration, ok := buildConfigurationByName(configurationList, name)
+		if !ok {
+			continue
+		}
+
+		if err := removeFromArray(rawConfigurationList, "buildConfigurations", buildConfiguration.ID); err != nil {
+			return fmt.Errorf("failed to remove build configuration from list (%s): %s", configurationList.ID, err)
+		}
+		delete(objects, buildConfiguration.ID)
+
+		if configurationList.DefaultConfigurationName != name {
+			continue
+		}
+		for _, remaining := range configurationList.BuildConfigurations {
+			if remaining.Name != name {
+				rawConfigurationList["defaultConfigurationName"] = remaining.Name
+				break
+			}
+		}
+	}
+
+	if err := p.reloadProj(); err != nil {
+		return err
+	}
+
+	if updateSchemes {
+		defaultConfigurationName := p.Proj.BuildConfigurationList.DefaultConfigurationName
+		if defaultConfigurationName == "" {
+			defaultConfigurationName = p.Proj.BuildConfigurationList.BuildConfigurations[0].Name
+		}
+		return p.renameBuildConfigurationInSchemes(name, defaultConfigurationName)
+	}
+	return nil
+}
+
+// SetDefaultBuildConfiguration sets the default configuration name of the project's and every target's build configuration list,
+// which contains the configuration.
+//
+// The project is not saved, call Save to write the changes.
+func (p *XcodeProj) SetDefaultBuildConfiguration(name string) error {
+	if _, ok := buildConfigurationByName(p.Proj.BuildConfigurationList, name); !ok {
+		return fmt.Errorf("build configuration not found: %s", name)
+	}
+
+	objects, _, err := p.rawObjects()
+	if err != nil {
+		return err
+	}
+
+	for _, configurationList := range p.configurationLists() {
+		if _, ok := buildConfigurationByName(configurationList, name); !ok {
+			continue
+		}
+
+		rawConfigurationList, err := objects.Object(configurationList.ID)
+		if err != nil {
+			return fmt.Errorf("failed to access build configuration list (%s): %s", configurationList.ID, err)
+		}
+		rawConfigurationList["defaultConfigurationName"] = name
+	}
+
+	return p.reloadProj()
+}
+
+// checkNewBuildConfigurationName checks if the project has the name configuration
+// and none of the project's and the targets' lists has the newName configuration.
+func (p XcodeProj) checkNewBuildConfigurationName(name, newName string) error {
+	if _, ok := buildConfigurationByName(p.Proj.BuildConfigurationList, name); !ok {
+		return fmt.Errorf("build configuration not found: %s", name)
+	}
+	if newName == "" {
+		return fmt.Errorf("new build configuration name is empty")
+	}
+	for _, configurationList := range p.configurationLists() {
+		if _, ok := buildConfigurationByName(configurationList, newName); ok {
+			return fmt.Errorf("build configuration already exists: %s", newName)
+		}
+	}
+	return nil
+}
+
+// configurationLists returns the project's and the targets' build configuration lists.
+func (p XcodeProj) configurationLists() []ConfigurationList {
+	configurationLists := []ConfigurationList{p.Proj.BuildConfigurationList}
+	for _, target := range p.Proj.Targets {
+		configurationLists = append(configurationLists, target.BuildConfigurationList)
+	}
+	return configurationLists
+}
+
+// renameBuildConfigurationInSchemes updates the scheme actions using the oldName configuration in the project's schemes.
+func (p XcodeProj) renameBuildConfigurationInSchemes(oldName, newName string) error {
+	if p.Path == "" {
+		return nil
+	}
+
+	schemes, err := p.Schemes()
+	if err != nil {
+		return err
+	}
+
+	for _, scheme := range schemes {
+		content, err := fileutil.ReadBytesFromFile(scheme.Path)
+		if err != nil {
+			return err
+		}
+
+		content, changes, err := xcscheme.RenameBuildConfiguration(content, oldName, newName)
+		if err != nil {
+			return fmt.Errorf("failed to update build configuration in scheme (%s): %s", scheme.Path, err)
+		}
+		if len(changes) == 0 {
+			continue
+		}
+
+		if err := fileutil.WriteBytesToFile(scheme.Path, content); err != nil {
+			return fmt.Errorf("failed to write scheme (%s): %s", scheme.Path, err)
+		}
+	}
+
+	return nil
+}
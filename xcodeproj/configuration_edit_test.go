@@ -0,0 +1,141 @@
+package xcodeproj
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func configurationNames(configurationList ConfigurationList) []string {
+	var names []string
+	for _, buildConfiguration := range configurationList.BuildConfigurations {
+		names = append(names, buildConfiguration.Name)
+	}
+	return names
+}
+
+func TestXcodeProj_DuplicateBuildConfiguration(t *testing.T) {
+	project, err := parsePBXProjContent([]byte(testhelper.XcodeProjectTest))
+	require.NoError(t, err)
+
+	require.NoError(t, project.DuplicateBuildConfiguration("Release", "Staging"))
+
+	for _, configurationList := range project.configurationLists() {
+		require.Equal(t, []string{"Debug", "Release", "Staging"}, configurationNames(configurationList))
+
+		release, ok := buildConfigurationByName(configurationList, "Release")
+		require.True(t, ok)
+		staging, ok := buildConfigurationByName(configurationList, "Staging")
+		require.True(t, ok)
+		require.NotEqual(t, release.ID, staging.ID)
+		require.Equal(t, release.BuildSettings, staging.BuildSettings)
+		require.Equal(t, release.BaseConfigurationReference, staging.BaseConfigurationReference)
+	}
+
+	t.Log("the copy is independent of the original")
+	{
+		target, ok := project.Proj.TargetByName("XcodeProj")
+		require.True(t, ok)
+		staging, ok := buildConfigurationByName(target.BuildConfigurationList, "Staging")
+		require.True(t, ok)
+		staging.BuildSettings["CODE_SIGN_STYLE"] = "Manual"
+
+		release, ok := buildConfigurationByName(target.BuildConfigurationList, "Release")
+		require.True(t, ok)
+		require.Equal(t, "Automatic", release.BuildSettings["CODE_SIGN_STYLE"])
+	}
+
+	require.EqualError(t, project.DuplicateBuildConfiguration("Release", "Staging"), "build configuration already exists: Staging")
+	require.EqualError(t, project.DuplicateBuildConfiguration("CI", "Staging"), "build configuration not found: CI")
+	require.EqualError(t, project.DuplicateBuildConfiguration("Release", ""), "new build configuration name is empty")
+
+	t.Log("the new name is used by a target's configuration only")
+	{
+		target, ok := project.Proj.TargetByName("XcodeProj")
+		require.True(t, ok)
+		staging, ok := buildConfigurationByName(target.BuildConfigurationList, "Staging")
+		require.True(t, ok)
+		objects, _, err := project.rawObjects()
+		require.NoError(t, err)
+		rawStaging, err := objects.Object(staging.ID)
+		require.NoError(t, err)
+		rawStaging["name"] = "Beta"
+		require.NoError(t, project.reloadProj())
+
+		require.EqualError(t, project.DuplicateBuildConfiguration("Release", "Beta"), "build configuration already exists: Beta")
+		require.EqualError(t, project.RenameBuildConfiguration("Staging", "Beta", false), "build configuration already exists: Beta")
+	}
+
+	_, err = project.perObjectModify()
+	require.NoError(t, err)
+}
+
+func TestXcodeProj_RenameAndRemoveBuildConfiguration(t *testing.T) {
+	schemePth := filepath.Join("XcodeProj.xcodeproj", "xcshareddata", "xcschemes", "XcodeProj.xcscheme")
+	scheme := `<?xml version="1.0" encoding="UTF-8"?>
+<Scheme
+   version = "1.3">
+   <TestAction
+      buildConfiguration = "Debug">
+   </TestAction>
+   <ArchiveAction
+      buildConfiguration = "Release"
+      revealArchiveInOrganizer = "YES">
+   </ArchiveAction>
+</Scheme>
+`
+	pth := createProjectInTmpDir(t, "XcodeProj", testhelper.XcodeProjectTest, map[string]string{schemePth: scheme})
+	schemeAbsPth := filepath.Join(filepath.Dir(pth), schemePth)
+	project, err := Open(pth)
+	require.NoError(t, err)
+
+	t.Log("rename")
+	{
+		require.NoError(t, project.RenameBuildConfiguration("Release", "AppStore", true))
+
+		for _, configurationList := range project.configurationLists() {
+			require.Equal(t, []string{"Debug", "AppStore"}, configurationNames(configurationList))
+			require.Equal(t, "AppStore", configurationList.DefaultConfigurationName)
+		}
+
+		content, err := ioutil.ReadFile(schemeAbsPth)
+		require.NoError(t, err)
+		require.Contains(t, string(content), `buildConfiguration = "AppStore"`)
+		require.Contains(t, string(content), `buildConfiguration = "Debug"`)
+	}
+
+	t.Log("set default")
+	{
+		require.NoError(t, project.SetDefaultBuildConfiguration("Debug"))
+		for _, configurationList := range project.configurationLists() {
+			require.Equal(t, "Debug", configurationList.DefaultConfigurationName)
+		}
+		require.EqualError(t, project.SetDefaultBuildConfiguration("Release"), "build configuration not found: Release")
+		require.NoError(t, project.SetDefaultBuildConfiguration("AppStore"))
+	}
+
+	t.Log("remove")
+	{
+		require.NoError(t, project.RemoveBuildConfiguration("AppStore", true))
+
+		for _, configurationList := range project.configurationLists() {
+			require.Equal(t, []string{"Debug"}, configurationNames(configurationList))
+			require.Equal(t, "Debug", configurationList.DefaultConfigurationName)
+		}
+
+		content, err := ioutil.ReadFile(schemeAbsPth)
+		require.NoError(t, err)
+		require.NotContains(t, string(content), `buildConfiguration = "AppStore"`)
+
+		require.EqualError(t, project.RemoveBuildConfiguration("Debug", true), "the last build configuration (Debug) can not be removed")
+	}
+
+	require.NoError(t, project.Save())
+	reopened, err := Open(pth)
+	require.NoError(t, err)
+	require.Equal(t, project.Proj.BuildConfigurationList.ID, reopened.Proj.BuildConfigurationList.ID)
+	require.Equal(t, []string{"Debug"}, configurationNames(reopened.Proj.BuildConfigurationList))
+}
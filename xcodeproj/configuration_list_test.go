@@ -92,7 +92,9 @@ const expectedConfigurationList = `{
 				"PROVISIONING_PROFILE": "",
 				"PROVISIONING_PROFILE_SPECIFIER": "",
 				"TARGETED_DEVICE_FAMILY": "1,2"
-			}
+			},
+			"BaseConfigurationReference": "",
+			"BaseConfiguration": null
 		},
 		{
 			"ID": "13E76E3C1F4AC90A0028096E",
@@ -109,7 +111,9 @@ const expectedConfigurationList = `{
 				"PROVISIONING_PROFILE": "",
 				"PROVISIONING_PROFILE_SPECIFIER": "",
 				"TARGETED_DEVICE_FAMILY": "1,2"
-			}
+			},
+			"BaseConfigurationReference": "",
+			"BaseConfiguration": null
 		}
 	]
 }`
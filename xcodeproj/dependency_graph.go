@@ -0,0 +1,421 @@
+package xcodeproj
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+)
+
+// TargetNode is a target of a DependencyGraph.
+type TargetNode struct {
+	// ProjectPath is the absolute path of the target's project, empty for a project not opened from a path.
+	ProjectPath string
+	ID          string
+	Name        string
+	// ProductType and BundleID are set for the targets of the projects in the graph,
+	// the BundleID is the PRODUCT_BUNDLE_IDENTIFIER of the default configuration, empty if it is not set.
+	ProductType string
+	BundleID    string
+}
+
+// String returns the target's name prefixed with its project's name, like `App/Widget`.
+func (n TargetNode) String() string {
+	if n.ProjectPath == "" {
+		return n.Name
+	}
+	return n.projectName() + "/" + n.Name
+}
+
+// projectName returns the name of the target's project, empty for a project not opened from a path.
+func (n TargetNode) projectName() string {
+	if n.ProjectPath == "" {
+		return ""
+	}
+	return strings.TrimSuffix(filepath.Base(n.ProjectPath), filepath.Ext(n.ProjectPath))
+}
+
+type targetKey struct {
+	projectPath, id string
+}
+
+func (n TargetNode) key() targetKey {
+	return targetKey{projectPath: n.ProjectPath, id: n.ID}
+}
+
+// DependencyGraph is the target dependency graph of one or more projects,
+// its edges point from the targets to the targets they depend on.
+// Dependencies on the targets of other projects (through a PBXContainerItemProxy) are resolved,
+// if the other project is part of the graph.
+type DependencyGraph struct {
+	nodes map[targetKey]TargetNode
+	// order is the order of the targets in their projects
+	order    []targetKey
+	edges    map[targetKey][]targetKey
+	projects map[string]bool
+	// usages are the frameworks, package products and target products linked or embedded by the targets
+	usages map[targetKey][]productUsage
+	// products are the targets by their product reference (the key of the product reference's project path and ID)
+	products map[targetKey]targetKey
+}
+
+// DependencyGraph returns the target dependency graph of the project.
+func (p XcodeProj) DependencyGraph() (DependencyGraph, error) {
+	return NewDependencyGraph(p)
+}
+
+// NewDependencyGraph returns the target dependency graph of the projects,
+// dependencies between the given projects are resolved.
+func NewDependencyGraph(projects ...XcodeProj) (DependencyGraph, error) {
+	g := DependencyGraph{
+		nodes:    map[targetKey]TargetNode{},
+		edges:    map[targetKey][]targetKey{},
+		projects: map[string]bool{},
+		usages:   map[targetKey][]productUsage{},
+		products: map[targetKey]targetKey{},
+	}
+
+	for _, project := range projects {
+		g.projects[project.Path] = true
+	}
+
+	for _, project := range projects {
+		if err := g.addProject(project); err != nil {
+			return DependencyGraph{}, err
+		}
+	}
+
+	return g, nil
+}
+
+// AddProject adds the targets and the target dependencies of the project to the graph,
+// the project's targets, which were unresolved dependencies of the already added projects, become resolved.
+// Adding an already added project does nothing.
+func (g *DependencyGraph) AddProject(p XcodeProj) error {
+	if g.projects[p.Path] {
+		return nil
+	}
+	g.projects[p.Path] = true
+
+	return g.addProject(p)
+}
+
+func (g *DependencyGraph) addProject(p XcodeProj) error {
+	objects, err := p.RawProj.Object("objects")
+	if err != nil {
+		return fmt.Errorf("failed to access objects: %s", err)
+	}
+
+	for _, target := range p.Proj.Targets {
+		rawTarget, err := objects.Object(target.ID)
+		if err != nil {
+			return fmt.Errorf("failed to access target (%s): %s", target.ID, err)
+		}
+
+		node := TargetNode{
+			ProjectPath: p.Path,
+			ID:          target.ID,
+			Name:        target.Name,
+			ProductType: target.ProductType,
+			BundleID:    p.targetBundleID(target),
+		}
+		g.addNode(node)
+		// a dependency of an already added project might have added the target without its product
+		g.nodes[node.key()] = node
+
+		if productReferenceID := optionalString(rawTarget, "productReference"); productReferenceID != "" {
+			g.products[targetKey{projectPath: p.Path, id: productReferenceID}] = node.key()
+		}
+	}
+
+	for _, target := range p.Proj.Targets {
+		from := g.nodes[targetKey{projectPath: p.Path, id: target.ID}]
+
+		rawTarget, err := objects.Object(target.ID)
+		if err != nil {
+			return fmt.Errorf("failed to access target (%s): %s", target.ID, err)
+		}
+
+		dependencyIDs, err := rawTarget.StringSlice("dependencies")
+		if err != nil && !serialized.IsKeyNotFoundError(err) {
+			return fmt.Errorf("failed to access dependencies of target (%s): %s", target.Name, err)
+		}
+
+		for _, dependencyID := range dependencyIDs {
+			to, ok, err := p.dependencyNode(objects, dependencyID)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+
+			g.addNode(to)
+			g.addEdge(from, to)
+		}
+
+		if err := g.addProductUsages(p, objects, target); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// targetBundleID returns the bundle ID of the target's default configuration, empty if it can not be resolved.
+func (p XcodeProj) targetBundleID(target Target) string {
+	buildSettings, err := p.ResolveTargetBuildSettings(target.Name, "")
+	if err != nil {
+		return ""
+	}
+	return optionalString(buildSettings, "PRODUCT_BUNDLE_IDENTIFIER")
+}
+
+// dependencyNode returns the target of the PBXTargetDependency,
+// the target of its PBXContainerItemProxy for a dependency on a target of another project.
+func (p XcodeProj) dependencyNode(objects serialized.Object, dependencyID string) (TargetNode, bool, error) {
+	dependency, err := objects.Object(dependencyID)
+	if err != nil {
+		if serialized.IsKeyNotFoundError(err) {
+			return TargetNode{}, false, nil
+		}
+		return TargetNode{}, false, fmt.Errorf("failed to access target dependency (%s): %s", dependencyID, err)
+	}
+
+	if targetID := optionalString(dependency, "target"); targetID != "" {
+		target, ok := p.Proj.Target(targetID)
+		if !ok {
+			return TargetNode{}, false, nil
+		}
+		return TargetNode{ProjectPath: p.Path, ID: target.ID, Name: target.Name}, true, nil
+	}
+
+	proxyID := optionalString(dependency, "targetProxy")
+	if proxyID == "" {
+		return TargetNode{}, false, nil
+	}
+	proxy, err := objects.Object(proxyID)
+	if err != nil {
+		if serialized.IsKeyNotFoundError(err) {
+			return TargetNode{}, false, nil
+		}
+		return TargetNode{}, false, fmt.Errorf("failed to access container item proxy (%s): %s", proxyID, err)
+	}
+
+	projectPath, ok, err := p.proxyProjectPath(proxy)
+	if err != nil || !ok {
+		return TargetNode{}, false, err
+	}
+
+	return TargetNode{
+		ProjectPath: projectPath,
+		ID:          optionalString(proxy, "remoteGlobalIDString"),
+		Name:        optionalString(proxy, "remoteInfo"),
+	}, true, nil
+}
+
+// proxyProjectPath returns the path of the project containing the remote object of the PBXContainerItemProxy:
+// the project itself or another project referenced by the project.
+func (p XcodeProj) proxyProjectPath(proxy serialized.Object) (string, bool, error) {
+	containerPortal := optionalString(proxy, "containerPortal")
+	if containerPortal == p.Proj.ID {
+		return p.Path, true, nil
+	}
+
+	element, ok := p.Proj.Element(containerPortal)
+	if !ok {
+		return "", false, nil
+	}
+	paths, err := p.SourceTreePaths()
+	if err != nil {
+		return "", false, err
+	}
+	pth, err := element.AbsPath(paths)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to resolve path of project reference (%s): %s", containerPortal, err)
+	}
+
+	return filepath.Clean(pth), true, nil
+}
+
+func (g *DependencyGraph) addNode(node TargetNode) {
+	key := node.key()
+	if _, ok := g.nodes[key]; ok {
+		return
+	}
+	g.nodes[key] = node
+	g.order = append(g.order, key)
+}
+
+func (g *DependencyGraph) addEdge(from, to TargetNode) {
+	for _, key := range g.edges[from.key()] {
+		if key == to.key() {
+			return
+		}
+	}
+	g.edges[from.key()] = append(g.edges[from.key()], to.key())
+}
+
+// Targets returns the targets of the graph: the targets of the projects in order, followed by the unresolved targets of other projects.
+func (g DependencyGraph) Targets() []TargetNode {
+	var resolved, unresolved []TargetNode
+	for _, key := range g.order {
+		if g.projects[key.projectPath] {
+			resolved = append(resolved, g.nodes[key])
+		} else {
+			unresolved = append(unresolved, g.nodes[key])
+		}
+	}
+	return append(resolved, unresolved...)
+}
+
+// TargetByName returns the first target with the given name.
+func (g DependencyGraph) TargetByName(name string) (TargetNode, bool) {
+	for _, target := range g.Targets() {
+		if target.Name == name {
+			return target, true
+		}
+	}
+	return TargetNode{}, false
+}
+
+// Unresolved returns the targets of the projects, which are not part of the graph.
+// Their dependencies are unknown.
+func (g DependencyGraph) Unresolved() []TargetNode {
+	var targets []TargetNode
+	for _, key := range g.order {
+		if !g.projects[key.projectPath] {
+			targets = append(targets, g.nodes[key])
+		}
+	}
+	return targets
+}
+
+// Dependencies returns the direct dependencies of the target, each target once.
+func (g DependencyGraph) Dependencies(target TargetNode) []TargetNode {
+	var targets []TargetNode
+	for _, key := range g.edges[target.key()] {
+		targets = append(targets, g.nodes[key])
+	}
+	return targets
+}
+
+// TransitiveDependencies returns the direct and indirect dependencies of the target, each target once, in depth first order.
+func (g DependencyGraph) TransitiveDependencies(target TargetNode) []TargetNode {
+	visited := map[targetKey]bool{target.key(): true}
+	var targets []TargetNode
+
+	var visit func(key targetKey)
+	visit = func(key targetKey) {
+		for _, dependency := range g.edges[key] {
+			if visited[dependency] {
+				continue
+			}
+			visited[dependency] = true
+			targets = append(targets, g.nodes[dependency])
+			visit(dependency)
+		}
+	}
+	visit(target.key())
+
+	return targets
+}
+
+// Cycles returns the groups of targets depending on each other (the strongly connected components of the graph),
+// including the targets depending on themselves.
+func (g DependencyGraph) Cycles() [][]TargetNode {
+	// Tarjan's algorithm
+	index := map[targetKey]int{}
+	lowLink := map[targetKey]int{}
+	onStack := map[targetKey]bool{}
+	var stack []targetKey
+	var cycles [][]TargetNode
+
+	var connect func(key targetKey)
+	connect = func(key targetKey) {
+		index[key] = len(index)
+		lowLink[key] = index[key]
+		stack = append(stack, key)
+		onStack[key] = true
+
+		selfDependent := false
+		for _, dependency := range g.edges[key] {
+			if dependency == key {
+				selfDependent = true
+			}
+			if _, ok := index[dependency]; !ok {
+				connect(dependency)
+				if lowLink[dependency] < lowLink[key] {
+					lowLink[key] = lowLink[dependency]
+				}
+			} else if onStack[dependency] && index[dependency] < lowLink[key] {
+				lowLink[key] = index[dependency]
+			}
+		}
+
+		if lowLink[key] != index[key] {
+			return
+		}
+
+		var component []TargetNode
+		for {
+			last := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[last] = false
+			component = append(component, g.nodes[last])
+			if last == key {
+				break
+			}
+		}
+
+		if len(component) > 1 || selfDependent {
+			sort.Slice(component, func(i, j int) bool {
+				return component[i].String() < component[j].String()
+			})
+			cycles = append(cycles, component)
+		}
+	}
+
+	for _, key := range g.order {
+		if _, ok := index[key]; !ok {
+			connect(key)
+		}
+	}
+
+	return cycles
+}
+
+// BuildOrder returns the targets in an order they can be built: every target follows its dependencies.
+// It returns an error if the graph has a dependency cycle.
+func (g DependencyGraph) BuildOrder() ([]TargetNode, error) {
+	if cycles := g.Cycles(); len(cycles) > 0 {
+		var names []string
+		for _, target := range cycles[0] {
+			names = append(names, target.String())
+		}
+		return nil, fmt.Errorf("dependency cycle between targets: %s", strings.Join(names, ", "))
+	}
+
+	visited := map[targetKey]bool{}
+	var targets []TargetNode
+
+	var visit func(key targetKey)
+	visit = func(key targetKey) {
+		if visited[key] {
+			return
+		}
+		visited[key] = true
+		for _, dependency := range g.edges[key] {
+			visit(dependency)
+		}
+		targets = append(targets, g.nodes[key])
+	}
+
+	for _, target := range g.Targets() {
+		visit(target.key())
+	}
+
+	return targets, nil
+}
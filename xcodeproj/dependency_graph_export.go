@@ -0,0 +1,381 @@
+package xcodeproj
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+)
+
+// DependencyNodeKind ...
+type DependencyNodeKind string
+
+// DependencyNodeKinds
+const (
+	TargetDependencyNode DependencyNodeKind = "target"
+	// FrameworkDependencyNode is a framework or library linked or embedded by a target, which is not the product of a target in the graph.
+	FrameworkDependencyNode      DependencyNodeKind = "framework"
+	PackageProductDependencyNode DependencyNodeKind = "package_product"
+)
+
+// DependencyEdgeKind ...
+type DependencyEdgeKind string
+
+// DependencyEdgeKinds
+const (
+	// TargetDependencyEdge: the target depends on the other target (PBXTargetDependency).
+	TargetDependencyEdge DependencyEdgeKind = "target_dependency"
+	// LinkDependencyEdge: the target links the framework or the other target's product (PBXFrameworksBuildPhase).
+	LinkDependencyEdge DependencyEdgeKind = "link"
+	// EmbedDependencyEdge: the target copies the framework or the other target's product into its product (PBXCopyFilesBuildPhase).
+	EmbedDependencyEdge DependencyEdgeKind = "embed"
+	// PackageProductDependencyEdge: the target uses the Swift package product (packageProductDependencies).
+	PackageProductDependencyEdge DependencyEdgeKind = "package_product"
+)
+
+// DependencyGraphNode is a node of the exported dependency graph.
+type DependencyGraphNode struct {
+	// ID is unique in the graph, like `target:App/Widget`, `framework:UIKit.framework` or `package_product:Alamofire`.
+	ID   string             `json:"id"`
+	Kind DependencyNodeKind `json:"kind"`
+	Name string             `json:"name"`
+	// Project is the name of the target's project, empty for a project not opened from a path.
+	Project     string `json:"project,omitempty"`
+	ProductType string `json:"product_type,omitempty"`
+	BundleID    string `json:"bundle_id,omitempty"`
+	// Unresolved is true for the targets of the projects, which are not part of the graph.
+	Unresolved bool `json:"unresolved,omitempty"`
+	// Package is the repository URL of a remote package or the relative path of a local package of the package product.
+	Package string `json:"package,omitempty"`
+}
+
+// DependencyGraphEdge is an edge of the exported dependency graph, pointing from a target to the node it depends on.
+type DependencyGraphEdge struct {
+	From string             `json:"from"`
+	To   string             `json:"to"`
+	Kind DependencyEdgeKind `json:"kind"`
+}
+
+// productUsage is a framework, package product or target product linked or embedded by a target.
+type productUsage struct {
+	kind DependencyEdgeKind
+	// product is the key of the used file's project path and product reference ID,
+	// it is resolved to a target if a target of the graph has the product.
+	product targetKey
+	// node is used if the product is not resolved to a target
+	node DependencyGraphNode
+}
+
+// embeddedFileExtensions are the extensions of the files, which are embedded into the product if copied by a PBXCopyFilesBuildPhase.
+var embeddedFileExtensions = map[string]bool{
+	".app":         true,
+	".appex":       true,
+	".bundle":      true,
+	".dylib":       true,
+	".framework":   true,
+	".xcframework": true,
+	".xpc":         true,
+}
+
+func (g *DependencyGraph) addProductUsages(p XcodeProj, objects serialized.Object, target Target) error {
+	from := targetKey{projectPath: p.Path, id: target.ID}
+
+	for _, dependency := range target.PackageProductDependencies {
+		g.addUsage(from, productUsage{kind: PackageProductDependencyEdge, node: p.packageProductNode(dependency)})
+	}
+
+	for _, buildPhase := range target.BuildPhases {
+		var kind DependencyEdgeKind
+		switch buildPhase.Type {
+		case FrameworksBuildPhaseType:
+			kind = LinkDependencyEdge
+		case CopyFilesBuildPhaseType:
+			kind = EmbedDependencyEdge
+		default:
+			continue
+		}
+
+		for _, buildFile := range buildPhase.Files {
+			if buildFile.ProductRef != "" {
+				// linked package products are listed in the packageProductDependencies too
+				if kind == EmbedDependencyEdge {
+					product, err := objects.Object(buildFile.ProductRef)
+					if err != nil {
+						continue
+					}
+					g.addUsage(from, productUsage{kind: kind, node: p.packageProductNode(SwiftPackageProductDependency{
+						ID:          buildFile.ProductRef,
+						ProductName: optionalString(product, "productName"),
+						Package:     optionalString(product, "package"),
+					})})
+				}
+				continue
+			}
+
+			usage, ok, err := p.fileUsage(objects, buildFile.FileRef)
+			if err != nil {
+				return err
+			}
+			if !ok {
+				continue
+			}
+			if _, isTargetProduct := g.products[usage.product]; kind == EmbedDependencyEdge && !isTargetProduct && !embeddedFileExtensions[filepath.Ext(usage.node.Name)] {
+				continue
+			}
+
+			usage.kind = kind
+			g.addUsage(from, usage)
+		}
+	}
+
+	return nil
+}
+
+// fileUsage returns the usage of a file reference or a PBXReferenceProxy (the product of a target of another project).
+func (p XcodeProj) fileUsage(objects serialized.Object, fileRef string) (productUsage, bool, error) {
+	file, err := objects.Object(fileRef)
+	if err != nil {
+		return productUsage{}, false, nil
+	}
+
+	name := optionalString(file, "name")
+	if name == "" {
+		name = filepath.Base(optionalString(file, "path"))
+	}
+	usage := productUsage{
+		product: targetKey{projectPath: p.Path, id: fileRef},
+		node:    DependencyGraphNode{ID: "framework:" + name, Kind: FrameworkDependencyNode, Name: name},
+	}
+
+	if optionalString(file, "isa") == "PBXReferenceProxy" {
+		proxy, err := objects.Object(optionalString(file, "remoteRef"))
+		if err != nil {
+			return usage, true, nil
+		}
+		projectPath, ok, err := p.proxyProjectPath(proxy)
+		if err != nil {
+			return productUsage{}, false, err
+		}
+		if ok {
+			usage.product = targetKey{projectPath: projectPath, id: optionalString(proxy, "remoteGlobalIDString")}
+		}
+	}
+
+	return usage, true, nil
+}
+
+func (p XcodeProj) packageProductNode(dependency SwiftPackageProductDependency) DependencyGraphNode {
+	node := DependencyGraphNode{
+		ID:   "package_product:" + dependency.ProductName,
+		Kind: PackageProductDependencyNode,
+		Name: dependency.ProductName,
+	}
+	for _, reference := range p.Proj.SwiftPackageReferences {
+		if reference.ID == dependency.Package {
+			node.Package = packageLocation(reference)
+		}
+	}
+	return node
+}
+
+func (g *DependencyGraph) addUsage(from targetKey, usage productUsage) {
+	for _, u := range g.usages[from] {
+		if u.kind == usage.kind && u.product == usage.product && u.node.ID == usage.node.ID {
+			return
+		}
+	}
+	g.usages[from] = append(g.usages[from], usage)
+}
+
+func (g DependencyGraph) targetGraphNode(target TargetNode) DependencyGraphNode {
+	return DependencyGraphNode{
+		ID:          "target:" + target.String(),
+		Kind:        TargetDependencyNode,
+		Name:        target.Name,
+		Project:     target.projectName(),
+		ProductType: target.ProductType,
+		BundleID:    target.BundleID,
+		Unresolved:  !g.projects[target.ProjectPath],
+	}
+}
+
+// usedNode returns the node of the used product: the target having the product or the framework or package product.
+func (g DependencyGraph) usedNode(usage productUsage) DependencyGraphNode {
+	if key, ok := g.products[usage.product]; ok {
+		return g.targetGraphNode(g.nodes[key])
+	}
+	return usage.node
+}
+
+// Nodes returns the nodes of the exported graph: the targets (in the order of Targets),
+// followed by the frameworks and package products used by the targets.
+func (g DependencyGraph) Nodes() []DependencyGraphNode {
+	var nodes []DependencyGraphNode
+	added := map[string]bool{}
+	add := func(node DependencyGraphNode) {
+		if !added[node.ID] {
+			added[node.ID] = true
+			nodes = append(nodes, node)
+		}
+	}
+
+	targets := g.Targets()
+	for _, target := range targets {
+		add(g.targetGraphNode(target))
+	}
+	for _, target := range targets {
+		for _, usage := range g.usages[target.key()] {
+			add(g.usedNode(usage))
+		}
+	}
+
+	return nodes
+}
+
+// Edges returns the edges of the exported graph: the target dependencies, framework linkages, embeddings
+// and package product usages of the targets, in the order of Targets.
+func (g DependencyGraph) Edges() []DependencyGraphEdge {
+	var edges []DependencyGraphEdge
+	added := map[DependencyGraphEdge]bool{}
+	add := func(edge DependencyGraphEdge) {
+		if !added[edge] {
+			added[edge] = true
+			edges = append(edges, edge)
+		}
+	}
+
+	for _, target := range g.Targets() {
+		from := g.targetGraphNode(target).ID
+		for _, dependency := range g.Dependencies(target) {
+			add(DependencyGraphEdge{From: from, To: g.targetGraphNode(dependency).ID, Kind: TargetDependencyEdge})
+		}
+		for _, usage := range g.usages[target.key()] {
+			add(DependencyGraphEdge{From: from, To: g.usedNode(usage).ID, Kind: usage.kind})
+		}
+	}
+
+	return edges
+}
+
+// JSON returns the nodes and edges of the graph in JSON format.
+func (g DependencyGraph) JSON() ([]byte, error) {
+	export := struct {
+		Nodes []DependencyGraphNode `json:"nodes"`
+		Edges []DependencyGraphEdge `json:"edges"`
+	}{
+		Nodes: g.Nodes(),
+		Edges: g.Edges(),
+	}
+	if export.Nodes == nil {
+		export.Nodes = []DependencyGraphNode{}
+	}
+	if export.Edges == nil {
+		export.Edges = []DependencyGraphEdge{}
+	}
+	return json.MarshalIndent(export, "", "  ")
+}
+
+// nodeLabelLines returns the lines of the node's label: the target's name (prefixed with its project's name),
+// product type and bundle ID, or the name of the framework or package product.
+func nodeLabelLines(node DependencyGraphNode) []string {
+	if node.Kind != TargetDependencyNode {
+		return []string{node.Name}
+	}
+
+	lines := []string{strings.TrimPrefix(node.ID, "target:")}
+	for _, line := range []string{node.ProductType, node.BundleID} {
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+var dotNodeShapes = map[DependencyNodeKind]string{
+	TargetDependencyNode:         "box",
+	FrameworkDependencyNode:      "ellipse",
+	PackageProductDependencyNode: "component",
+}
+
+var dotEdgeStyles = map[DependencyEdgeKind]string{
+	TargetDependencyEdge:         "solid",
+	LinkDependencyEdge:           "dashed",
+	EmbedDependencyEdge:          "bold",
+	PackageProductDependencyEdge: "dotted",
+}
+
+func dotQuote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(s) + `"`
+}
+
+// DOT returns the graph in Graphviz DOT format, like:
+//
+//	digraph dependencies {
+//		"target:App" [label="App\ncom.apple.product-type.application\nio.bitrise.App", shape=box];
+//		"target:App" -> "target:Widget" [label="embed", style=bold];
+//	}
+func (g DependencyGraph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph dependencies {\n")
+
+	for _, node := range g.Nodes() {
+		attributes := fmt.Sprintf("label=%s, shape=%s", dotQuote(strings.Join(nodeLabelLines(node), "\n")), dotNodeShapes[node.Kind])
+		if node.Unresolved {
+			attributes += ", style=dashed"
+		}
+		fmt.Fprintf(&b, "\t%s [%s];\n", dotQuote(node.ID), attributes)
+	}
+
+	for _, edge := range g.Edges() {
+		fmt.Fprintf(&b, "\t%s -> %s [label=%s, style=%s];\n", dotQuote(edge.From), dotQuote(edge.To), dotQuote(string(edge.Kind)), dotEdgeStyles[edge.Kind])
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// mermaidNodeShapes are the opening and closing brackets of the node shapes.
+var mermaidNodeShapes = map[DependencyNodeKind][2]string{
+	TargetDependencyNode:         {"[", "]"},
+	FrameworkDependencyNode:      {"([", "])"},
+	PackageProductDependencyNode: {"[[", "]]"},
+}
+
+var mermaidEdgeArrows = map[DependencyEdgeKind]string{
+	TargetDependencyEdge:         "-->",
+	LinkDependencyEdge:           "-. link .->",
+	EmbedDependencyEdge:          "== embed ==>",
+	PackageProductDependencyEdge: "-- package product -->",
+}
+
+// Mermaid returns the graph as a Mermaid flowchart, like:
+//
+//	graph LR
+//	    n0["App<br/>com.apple.product-type.application<br/>io.bitrise.App"]
+//	    n1["Widget<br/>com.apple.product-type.app-extension<br/>io.bitrise.App.Widget"]
+//	    n0 --> n1
+//	    n0 == embed ==> n1
+func (g DependencyGraph) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+
+	ids := map[string]string{}
+	for i, node := range g.Nodes() {
+		ids[node.ID] = fmt.Sprintf("n%d", i)
+
+		var lines []string
+		for _, line := range nodeLabelLines(node) {
+			lines = append(lines, strings.Replace(line, `"`, "#quot;", -1))
+		}
+		shape := mermaidNodeShapes[node.Kind]
+		fmt.Fprintf(&b, "    %s%s\"%s\"%s\n", ids[node.ID], shape[0], strings.Join(lines, "<br/>"), shape[1])
+	}
+
+	for _, edge := range g.Edges() {
+		fmt.Fprintf(&b, "    %s %s %s\n", ids[edge.From], mermaidEdgeArrows[edge.Kind], ids[edge.To])
+	}
+
+	return b.String()
+}
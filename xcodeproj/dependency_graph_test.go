@@ -0,0 +1,190 @@
+package xcodeproj
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func targetNames(targets []TargetNode) []string {
+	var names []string
+	for _, target := range targets {
+		names = append(names, target.String())
+	}
+	return names
+}
+
+func targetNodes(targets []Target) []TargetNode {
+	var nodes []TargetNode
+	for _, target := range targets {
+		nodes = append(nodes, TargetNode{ID: target.ID, Name: target.Name})
+	}
+	return nodes
+}
+
+func TestDependencyGraph(t *testing.T) {
+	// XcodeProjUITests depends on TodayExtension directly and through XcodeProj
+	content := strings.Replace(testhelper.XcodeProjectTest, `			dependencies = (
+				7D0342F720F4BA280050B6A6 /* PBXTargetDependency */,
+			);`, `			dependencies = (
+				7D0342F720F4BA280050B6A6 /* PBXTargetDependency */,
+				7D03431920F4BB070050B6A6 /* PBXTargetDependency */,
+			);`, 1)
+	project, err := parsePBXProjContent([]byte(content))
+	require.NoError(t, err)
+
+	uiTests, ok := project.Proj.TargetByName("XcodeProjUITests")
+	require.True(t, ok)
+	require.Equal(t, []string{"XcodeProj", "TodayExtension"}, targetNames(targetNodes(uiTests.DependentTargets())))
+
+	graph, err := project.DependencyGraph()
+	require.NoError(t, err)
+
+	node, ok := graph.TargetByName("XcodeProjUITests")
+	require.True(t, ok)
+	require.Equal(t, []string{"XcodeProj", "TodayExtension"}, targetNames(graph.Dependencies(node)))
+	require.Equal(t, []string{"XcodeProj", "TodayExtension"}, targetNames(graph.TransitiveDependencies(node)))
+	require.Equal(t, 0, len(graph.Cycles()))
+	require.Equal(t, 0, len(graph.Unresolved()))
+
+	order, err := graph.BuildOrder()
+	require.NoError(t, err)
+	require.Equal(t, []string{"TodayExtension", "XcodeProj", "XcodeProjUITests"}, targetNames(order))
+
+	t.Log("dependency cycle")
+	{
+		content := strings.Replace(content, `			dependencies = (
+			);
+			name = TodayExtension;`, `			dependencies = (
+				7D0342F720F4BA280050B6A6 /* PBXTargetDependency */,
+			);
+			name = TodayExtension;`, 1)
+		project, err := parsePBXProjContent([]byte(content))
+		require.NoError(t, err)
+
+		graph, err := project.DependencyGraph()
+		require.NoError(t, err)
+		require.Equal(t, 1, len(graph.Cycles()))
+		require.Equal(t, []string{"TodayExtension", "XcodeProj"}, targetNames(graph.Cycles()[0]))
+
+		_, err = graph.BuildOrder()
+		require.EqualError(t, err, "dependency cycle between targets: TodayExtension, XcodeProj")
+	}
+}
+
+func TestNewDependencyGraph(t *testing.T) {
+	pth := createProjectInTmpDir(t, "XcodeProj", testhelper.XcodeProjectTestWithProjectDependency("Lib/Lib.xcodeproj"), map[string]string{
+		filepath.Join("Lib", "Lib.xcodeproj", "project.pbxproj"): testhelper.XcodeProjectTest,
+	})
+	project, err := Open(pth)
+	require.NoError(t, err)
+
+	graph, err := project.DependencyGraph()
+	require.NoError(t, err)
+
+	node, ok := graph.TargetByName("XcodeProj")
+	require.True(t, ok)
+	require.Equal(t, []string{"XcodeProj/TodayExtension", "Lib/TodayExtension"}, targetNames(graph.Dependencies(node)))
+	require.Equal(t, []TargetNode{{ProjectPath: filepath.Join(filepath.Dir(pth), "Lib", "Lib.xcodeproj"), ID: "7D03430C20F4BB070050B6A6", Name: "TodayExtension"}}, graph.Unresolved())
+
+	lib, err := Open(graph.Unresolved()[0].ProjectPath)
+	require.NoError(t, err)
+
+	graph, err = NewDependencyGraph(project, lib)
+	require.NoError(t, err)
+	require.Equal(t, 0, len(graph.Unresolved()))
+	require.Equal(t, 6, len(graph.Targets()))
+
+	order, err := graph.BuildOrder()
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		"XcodeProj/TodayExtension", "Lib/TodayExtension", "XcodeProj/XcodeProj", "XcodeProj/XcodeProjUITests",
+		"Lib/XcodeProj", "Lib/XcodeProjUITests",
+	}, targetNames(order))
+
+	t.Log("add the dependency's project to the graph")
+	{
+		graph, err := project.DependencyGraph()
+		require.NoError(t, err)
+
+		require.NoError(t, graph.AddProject(lib))
+		require.NoError(t, graph.AddProject(lib))
+		require.Equal(t, 0, len(graph.Unresolved()))
+		require.Equal(t, 6, len(graph.Targets()))
+
+		node, ok := graph.TargetByName("XcodeProjUITests")
+		require.True(t, ok)
+		require.Equal(t, []string{"XcodeProj/XcodeProj", "XcodeProj/TodayExtension", "Lib/TodayExtension"}, targetNames(graph.TransitiveDependencies(node)))
+	}
+}
+
+func TestDependencyGraph_Export(t *testing.T) {
+	project, err := parsePBXProjContent([]byte(testhelper.XcodeProjectTest))
+	require.NoError(t, err)
+
+	graph, err := project.DependencyGraph()
+	require.NoError(t, err)
+
+	require.Equal(t, `digraph dependencies {
+	"target:XcodeProj" [label="XcodeProj\ncom.apple.product-type.application\ncom.bitrise.XcodeProj", shape=box];
+	"target:XcodeProjUITests" [label="XcodeProjUITests\ncom.apple.product-type.bundle.ui-testing\ncom.bitrise.XcodeProjUITests", shape=box];
+	"target:TodayExtension" [label="TodayExtension\ncom.apple.product-type.app-extension\ncom.bitrise.XcodeProj.TodayExtension", shape=box];
+	"framework:NotificationCenter.framework" [label="NotificationCenter.framework", shape=ellipse];
+	"framework:CloudKit.framework" [label="CloudKit.framework", shape=ellipse];
+	"target:XcodeProj" -> "target:TodayExtension" [label="target_dependency", style=solid];
+	"target:XcodeProj" -> "target:TodayExtension" [label="embed", style=bold];
+	"target:XcodeProjUITests" -> "target:XcodeProj" [label="target_dependency", style=solid];
+	"target:TodayExtension" -> "framework:NotificationCenter.framework" [label="link", style=dashed];
+	"target:TodayExtension" -> "framework:CloudKit.framework" [label="link", style=dashed];
+}
+`, graph.DOT())
+
+	require.Equal(t, `graph LR
+    n0["XcodeProj<br/>com.apple.product-type.application<br/>com.bitrise.XcodeProj"]
+    n1["XcodeProjUITests<br/>com.apple.product-type.bundle.ui-testing<br/>com.bitrise.XcodeProjUITests"]
+    n2["TodayExtension<br/>com.apple.product-type.app-extension<br/>com.bitrise.XcodeProj.TodayExtension"]
+    n3(["NotificationCenter.framework"])
+    n4(["CloudKit.framework"])
+    n0 --> n2
+    n0 == embed ==> n2
+    n1 --> n0
+    n2 -. link .-> n3
+    n2 -. link .-> n4
+`, graph.Mermaid())
+
+	t.Log("package products")
+	{
+		project, err := parsePBXProjContent([]byte(rawSwiftPackages))
+		require.NoError(t, err)
+
+		graph, err := project.DependencyGraph()
+		require.NoError(t, err)
+
+		content, err := graph.JSON()
+		require.NoError(t, err)
+
+		var export struct {
+			Nodes []DependencyGraphNode
+			Edges []DependencyGraphEdge
+		}
+		require.NoError(t, json.Unmarshal(content, &export))
+		require.Equal(t, 6, len(export.Nodes))
+		require.Equal(t, DependencyGraphNode{
+			ID:      "package_product:Alamofire",
+			Kind:    PackageProductDependencyNode,
+			Name:    "Alamofire",
+			Package: "https://github.com/Alamofire/Alamofire.git",
+		}, export.Nodes[2])
+		require.Equal(t, []DependencyGraphEdge{
+			{From: "target:App", To: "package_product:Alamofire", Kind: PackageProductDependencyEdge},
+			{From: "target:App", To: "package_product:Kingfisher", Kind: PackageProductDependencyEdge},
+			{From: "target:App", To: "package_product:Core", Kind: PackageProductDependencyEdge},
+			{From: "target:AppTests", To: "package_product:Alamofire", Kind: PackageProductDependencyEdge},
+			{From: "target:AppTests", To: "package_product:SnapshotTesting", Kind: PackageProductDependencyEdge},
+		}, export.Edges)
+	}
+}
@@ -0,0 +1,637 @@
+package xcodeproj
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/xcode-project/xcscheme"
+)
+
+// ChangeType ...
+type ChangeType string
+
+// ChangeTypes
+const (
+	AddedChange    ChangeType = "added"
+	RemovedChange  ChangeType = "removed"
+	ModifiedChange ChangeType = "modified"
+)
+
+func (c ChangeType) sign() string {
+	switch c {
+	case AddedChange:
+		return "+"
+	case RemovedChange:
+		return "-"
+	default:
+		return "~"
+	}
+}
+
+// TargetDiff is an added or removed target.
+type TargetDiff struct {
+	Change      ChangeType `json:"change"`
+	Name        string     `json:"name"`
+	ProductType string     `json:"product_type,omitempty"`
+}
+
+// ConfigurationDiff is a build configuration added to or removed from the project or a target.
+type ConfigurationDiff struct {
+	Change ChangeType `json:"change"`
+	// Target is empty for the project's build configurations.
+	Target string `json:"target,omitempty"`
+	Name   string `json:"name"`
+}
+
+// FileDiff is a file added to or removed from a build phase of a target.
+type FileDiff struct {
+	Change     ChangeType `json:"change"`
+	Target     string     `json:"target"`
+	BuildPhase string     `json:"build_phase"`
+	// File is the path of the file in the project's file tree (like App/Sources/AppDelegate.swift),
+	// or the name of the Swift package product.
+	File string `json:"file"`
+}
+
+// BuildSettingDiff is an added, removed or modified build setting.
+type BuildSettingDiff struct {
+	Change ChangeType `json:"change"`
+	// Target is empty for the project's build settings.
+	Target        string             `json:"target,omitempty"`
+	Configuration string             `json:"configuration"`
+	Key           string             `json:"key"`
+	Old           *BuildSettingValue `json:"old,omitempty"`
+	New           *BuildSettingValue `json:"new,omitempty"`
+}
+
+// PackageDiff is an added or removed Swift package, or a package with modified version requirement.
+type PackageDiff struct {
+	Change ChangeType `json:"change"`
+	// Package is the repository URL of remote packages and the relative path of local packages.
+	Package        string `json:"package"`
+	OldRequirement string `json:"old_requirement,omitempty"`
+	NewRequirement string `json:"new_requirement,omitempty"`
+}
+
+// PackageProductDiff is a Swift package product linked to or unlinked from a target.
+type PackageProductDiff struct {
+	Change  ChangeType `json:"change"`
+	Target  string     `json:"target"`
+	Product string     `json:"product"`
+}
+
+// SchemeDiff is an added, removed or modified scheme.
+type SchemeDiff struct {
+	Change ChangeType `json:"change"`
+	Name   string     `json:"name"`
+	// Changes describe the modifications of a modified scheme.
+	Changes []string `json:"changes,omitempty"`
+}
+
+// ProjectDiff is the semantic difference between two versions of a project.
+type ProjectDiff struct {
+	Targets         []TargetDiff         `json:"targets,omitempty"`
+	Configurations  []ConfigurationDiff  `json:"configurations,omitempty"`
+	Files           []FileDiff           `json:"files,omitempty"`
+	BuildSettings   []BuildSettingDiff   `json:"build_settings,omitempty"`
+	Packages        []PackageDiff        `json:"packages,omitempty"`
+	PackageProducts []PackageProductDiff `json:"package_products,omitempty"`
+	Schemes         []SchemeDiff         `json:"schemes,omitempty"`
+}
+
+// IsEmpty reports whether the two versions are semantically equal.
+func (d ProjectDiff) IsEmpty() bool {
+	return len(d.Targets) == 0 && len(d.Configurations) == 0 && len(d.Files) == 0 && len(d.BuildSettings) == 0 &&
+		len(d.Packages) == 0 && len(d.PackageProducts) == 0 && len(d.Schemes) == 0
+}
+
+// DiffPBXProj returns the semantic difference between two project.pbxproj file contents.
+func DiffPBXProj(oldContent, newContent []byte) (ProjectDiff, error) {
+	oldProject, err := parsePBXProjContent(oldContent)
+	if err != nil {
+		return ProjectDiff{}, fmt.Errorf("failed to parse old project: %s", err)
+	}
+
+	newProject, err := parsePBXProjContent(newContent)
+	if err != nil {
+		return ProjectDiff{}, fmt.Errorf("failed to parse new project: %s", err)
+	}
+
+	return DiffProjects(*oldProject, *newProject)
+}
+
+// DiffProjects returns the semantic difference between two versions of a project.
+// Schemes are compared if the projects were opened from the disk (their Path is set).
+func DiffProjects(oldProject, newProject XcodeProj) (ProjectDiff, error) {
+	var d ProjectDiff
+
+	oldTargets := targetsByName(oldProject.Proj)
+	newTargets := targetsByName(newProject.Proj)
+
+	for _, name := range sortedUnion(projectTargetNames(oldProject.Proj), projectTargetNames(newProject.Proj)) {
+		oldTarget, inOld := oldTargets[name]
+		newTarget, inNew := newTargets[name]
+
+		switch {
+		case !inOld:
+			d.Targets = append(d.Targets, TargetDiff{Change: AddedChange, Name: name, ProductType: newTarget.ProductType})
+		case !inNew:
+			d.Targets = append(d.Targets, TargetDiff{Change: RemovedChange, Name: name, ProductType: oldTarget.ProductType})
+		default:
+			d.Files = append(d.Files, diffBuildFiles(name, oldProject.Proj, oldTarget, newProject.Proj, newTarget)...)
+			d.PackageProducts = append(d.PackageProducts, diffPackageProducts(name, oldTarget, newTarget)...)
+
+			configurations, buildSettings, err := diffConfigurationLists(name, oldTarget.BuildConfigurationList, newTarget.BuildConfigurationList)
+			if err != nil {
+				return ProjectDiff{}, err
+			}
+			d.Configurations = append(d.Configurations, configurations...)
+			d.BuildSettings = append(d.BuildSettings, buildSettings...)
+		}
+	}
+
+	configurations, buildSettings, err := diffConfigurationLists("", oldProject.Proj.BuildConfigurationList, newProject.Proj.BuildConfigurationList)
+	if err != nil {
+		return ProjectDiff{}, err
+	}
+	d.Configurations = append(configurations, d.Configurations...)
+	d.BuildSettings = append(buildSettings, d.BuildSettings...)
+
+	d.Packages = diffPackages(oldProject.Proj.SwiftPackageReferences, newProject.Proj.SwiftPackageReferences)
+
+	if oldProject.Path != "" && newProject.Path != "" {
+		schemes, err := diffSchemes(oldProject, newProject)
+		if err != nil {
+			return ProjectDiff{}, err
+		}
+		d.Schemes = schemes
+	}
+
+	return d, nil
+}
+
+func projectTargetNames(proj Proj) []string {
+	var names []string
+	for _, target := range proj.Targets {
+		names = append(names, target.Name)
+	}
+	return names
+}
+
+func targetsByName(proj Proj) map[string]Target {
+	targets := map[string]Target{}
+	for _, target := range proj.Targets {
+		targets[target.Name] = target
+	}
+	return targets
+}
+
+func diffBuildFiles(targetName string, oldProj Proj, oldTarget Target, newProj Proj, newTarget Target) []FileDiff {
+	oldFiles := buildFilesByPhase(oldProj, oldTarget)
+	newFiles := buildFilesByPhase(newProj, newTarget)
+
+	var diffs []FileDiff
+	for _, phase := range sortedUnion(buildPhaseKeys(oldTarget), buildPhaseKeys(newTarget)) {
+		for _, file := range sortedUnion(setKeys(oldFiles[phase]), setKeys(newFiles[phase])) {
+			switch {
+			case !oldFiles[phase][file]:
+				diffs = append(diffs, FileDiff{Change: AddedChange, Target: targetName, BuildPhase: phase, File: file})
+			case !newFiles[phase][file]:
+				diffs = append(diffs, FileDiff{Change: RemovedChange, Target: targetName, BuildPhase: phase, File: file})
+			}
+		}
+	}
+	return diffs
+}
+
+// buildPhaseKeys returns the keys of the target's build phases: their names,
+// followed by their position among the same named phases (like `CopyFiles #2`) if the target has multiple phases with the same name.
+func buildPhaseKeys(target Target) []string {
+	var keys []string
+	count := map[string]int{}
+	for _, buildPhase := range target.BuildPhases {
+		name := buildPhase.DisplayName()
+		count[name]++
+		if count[name] > 1 {
+			name = fmt.Sprintf("%s #%d", name, count[name])
+		}
+		keys = append(keys, name)
+	}
+	return keys
+}
+
+// buildFilesByPhase returns the names of the target's build files by build phase key (see buildPhaseKeys).
+func buildFilesByPhase(proj Proj, target Target) map[string]map[string]bool {
+	products := map[string]string{}
+	for _, dependency := range target.PackageProductDependencies {
+		products[dependency.ID] = dependency.ProductName
+	}
+
+	files := map[string]map[string]bool{}
+	phaseKeys := buildPhaseKeys(target)
+	for i, buildPhase := range target.BuildPhases {
+		phase := phaseKeys[i]
+		files[phase] = map[string]bool{}
+
+		for _, buildFile := range buildPhase.Files {
+			var name string
+			switch {
+			case buildFile.ProductRef != "":
+				name = products[buildFile.ProductRef]
+				if name == "" {
+					name = buildFile.ProductRef
+				}
+			default:
+				name = elementTreePath(proj, buildFile.FileRef)
+			}
+			files[phase][name] = true
+		}
+	}
+	return files
+}
+
+// elementTreePath returns the path of the element in the project's file tree, built from the display names of the groups.
+func elementTreePath(proj Proj, id string) string {
+	element, ok := proj.Element(id)
+	if !ok {
+		return id
+	}
+
+	var components []string
+	for element != nil && element.parentGroup() != nil {
+		switch e := element.(type) {
+		case *FileReference:
+			components = append([]string{e.DisplayName()}, components...)
+		case *Group:
+			components = append([]string{e.DisplayName()}, components...)
+		}
+		element = element.parentGroup()
+	}
+	return strings.Join(components, "/")
+}
+
+func diffPackageProducts(targetName string, oldTarget, newTarget Target) []PackageProductDiff {
+	oldProducts := map[string]bool{}
+	for _, dependency := range oldTarget.PackageProductDependencies {
+		oldProducts[dependency.ProductName] = true
+	}
+	newProducts := map[string]bool{}
+	for _, dependency := range newTarget.PackageProductDependencies {
+		newProducts[dependency.ProductName] = true
+	}
+
+	var diffs []PackageProductDiff
+	for _, product := range sortedUnion(setKeys(oldProducts), setKeys(newProducts)) {
+		switch {
+		case !oldProducts[product]:
+			diffs = append(diffs, PackageProductDiff{Change: AddedChange, Target: targetName, Product: product})
+		case !newProducts[product]:
+			diffs = append(diffs, PackageProductDiff{Change: RemovedChange, Target: targetName, Product: product})
+		}
+	}
+	return diffs
+}
+
+func buildConfigurationNames(list ConfigurationList) []string {
+	var names []string
+	for _, buildConfiguration := range list.BuildConfigurations {
+		names = append(names, buildConfiguration.Name)
+	}
+	return names
+}
+
+func diffConfigurationLists(targetName string, oldList, newList ConfigurationList) ([]ConfigurationDiff, []BuildSettingDiff, error) {
+	oldConfigurations := map[string]BuildConfiguration{}
+	for _, buildConfiguration := range oldList.BuildConfigurations {
+		oldConfigurations[buildConfiguration.Name] = buildConfiguration
+	}
+	newConfigurations := map[string]BuildConfiguration{}
+	for _, buildConfiguration := range newList.BuildConfigurations {
+		newConfigurations[buildConfiguration.Name] = buildConfiguration
+	}
+
+	var configurationDiffs []ConfigurationDiff
+	var buildSettingDiffs []BuildSettingDiff
+	for _, name := range sortedUnion(buildConfigurationNames(oldList), buildConfigurationNames(newList)) {
+		oldConfiguration, inOld := oldConfigurations[name]
+		newConfiguration, inNew := newConfigurations[name]
+
+		switch {
+		case !inOld:
+			configurationDiffs = append(configurationDiffs, ConfigurationDiff{Change: AddedChange, Target: targetName, Name: name})
+		case !inNew:
+			configurationDiffs = append(configurationDiffs, ConfigurationDiff{Change: RemovedChange, Target: targetName, Name: name})
+		default:
+			for _, key := range sortedUnion(sortedKeys(oldConfiguration.BuildSettings), sortedKeys(newConfiguration.BuildSettings)) {
+				oldRaw, inOld := oldConfiguration.BuildSettings[key]
+				newRaw, inNew := newConfiguration.BuildSettings[key]
+				if inOld && inNew && reflect.DeepEqual(oldRaw, newRaw) {
+					continue
+				}
+
+				diff := BuildSettingDiff{Change: ModifiedChange, Target: targetName, Configuration: name, Key: key}
+				if inOld {
+					value, err := parseBuildSettingValue(key, oldRaw)
+					if err != nil {
+						return nil, nil, err
+					}
+					diff.Old = &value
+				} else {
+					diff.Change = AddedChange
+				}
+				if inNew {
+					value, err := parseBuildSettingValue(key, newRaw)
+					if err != nil {
+						return nil, nil, err
+					}
+					diff.New = &value
+				} else {
+					diff.Change = RemovedChange
+				}
+				buildSettingDiffs = append(buildSettingDiffs, diff)
+			}
+		}
+	}
+	return configurationDiffs, buildSettingDiffs, nil
+}
+
+func diffPackages(oldReferences, newReferences []SwiftPackageReference) []PackageDiff {
+	byPackage := func(references []SwiftPackageReference) map[string]SwiftPackageReference {
+		packages := map[string]SwiftPackageReference{}
+		for _, reference := range references {
+			packages[packageLocation(reference)] = reference
+		}
+		return packages
+	}
+	locations := func(references []SwiftPackageReference) []string {
+		var locations []string
+		for _, reference := range references {
+			locations = append(locations, packageLocation(reference))
+		}
+		return locations
+	}
+	oldPackages := byPackage(oldReferences)
+	newPackages := byPackage(newReferences)
+
+	var diffs []PackageDiff
+	for _, location := range sortedUnion(locations(oldReferences), locations(newReferences)) {
+		oldReference, inOld := oldPackages[location]
+		newReference, inNew := newPackages[location]
+
+		switch {
+		case !inOld:
+			diffs = append(diffs, PackageDiff{Change: AddedChange, Package: location, NewRequirement: packageRequirement(newReference)})
+		case !inNew:
+			diffs = append(diffs, PackageDiff{Change: RemovedChange, Package: location, OldRequirement: packageRequirement(oldReference)})
+		case !reflect.DeepEqual(oldReference.Requirement, newReference.Requirement):
+			diffs = append(diffs, PackageDiff{Change: ModifiedChange, Package: location, OldRequirement: packageRequirement(oldReference), NewRequirement: packageRequirement(newReference)})
+		}
+	}
+	return diffs
+}
+
+// packageLocation identifies a package: remote packages by their repository URL and local packages by their path.
+func packageLocation(reference SwiftPackageReference) string {
+	if reference.Type == LocalSwiftPackageReferenceType {
+		return reference.RelativePath
+	}
+	return reference.RepositoryURL
+}
+
+func packageRequirement(reference SwiftPackageReference) string {
+	if reference.Type == LocalSwiftPackageReferenceType {
+		return ""
+	}
+	return reference.Requirement.String()
+}
+
+func diffSchemes(oldProject, newProject XcodeProj) ([]SchemeDiff, error) {
+	var names []string
+	bySchemeName := func(project XcodeProj) (map[string]xcscheme.Scheme, error) {
+		schemes, err := project.Schemes()
+		if err != nil {
+			return nil, err
+		}
+
+		byName := map[string]xcscheme.Scheme{}
+		for _, scheme := range schemes {
+			byName[scheme.Name] = scheme
+			names = append(names, scheme.Name)
+		}
+		return byName, nil
+	}
+
+	oldSchemes, err := bySchemeName(oldProject)
+	if err != nil {
+		return nil, err
+	}
+	newSchemes, err := bySchemeName(newProject)
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []SchemeDiff
+	for _, name := range sortedUnion(names, nil) {
+		oldScheme, inOld := oldSchemes[name]
+		newScheme, inNew := newSchemes[name]
+
+		switch {
+		case !inOld:
+			diffs = append(diffs, SchemeDiff{Change: AddedChange, Name: name})
+		case !inNew:
+			diffs = append(diffs, SchemeDiff{Change: RemovedChange, Name: name})
+		default:
+			if changes := schemeChanges(oldScheme, newScheme); len(changes) > 0 {
+				diffs = append(diffs, SchemeDiff{Change: ModifiedChange, Name: name, Changes: changes})
+			}
+		}
+	}
+	return diffs, nil
+}
+
+func schemeChanges(oldScheme, newScheme xcscheme.Scheme) []string {
+	var changes []string
+
+	buildEntries := func(scheme xcscheme.Scheme) map[string]bool {
+		entries := map[string]bool{}
+		for _, entry := range scheme.BuildAction.BuildActionEntries {
+			entries[entry.BuildableReference.BlueprintName] = true
+		}
+		return entries
+	}
+	changes = append(changes, setChanges("build", buildEntries(oldScheme), buildEntries(newScheme))...)
+
+	testables := func(scheme xcscheme.Scheme) map[string]bool {
+		entries := map[string]bool{}
+		for _, testable := range scheme.TestAction.Testables {
+			name := testable.BuildableReference.BlueprintName
+			if testable.Skipped == "YES" {
+				name += " (skipped)"
+			}
+			entries[name] = true
+		}
+		return entries
+	}
+	changes = append(changes, setChanges("test", testables(oldScheme), testables(newScheme))...)
+
+	if oldScheme.TestAction.BuildConfiguration != newScheme.TestAction.BuildConfiguration {
+		changes = append(changes, fmt.Sprintf("test configuration: %s -> %s", oldScheme.TestAction.BuildConfiguration, newScheme.TestAction.BuildConfiguration))
+	}
+	if oldScheme.ArchiveAction.BuildConfiguration != newScheme.ArchiveAction.BuildConfiguration {
+		changes = append(changes, fmt.Sprintf("archive configuration: %s -> %s", oldScheme.ArchiveAction.BuildConfiguration, newScheme.ArchiveAction.BuildConfiguration))
+	}
+
+	return changes
+}
+
+func setChanges(action string, oldSet, newSet map[string]bool) []string {
+	var changes []string
+	for _, name := range sortedUnion(setKeys(oldSet), setKeys(newSet)) {
+		switch {
+		case !oldSet[name]:
+			changes = append(changes, fmt.Sprintf("%s: added %s", action, name))
+		case !newSet[name]:
+			changes = append(changes, fmt.Sprintf("%s: removed %s", action, name))
+		}
+	}
+	return changes
+}
+
+func setKeys(set map[string]bool) []string {
+	var keys []string
+	for key := range set {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+func sortedUnion(a, b []string) []string {
+	set := map[string]bool{}
+	for _, s := range append(append([]string{}, a...), b...) {
+		set[s] = true
+	}
+
+	var union []string
+	for s := range set {
+		union = append(union, s)
+	}
+	sort.Strings(union)
+	return union
+}
+
+// text returns the value as shown in the Build Settings editor of Xcode, list items are separated by spaces.
+func (v BuildSettingValue) text() string {
+	if !v.IsList {
+		return v.String
+	}
+	return strings.Join(v.List, " ")
+}
+
+// MarshalJSON encodes string values as JSON strings and list values as JSON arrays.
+func (v BuildSettingValue) MarshalJSON() ([]byte, error) {
+	return json.Marshal(v.raw())
+}
+
+// JSON returns the diff in JSON format.
+func (d ProjectDiff) JSON() ([]byte, error) {
+	return json.MarshalIndent(d, "", "  ")
+}
+
+// Text returns the diff in a human readable format, like:
+//
+//	Targets:
+//	  + Widget (com.apple.product-type.app-extension)
+//	Build settings:
+//	  ~ App [Release] SWIFT_VERSION: 4.0 -> 5.0
+func (d ProjectDiff) Text() string {
+	if d.IsEmpty() {
+		return "No changes\n"
+	}
+
+	var b strings.Builder
+	section := func(title string, lines []string) {
+		if len(lines) == 0 {
+			return
+		}
+		b.WriteString(title + ":\n")
+		for _, line := range lines {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+
+	var lines []string
+	for _, target := range d.Targets {
+		line := target.Change.sign() + " " + target.Name
+		if target.ProductType != "" {
+			line += " (" + target.ProductType + ")"
+		}
+		lines = append(lines, line)
+	}
+	section("Targets", lines)
+
+	lines = nil
+	for _, configuration := range d.Configurations {
+		lines = append(lines, fmt.Sprintf("%s %s %s", configuration.Change.sign(), diffTargetName(configuration.Target), configuration.Name))
+	}
+	section("Configurations", lines)
+
+	lines = nil
+	for _, file := range d.Files {
+		lines = append(lines, fmt.Sprintf("%s %s > %s: %s", file.Change.sign(), file.Target, file.BuildPhase, file.File))
+	}
+	section("Files", lines)
+
+	lines = nil
+	for _, setting := range d.BuildSettings {
+		line := fmt.Sprintf("%s %s [%s] %s", setting.Change.sign(), diffTargetName(setting.Target), setting.Configuration, setting.Key)
+		switch setting.Change {
+		case AddedChange:
+			line += ": " + setting.New.text()
+		case RemovedChange:
+			line += ": " + setting.Old.text()
+		default:
+			line += ": " + setting.Old.text() + " -> " + setting.New.text()
+		}
+		lines = append(lines, line)
+	}
+	section("Build settings", lines)
+
+	lines = nil
+	for _, pkg := range d.Packages {
+		line := fmt.Sprintf("%s %s", pkg.Change.sign(), pkg.Package)
+		switch {
+		case pkg.Change == ModifiedChange:
+			line += ": " + pkg.OldRequirement + " -> " + pkg.NewRequirement
+		case pkg.NewRequirement != "":
+			line += " (" + pkg.NewRequirement + ")"
+		case pkg.OldRequirement != "":
+			line += " (" + pkg.OldRequirement + ")"
+		}
+		lines = append(lines, line)
+	}
+	for _, product := range d.PackageProducts {
+		lines = append(lines, fmt.Sprintf("%s %s > %s", product.Change.sign(), product.Target, product.Product))
+	}
+	section("Packages", lines)
+
+	lines = nil
+	for _, scheme := range d.Schemes {
+		lines = append(lines, scheme.Change.sign()+" "+scheme.Name)
+		for _, change := range scheme.Changes {
+			lines = append(lines, "    "+change)
+		}
+	}
+	section("Schemes", lines)
+
+	return b.String()
+}
+
+func diffTargetName(target string) string {
+	if target == "" {
+		return "(project)"
+	}
+	return target
+}
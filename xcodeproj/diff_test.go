@@ -0,0 +1,148 @@
+package xcodeproj
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/bitrise-io/xcode-project/xcscheme"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffPBXProj(t *testing.T) {
+	project, err := parsePBXProjContent([]byte(testhelper.XcodeProjectTest))
+	require.NoError(t, err)
+
+	_, err = project.AddTarget("Widget", AppExtensionProductType)
+	require.NoError(t, err)
+	require.NoError(t, project.RemoveTarget("TodayExtension", false))
+
+	plist, err := project.AddFile("7D5B35FE20E28EE80022BAE6", "GoogleService-Info.plist")
+	require.NoError(t, err)
+	_, err = project.AddFileToTarget(plist.ID, "XcodeProj", ResourcesBuildPhaseType)
+	require.NoError(t, err)
+
+	require.NoError(t, project.DuplicateBuildConfiguration("Release", "Staging"))
+	require.NoError(t, project.SetBuildSetting("XcodeProj", "Debug", "SWIFT_VERSION", NewStringBuildSettingValue("5.0")))
+	require.NoError(t, project.AppendBuildSetting("XcodeProj", "Release", "OTHER_LDFLAGS", "-ObjC"))
+	require.NoError(t, project.DeleteBuildSetting("XcodeProjUITests", "Release", "TEST_TARGET_NAME"))
+
+	content, err := project.Marshal()
+	require.NoError(t, err)
+
+	diff, err := DiffPBXProj([]byte(testhelper.XcodeProjectTest), content)
+	require.NoError(t, err)
+
+	require.Equal(t, []TargetDiff{
+		{Change: RemovedChange, Name: "TodayExtension", ProductType: "com.apple.product-type.app-extension"},
+		{Change: AddedChange, Name: "Widget", ProductType: "com.apple.product-type.app-extension"},
+	}, diff.Targets)
+	require.Equal(t, []ConfigurationDiff{
+		{Change: AddedChange, Name: "Staging"},
+		{Change: AddedChange, Target: "XcodeProj", Name: "Staging"},
+		{Change: AddedChange, Target: "XcodeProjUITests", Name: "Staging"},
+	}, diff.Configurations)
+	require.Equal(t, []FileDiff{
+		{Change: RemovedChange, Target: "XcodeProj", BuildPhase: "Embed App Extensions", File: "Products/TodayExtension.appex"},
+		{Change: AddedChange, Target: "XcodeProj", BuildPhase: "Resources", File: "XcodeProj/GoogleService-Info.plist"},
+	}, diff.Files)
+
+	ldflags := NewListBuildSettingValue("$(inherited)", "-ObjC")
+	oldSwiftVersion := NewStringBuildSettingValue("4.0")
+	newSwiftVersion := NewStringBuildSettingValue("5.0")
+	testTargetName := NewStringBuildSettingValue("XcodeProj")
+	require.Equal(t, []BuildSettingDiff{
+		{Change: ModifiedChange, Target: "XcodeProj", Configuration: "Debug", Key: "SWIFT_VERSION", Old: &oldSwiftVersion, New: &newSwiftVersion},
+		{Change: AddedChange, Target: "XcodeProj", Configuration: "Release", Key: "OTHER_LDFLAGS", New: &ldflags},
+		{Change: RemovedChange, Target: "XcodeProjUITests", Configuration: "Release", Key: "TEST_TARGET_NAME", Old: &testTargetName},
+	}, diff.BuildSettings)
+	require.Equal(t, 0, len(diff.Packages))
+	require.Equal(t, 0, len(diff.Schemes))
+
+	require.Equal(t, `Targets:
+  - TodayExtension (com.apple.product-type.app-extension)
+  + Widget (com.apple.product-type.app-extension)
+Configurations:
+  + (project) Staging
+  + XcodeProj Staging
+  + XcodeProjUITests Staging
+Files:
+  - XcodeProj > Embed App Extensions: Products/TodayExtension.appex
+  + XcodeProj > Resources: XcodeProj/GoogleService-Info.plist
+Build settings:
+  ~ XcodeProj [Debug] SWIFT_VERSION: 4.0 -> 5.0
+  + XcodeProj [Release] OTHER_LDFLAGS: $(inherited) -ObjC
+  - XcodeProjUITests [Release] TEST_TARGET_NAME: XcodeProj
+`, diff.Text())
+
+	b, err := diff.JSON()
+	require.NoError(t, err)
+	require.Contains(t, string(b), `"key": "OTHER_LDFLAGS",
+      "new": [
+        "$(inherited)",
+        "-ObjC"
+      ]`)
+	require.Contains(t, string(b), `"old": "4.0",
+      "new": "5.0"`)
+
+	diff, err = DiffPBXProj([]byte(testhelper.XcodeProjectTest), []byte(testhelper.XcodeProjectTest))
+	require.NoError(t, err)
+	require.True(t, diff.IsEmpty())
+	require.Equal(t, "No changes\n", diff.Text())
+}
+
+func Test_diffPackages(t *testing.T) {
+	alamofire := SwiftPackageReference{Type: RemoteSwiftPackageReferenceType, RepositoryURL: "https://github.com/Alamofire/Alamofire", Requirement: VersionRequirement{Kind: UpToNextMajorVersionRequirementKind, MinimumVersion: "5.4.0"}}
+	alamofireUpdated := alamofire
+	alamofireUpdated.Requirement.MinimumVersion = "5.6.0"
+	kingfisher := SwiftPackageReference{Type: RemoteSwiftPackageReferenceType, RepositoryURL: "https://github.com/onevcat/Kingfisher", Requirement: VersionRequirement{Kind: ExactVersionRequirementKind, Version: "7.0.0"}}
+	local := SwiftPackageReference{Type: LocalSwiftPackageReferenceType, RelativePath: "Packages/Core"}
+
+	require.Equal(t, []PackageDiff{
+		{Change: RemovedChange, Package: "Packages/Core"},
+		{Change: ModifiedChange, Package: "https://github.com/Alamofire/Alamofire", OldRequirement: "up to next major from 5.4.0", NewRequirement: "up to next major from 5.6.0"},
+		{Change: AddedChange, Package: "https://github.com/onevcat/Kingfisher", NewRequirement: "exactly 7.0.0"},
+	}, diffPackages([]SwiftPackageReference{alamofire, local}, []SwiftPackageReference{alamofireUpdated, kingfisher}))
+}
+
+func Test_schemeChanges(t *testing.T) {
+	oldScheme := xcscheme.Scheme{
+		BuildAction: xcscheme.BuildAction{BuildActionEntries: []xcscheme.BuildActionEntry{
+			{BuildableReference: xcscheme.BuildableReference{BlueprintName: "App"}},
+			{BuildableReference: xcscheme.BuildableReference{BlueprintName: "Widget"}},
+		}},
+		TestAction:    xcscheme.TestAction{BuildConfiguration: "Debug", Testables: []xcscheme.TestableReference{{Skipped: "NO", BuildableReference: xcscheme.BuildableReference{BlueprintName: "AppTests"}}}},
+		ArchiveAction: xcscheme.ArchiveAction{BuildConfiguration: "Release"},
+	}
+	newScheme := xcscheme.Scheme{
+		BuildAction: xcscheme.BuildAction{BuildActionEntries: []xcscheme.BuildActionEntry{
+			{BuildableReference: xcscheme.BuildableReference{BlueprintName: "App"}},
+		}},
+		TestAction:    xcscheme.TestAction{BuildConfiguration: "Debug", Testables: []xcscheme.TestableReference{{Skipped: "YES", BuildableReference: xcscheme.BuildableReference{BlueprintName: "AppTests"}}}},
+		ArchiveAction: xcscheme.ArchiveAction{BuildConfiguration: "Staging"},
+	}
+
+	require.Equal(t, []string{
+		"build: removed Widget",
+		"test: removed AppTests",
+		"test: added AppTests (skipped)",
+		"archive configuration: Release -> Staging",
+	}, schemeChanges(oldScheme, newScheme))
+}
+
+func Test_diffBuildFiles_SameNamedPhases(t *testing.T) {
+	target := func(firstFiles, secondFiles []BuildFile) Target {
+		return Target{BuildPhases: []BuildPhase{
+			{Type: CopyFilesBuildPhaseType, Files: firstFiles},
+			{Type: CopyFilesBuildPhaseType, Files: secondFiles},
+		}}
+	}
+	framework := BuildFile{FileRef: "FRAMEWORK"}
+
+	oldTarget := target([]BuildFile{framework}, nil)
+	newTarget := target(nil, []BuildFile{framework})
+	require.Equal(t, []string{"CopyFiles", "CopyFiles #2"}, buildPhaseKeys(newTarget))
+	require.Equal(t, []FileDiff{
+		{Change: RemovedChange, Target: "App", BuildPhase: "CopyFiles", File: "FRAMEWORK"},
+		{Change: AddedChange, Target: "App", BuildPhase: "CopyFiles #2", File: "FRAMEWORK"},
+	}, diffBuildFiles("App", Proj{}, oldTarget, Proj{}, newTarget))
+}
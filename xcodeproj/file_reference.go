@@ -0,0 +1,64 @@
+package xcodeproj
+
+import (
+	"path/filepath"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+)
+
+// FileReference represents a PBXFileReference element
+// 47C11A4921FF63970084FD7F /* Assets.xcassets */ = {isa = PBXFileReference; lastKnownFileType = folder.assetcatalog; path = Assets.xcassets; sourceTree = "<group>"; };
+type FileReference struct {
+	ID                string
+	Name              string
+	Path              string
+	SourceTree        SourceTree
+	LastKnownFileType string
+	ExplicitFileType  string
+	FileEncoding      string
+
+	parent *Group
+}
+
+func newFileReference(id string, raw serialized.Object, parent *Group) *FileReference {
+	return &FileReference{
+		ID:                id,
+		Name:              optionalString(raw, "name"),
+		Path:              optionalString(raw, "path"),
+		SourceTree:        SourceTree(optionalString(raw, "sourceTree")),
+		LastKnownFileType: optionalString(raw, "lastKnownFileType"),
+		ExplicitFileType:  optionalString(raw, "explicitFileType"),
+		FileEncoding:      optionalString(raw, "fileEncoding"),
+		parent:            parent,
+	}
+}
+
+// AbsPath ...
+func (f *FileReference) AbsPath(paths SourceTreePaths) (string, error) {
+	return elementAbsPath(f, f.Path, f.SourceTree, paths)
+}
+
+func (f *FileReference) parentGroup() *Group {
+	return f.parent
+}
+
+// Parent returns the group containing the file reference.
+func (f *FileReference) Parent() *Group {
+	return f.parent
+}
+
+// DisplayName returns the name of the file reference as shown in Xcode.
+func (f *FileReference) DisplayName() string {
+	if f.Name != "" {
+		return f.Name
+	}
+	return filepath.Base(f.Path)
+}
+
+// FileType returns the explicit file type if set, otherwise the last known file type.
+func (f *FileReference) FileType() string {
+	if f.ExplicitFileType != "" {
+		return f.ExplicitFileType
+	}
+	return f.LastKnownFileType
+}
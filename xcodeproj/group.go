@@ -0,0 +1,314 @@
+package xcodeproj
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+)
+
+// SourceTree describes what the path of a project file tree element is relative to.
+type SourceTree string
+
+// SourceTrees
+const (
+	GroupSourceTree            SourceTree = "<group>"
+	AbsoluteSourceTree         SourceTree = "<absolute>"
+	SourceRootSourceTree       SourceTree = "SOURCE_ROOT"
+	BuiltProductsDirSourceTree SourceTree = "BUILT_PRODUCTS_DIR"
+	SDKRootSourceTree          SourceTree = "SDKROOT"
+	DeveloperDirSourceTree     SourceTree = "DEVELOPER_DIR"
+)
+
+// SourceTreePaths holds the absolute paths of the source trees, which are not part of the project's file tree.
+type SourceTreePaths struct {
+	SourceRoot       string
+	BuiltProductsDir string
+	SDKRoot          string
+	DeveloperDir     string
+}
+
+func (paths SourceTreePaths) root(sourceTree SourceTree) (string, error) {
+	var root string
+	switch sourceTree {
+	case SourceRootSourceTree:
+		root = paths.SourceRoot
+	case BuiltProductsDirSourceTree:
+		root = paths.BuiltProductsDir
+	case SDKRootSourceTree:
+		root = paths.SDKRoot
+	case DeveloperDirSourceTree:
+		root = paths.DeveloperDir
+	default:
+		return "", fmt.Errorf("unsupported source tree: %s", sourceTree)
+	}
+
+	if root == "" {
+		return "", fmt.Errorf("path of source tree (%s) is not set", sourceTree)
+	}
+	return root, nil
+}
+
+// SourceTreePaths returns the source tree paths known without building the project:
+// SourceRoot is the project's directory and DeveloperDir is read from the DEVELOPER_DIR environment variable
+// (falls back to the default Xcode location). BuiltProductsDir and SDKRoot depend on the build, they are left empty.
+func (p XcodeProj) SourceTreePaths() (SourceTreePaths, error) {
+	objects, err := p.RawProj.Object("objects")
+	if err != nil {
+		return SourceTreePaths{}, err
+	}
+
+	rawPBXProj, err := objects.Object(p.Proj.ID)
+	if err != nil {
+		return SourceTreePaths{}, err
+	}
+
+	sourceRoot := filepath.Join(filepath.Dir(p.Path), optionalString(rawPBXProj, "projectDirPath"), optionalString(rawPBXProj, "projectRoot"))
+
+	developerDir := os.Getenv("DEVELOPER_DIR")
+	if developerDir == "" {
+		developerDir = defaultBuildSettings["DEVELOPER_DIR"]
+	}
+
+	return SourceTreePaths{
+		SourceRoot:   sourceRoot,
+		DeveloperDir: developerDir,
+	}, nil
+}
+
+// FileLocations returns the absolute path of every file referenced in the project's file tree.
+// Files relative to a source tree, which path is unknown (like the products relative to BUILT_PRODUCTS_DIR), are skipped.
+func (p XcodeProj) FileLocations() ([]string, error) {
+	paths, err := p.SourceTreePaths()
+	if err != nil {
+		return nil, err
+	}
+
+	var locations []string
+	for _, fileReference := range p.Proj.FileReferences() {
+		if fileReference.SourceTree == BuiltProductsDirSourceTree || fileReference.SourceTree == SDKRootSourceTree {
+			continue
+		}
+
+		pth, err := fileReference.AbsPath(paths)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve path of file reference (%s): %s", fileReference.ID, err)
+		}
+		locations = append(locations, pth)
+	}
+	return locations, nil
+}
+
+// Element is a node of the project's file tree: a *Group or a *FileReference.
+type Element interface {
+	// AbsPath returns the absolute path of the element.
+	AbsPath(paths SourceTreePaths) (string, error)
+	parentGroup() *Group
+}
+
+// GroupType ...
+type GroupType string
+
+// GroupTypes
+const (
+	NormalGroupType  GroupType = "PBXGroup"
+	VariantGroupType GroupType = "PBXVariantGroup"
+	VersionGroupType GroupType = "XCVersionGroup"
+)
+
+// Group represents a PBXGroup, a PBXVariantGroup (localized resources)
+// or an XCVersionGroup (versioned Core Data models) element.
+type Group struct {
+	Type       GroupType
+	ID         string
+	Name       string
+	Path       string
+	SourceTree SourceTree
+	Children   []Element
+
+	parent *Group
+}
+
+// AbsPath ...
+func (g *Group) AbsPath(paths SourceTreePaths) (string, error) {
+	return elementAbsPath(g, g.Path, g.SourceTree, paths)
+}
+
+func (g *Group) parentGroup() *Group {
+	return g.parent
+}
+
+// Parent returns the group containing this group, nil for the main group.
+func (g *Group) Parent() *Group {
+	return g.parent
+}
+
+// DisplayName returns the name of the group as shown in Xcode.
+func (g *Group) DisplayName() string {
+	if g.Name != "" {
+		return g.Name
+	}
+	return filepath.Base(g.Path)
+}
+
+// Groups returns the direct child groups.
+func (g *Group) Groups() []*Group {
+	var groups []*Group
+	for _, child := range g.Children {
+		if group, ok := child.(*Group); ok {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// FileReferences returns the file references of the group and its descendant groups.
+func (g *Group) FileReferences() []*FileReference {
+	var fileReferences []*FileReference
+	for _, child := range g.Children {
+		switch child := child.(type) {
+		case *FileReference:
+			fileReferences = append(fileReferences, child)
+		case *Group:
+			fileReferences = append(fileReferences, child.FileReferences()...)
+		}
+	}
+	return fileReferences
+}
+
+// Element returns the element with the given ID from the group's tree (including the group itself).
+func (g *Group) Element(id string) (Element, bool) {
+	if g.ID == id {
+		return g, true
+	}
+
+	for _, child := range g.Children {
+		switch child := child.(type) {
+		case *FileReference:
+			if child.ID == id {
+				return child, true
+			}
+		case *Group:
+			if element, ok := child.Element(id); ok {
+				return element, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// elementAbsPath resolves the path of an element based on its source tree:
+// `<group>` paths are relative to the parent group, the other (non absolute) paths to the given source tree.
+func elementAbsPath(element Element, pth string, sourceTree SourceTree, paths SourceTreePaths) (string, error) {
+	switch sourceTree {
+	case AbsoluteSourceTree:
+		return filepath.Clean(pth), nil
+	case GroupSourceTree, "":
+		parent := element.parentGroup()
+		if parent == nil {
+			// the main group is relative to the project's source root
+			if paths.SourceRoot == "" {
+				return "", fmt.Errorf("path of source tree (%s) is not set", SourceRootSourceTree)
+			}
+			return filepath.Join(paths.SourceRoot, pth), nil
+		}
+
+		parentPth, err := parent.AbsPath(paths)
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(parentPth, pth), nil
+	default:
+		root, err := paths.root(sourceTree)
+		if err != nil {
+			return "", err
+		}
+		return filepath.Join(root, pth), nil
+	}
+}
+
+func isGroupType(isa string) bool {
+	switch GroupType(isa) {
+	case NormalGroupType, VariantGroupType, VersionGroupType:
+		return true
+	}
+	return false
+}
+
+// parseGroup parses the group and its descendants, ancestors are the IDs of the groups on the path from the main group.
+// A group can be the child of multiple groups, but a group referencing one of its ancestors is skipped.
+func parseGroup(id string, objects serialized.Object, parent *Group, ancestors map[string]bool) (*Group, error) {
+	ancestors[id] = true
+	defer delete(ancestors, id)
+
+	raw, err := objects.Object(id)
+	if err != nil {
+		return nil, err
+	}
+
+	isa, err := raw.String("isa")
+	if err != nil {
+		return nil, err
+	}
+
+	if !isGroupType(isa) {
+		return nil, fmt.Errorf("not a group element: %s", isa)
+	}
+
+	group := &Group{
+		Type:       GroupType(isa),
+		ID:         id,
+		Name:       optionalString(raw, "name"),
+		Path:       optionalString(raw, "path"),
+		SourceTree: SourceTree(optionalString(raw, "sourceTree")),
+		parent:     parent,
+	}
+
+	childIDs, err := raw.StringSlice("children")
+	if err != nil && !serialized.IsKeyNotFoundError(err) {
+		return nil, err
+	}
+
+	for _, childID := range childIDs {
+		if ancestors[childID] {
+			// circular reference, skipped as the dangling references
+			continue
+		}
+
+		rawChild, err := objects.Object(childID)
+		if err != nil {
+			if serialized.IsKeyNotFoundError(err) {
+				// dangling reference, Xcode ignores it too
+				continue
+			}
+			return nil, err
+		}
+
+		childIsa, err := rawChild.String("isa")
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case isGroupType(childIsa):
+			child, err := parseGroup(childID, objects, group, ancestors)
+			if err != nil {
+				return nil, err
+			}
+			group.Children = append(group.Children, child)
+		case childIsa == fileReferenceElementType:
+			group.Children = append(group.Children, newFileReference(childID, rawChild, group))
+		}
+	}
+
+	return group, nil
+}
+
+func optionalString(raw serialized.Object, key string) string {
+	value, err := raw.String(key)
+	if err != nil {
+		return ""
+	}
+	return value
+}
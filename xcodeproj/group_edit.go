@@ -0,0 +1,405 @@
+package xcodeproj
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+)
+
+// lastKnownFileTypeByExtension maps the file extensions to the file types Xcode assigns to the added files.
+var lastKnownFileTypeByExtension = map[string]string{
+	".swift":            "sourcecode.swift",
+	".h":                "sourcecode.c.h",
+	".m":                "sourcecode.c.objc",
+	".mm":               "sourcecode.cpp.objcpp",
+	".c":                "sourcecode.c.c",
+	".cpp":              "sourcecode.cpp.cpp",
+	".hpp":              "sourcecode.cpp.h",
+	".metal":            "sourcecode.metal",
+	".plist":            "text.plist.xml",
+	".entitlements":     "text.plist.entitlements",
+	".strings":          "text.plist.strings",
+	".stringsdict":      "text.plist.stringsdict",
+	".xcconfig":         "text.xcconfig",
+	".json":             "text.json",
+	".txt":              "text",
+	".md":               "net.daringfireball.markdown",
+	".sh":               "text.script.sh",
+	".storyboard":       "file.storyboard",
+	".xib":              "file.xib",
+	".intentdefinition": "file.intentdefinition",
+	".xcassets":         "folder.assetcatalog",
+	".png":              "image.png",
+	".jpg":              "image.jpeg",
+	".jpeg":             "image.jpeg",
+	".pdf":              "image.pdf",
+	".framework":        "wrapper.framework",
+	".xcframework":      "wrapper.xcframework",
+	".bundle":           "wrapper.plug-in",
+	".a":                "archive.ar",
+	".dylib":            "compiled.mach-o.dylib",
+	".tbd":              "sourcecode.text-based-dylib-definition",
+}
+
+// lastKnownFileType returns the file type of the file at pth based on its extension, `file` if the extension is unknown.
+func lastKnownFileType(pth string) string {
+	if fileType, ok := lastKnownFileTypeByExtension[strings.ToLower(filepath.Ext(pth))]; ok {
+		return fileType
+	}
+	return "file"
+}
+
+// AddGroup adds a group to the group with parentID (the main group if parentID is empty).
+// A group with a path is a folder relative to its parent group, a group with a name only is a virtual group;
+// the name is only stored if it differs from the last path component.
+func (p *XcodeProj) AddGroup(parentID, name, pth string) (*Group, error) {
+	if name == "" && pth == "" {
+		return nil, fmt.Errorf("either name or path of the group is required")
+	}
+
+	objects, _, err := p.rawObjects()
+	if err != nil {
+		return nil, err
+	}
+
+	rawParent, err := p.rawGroup(objects, parentID)
+	if err != nil {
+		return nil, err
+	}
+
+	rawGroup := serialized.Object{
+		"isa":        string(NormalGroupType),
+		"children":   []interface{}{},
+		"sourceTree": string(GroupSourceTree),
+	}
+	if pth != "" {
+		rawGroup["path"] = pth
+	}
+	if name != "" && name != filepath.Base(pth) {
+		rawGroup["name"] = name
+	}
+
+	id, err := addObject(objects, rawGroup)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := appendToArray(rawParent, "children", id); err != nil {
+		return nil, fmt.Errorf("failed to add group to its parent: %s", err)
+	}
+
+	if err := p.reloadProj(); err != nil {
+		return nil, err
+	}
+
+	element, ok := p.Proj.Element(id)
+	if !ok {
+		return nil, fmt.Errorf("failed to find added group: %s", id)
+	}
+	return element.(*Group), nil
+}
+
+// AddFile adds a file reference to the group with groupID (the main group if groupID is empty),
+// pth is relative to the group and the file type is inferred from the file's extension.
+func (p *XcodeProj) AddFile(groupID, pth string) (*FileReference, error) {
+	objects, _, err := p.rawObjects()
+	if err != nil {
+		return nil, err
+	}
+
+	rawParent, err := p.rawGroup(objects, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	if group, ok := p.group(groupID); ok {
+		for _, child := range group.Children {
+			if fileReference, ok := child.(*FileReference); ok && fileReference.Path == pth {
+				return nil, fmt.Errorf("file already exists in group (%s): %s", group.DisplayName(), pth)
+			}
+		}
+	}
+
+	rawFileReference := serialized.Object{
+		"isa":               fileReferenceElementType,
+		"lastKnownFileType": lastKnownFileType(pth),
+		"path":              pth,
+		"sourceTree":        string(GroupSourceTree),
+	}
+	if name := filepath.Base(pth); name != pth {
+		rawFileReference["name"] = name
+	}
+
+	id, err := addObject(objects, rawFileReference)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := appendToArray(rawParent, "children", id); err != nil {
+		return nil, fmt.Errorf("failed to add file reference to its group: %s", err)
+	}
+
+	if err := p.reloadProj(); err != nil {
+		return nil, err
+	}
+
+	element, ok := p.Proj.Element(id)
+	if !ok {
+		return nil, fmt.Errorf("failed to find added file reference: %s", id)
+	}
+	return element.(*FileReference), nil
+}
+
+// AddFileToTarget adds the file tree element with fileID to the target's first build phase of the given type
+// (like SourcesBuildPhaseType, ResourcesBuildPhaseType or FrameworksBuildPhaseType).
+func (p *XcodeProj) AddFileToTarget(fileID, targetName string, buildPhaseType BuildPhaseType) (BuildFile, error) {
+	if _, ok := p.Proj.Element(fileID); !ok {
+		return BuildFile{}, fmt.Errorf("file not found: %s", fileID)
+	}
+
+	target, ok := p.Proj.TargetByName(targetName)
+	if !ok {
+		return BuildFile{}, fmt.Errorf("target not found: %s", targetName)
+	}
+
+	buildPhases := target.BuildPhasesOfType(buildPhaseType)
+	if len(buildPhases) == 0 {
+		return BuildFile{}, fmt.Errorf("target (%s) has no %s build phase", targetName, buildPhaseType)
+	}
+	buildPhase := buildPhases[0]
+
+	for _, buildFile := range buildPhase.Files {
+		if buildFile.FileRef == fileID {
+			return BuildFile{}, fmt.Errorf("file (%s) is already in the %s build phase of target (%s)", fileID, buildPhase.DisplayName(), targetName)
+		}
+	}
+
+	objects, _, err := p.rawObjects()
+	if err != nil {
+		return BuildFile{}, err
+	}
+
+	rawBuildPhase, err := objects.Object(buildPhase.ID)
+	if err != nil {
+		return BuildFile{}, fmt.Errorf("failed to access build phase (%s): %s", buildPhase.ID, err)
+	}
+
+	id, err := addObject(objects, serialized.Object{
+		"isa":     "PBXBuildFile",
+		"fileRef": fileID,
+	})
+	if err != nil {
+		return BuildFile{}, err
+	}
+
+	if err := appendToArray(rawBuildPhase, "files", id); err != nil {
+		return BuildFile{}, fmt.Errorf("failed to add build file to build phase: %s", err)
+	}
+
+	if err := p.reloadProj(); err != nil {
+		return BuildFile{}, err
+	}
+
+	return BuildFile{ID: id, FileRef: fileID}, nil
+}
+
+// MoveElement moves the file tree element with id to the group with groupID (the main group if groupID is empty).
+// The element's path is kept as it is, so a group relative path points to a different location after the move.
+func (p *XcodeProj) MoveElement(id, groupID string) error {
+	element, ok := p.Proj.Element(id)
+	if !ok {
+		return fmt.Errorf("element not found: %s", id)
+	}
+
+	oldParent := element.parentGroup()
+	if oldParent == nil {
+		return fmt.Errorf("the main group can not be moved")
+	}
+
+	newParent, ok := p.group(groupID)
+	if !ok {
+		return fmt.Errorf("group not found: %s", groupID)
+	}
+
+	for g := newParent; g != nil; g = g.parent {
+		if g.ID == id {
+			return fmt.Errorf("group (%s) can not be moved into itself or into its descendant", id)
+		}
+	}
+
+	if oldParent.ID == newParent.ID {
+		return nil
+	}
+
+	objects, _, err := p.rawObjects()
+	if err != nil {
+		return err
+	}
+
+	rawOldParent, err := objects.Object(oldParent.ID)
+	if err != nil {
+		return fmt.Errorf("failed to access group (%s): %s", oldParent.ID, err)
+	}
+	rawNewParent, err := objects.Object(newParent.ID)
+	if err != nil {
+		return fmt.Errorf("failed to access group (%s): %s", newParent.ID, err)
+	}
+
+	if err := removeFromArray(rawOldParent, "children", id); err != nil {
+		return fmt.Errorf("failed to remove element from group (%s): %s", oldParent.ID, err)
+	}
+	if err := appendToArray(rawNewParent, "children", id); err != nil {
+		return fmt.Errorf("failed to add element to group (%s): %s", newParent.ID, err)
+	}
+
+	return p.reloadProj()
+}
+
+// RemoveFile removes the file reference with id from the project (and from every group listing it),
+// together with the build files referencing it from the targets' build phases.
+// The file itself is not deleted from the disk.
+func (p *XcodeProj) RemoveFile(id string) error {
+	element, ok := p.Proj.Element(id)
+	if !ok {
+		return fmt.Errorf("file not found: %s", id)
+	}
+	if _, ok := element.(*FileReference); !ok {
+		return fmt.Errorf("not a file reference: %s", id)
+	}
+
+	return p.removeElement(element)
+}
+
+// RemoveGroup removes the group with id and its whole subtree from the project (and from every group listing them),
+// together with the build files referencing any of the removed elements.
+func (p *XcodeProj) RemoveGroup(id string) error {
+	element, ok := p.Proj.Element(id)
+	if !ok {
+		return fmt.Errorf("group not found: %s", id)
+	}
+	if _, ok := element.(*Group); !ok {
+		return fmt.Errorf("not a group: %s", id)
+	}
+	if element.parentGroup() == nil {
+		return fmt.Errorf("the main group can not be removed")
+	}
+
+	return p.removeElement(element)
+}
+
+func (p *XcodeProj) removeElement(element Element) error {
+	objects, _, err := p.rawObjects()
+	if err != nil {
+		return err
+	}
+
+	removedIDs := map[string]bool{}
+	collectElementIDs(element, removedIDs)
+
+	// an element can be listed by more groups, it is removed from every group (not only from its parent group),
+	// like the removed group's descendants listed by groups outside of the removed subtree
+	var removedIDList []string
+	for id := range removedIDs {
+		removedIDList = append(removedIDList, id)
+	}
+	for id := range objects {
+		if removedIDs[id] {
+			continue
+		}
+		object, err := objects.Object(id)
+		if err != nil {
+			continue
+		}
+		if err := removeFromArray(object, "children", removedIDList...); err != nil {
+			return fmt.Errorf("failed to remove element from group (%s): %s", id, err)
+		}
+	}
+
+	if err := removeBuildFiles(objects, removedIDs); err != nil {
+		return err
+	}
+
+	for id := range removedIDs {
+		delete(objects, id)
+	}
+
+	return p.reloadProj()
+}
+
+// removeBuildFiles removes the build files referencing any of the given file tree elements from the build phases and the objects.
+func removeBuildFiles(objects serialized.Object, fileRefIDs map[string]bool) error {
+	var buildFileIDs []string
+	for id := range objects {
+		object, err := objects.Object(id)
+		if err != nil {
+			continue
+		}
+		if optionalString(object, "isa") == "PBXBuildFile" && fileRefIDs[optionalString(object, "fileRef")] {
+			buildFileIDs = append(buildFileIDs, id)
+		}
+	}
+	if len(buildFileIDs) == 0 {
+		return nil
+	}
+
+	for id := range objects {
+		object, err := objects.Object(id)
+		if err != nil || !strings.HasSuffix(optionalString(object, "isa"), "BuildPhase") {
+			continue
+		}
+		if err := removeFromArray(object, "files", buildFileIDs...); err != nil {
+			return fmt.Errorf("failed to remove build files from build phase (%s): %s", id, err)
+		}
+	}
+
+	for _, id := range buildFileIDs {
+		delete(objects, id)
+	}
+	return nil
+}
+
+// collectElementIDs collects the IDs of the element and its descendants.
+func collectElementIDs(element Element, ids map[string]bool) {
+	switch element := element.(type) {
+	case *FileReference:
+		ids[element.ID] = true
+	case *Group:
+		ids[element.ID] = true
+		for _, child := range element.Children {
+			collectElementIDs(child, ids)
+		}
+	}
+}
+
+// group returns the group with the given ID, the main group if id is empty.
+func (p XcodeProj) group(id string) (*Group, bool) {
+	if id == "" {
+		return p.Proj.MainGroup, p.Proj.MainGroup != nil
+	}
+
+	element, ok := p.Proj.Element(id)
+	if !ok {
+		return nil, false
+	}
+	group, ok := element.(*Group)
+	return group, ok
+}
+
+// rawGroup returns the raw group object with the given ID, the main group if id is empty.
+func (p XcodeProj) rawGroup(objects serialized.Object, id string) (serialized.Object, error) {
+	group, ok := p.group(id)
+	if !ok {
+		if id == "" {
+			return nil, fmt.Errorf("project has no main group")
+		}
+		return nil, fmt.Errorf("group not found: %s", id)
+	}
+
+	rawGroup, err := objects.Object(group.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access group (%s): %s", group.ID, err)
+	}
+	return rawGroup, nil
+}
@@ -0,0 +1,205 @@
+package xcodeproj
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXcodeProj_AddGroupAndFile(t *testing.T) {
+	pth := createProjectInTmpDir(t, "XcodeProj", testhelper.XcodeProjectTest, nil)
+	project, err := Open(pth)
+	require.NoError(t, err)
+
+	const appGroupID = "7D5B35FE20E28EE80022BAE6"
+
+	configGroup, err := project.AddGroup(appGroupID, "", "Config")
+	require.NoError(t, err)
+	require.Equal(t, "Config", configGroup.Path)
+	require.Equal(t, "", configGroup.Name)
+	require.Equal(t, appGroupID, configGroup.Parent().ID)
+
+	flavorsGroup, err := project.AddGroup("", "Flavors", "")
+	require.NoError(t, err)
+	require.Equal(t, "Flavors", flavorsGroup.Name)
+	require.Equal(t, "", flavorsGroup.Path)
+	require.Equal(t, project.Proj.MainGroup.ID, flavorsGroup.Parent().ID)
+
+	_, err = project.AddGroup("", "", "")
+	require.EqualError(t, err, "either name or path of the group is required")
+
+	_, err = project.AddGroup("NOT_EXISTING", "Group", "")
+	require.EqualError(t, err, "group not found: NOT_EXISTING")
+
+	plist, err := project.AddFile(configGroup.ID, "GoogleService-Info.plist")
+	require.NoError(t, err)
+	require.Equal(t, "text.plist.xml", plist.LastKnownFileType)
+	require.Equal(t, GroupSourceTree, plist.SourceTree)
+	require.Equal(t, "", plist.Name)
+
+	paths, err := project.SourceTreePaths()
+	require.NoError(t, err)
+	plistPth, err := plist.AbsPath(paths)
+	require.NoError(t, err)
+	require.Equal(t, filepath.Join(filepath.Dir(pth), "XcodeProj", "Config", "GoogleService-Info.plist"), plistPth)
+
+	xcconfig, err := project.AddFile(configGroup.ID, "Staging/Staging.xcconfig")
+	require.NoError(t, err)
+	require.Equal(t, "text.xcconfig", xcconfig.LastKnownFileType)
+	require.Equal(t, "Staging.xcconfig", xcconfig.Name)
+
+	_, err = project.AddFile(configGroup.ID, "GoogleService-Info.plist")
+	require.EqualError(t, err, "file already exists in group (Config): GoogleService-Info.plist")
+
+	buildFile, err := project.AddFileToTarget(plist.ID, "XcodeProj", ResourcesBuildPhaseType)
+	require.NoError(t, err)
+	require.Equal(t, plist.ID, buildFile.FileRef)
+
+	target, ok := project.Proj.TargetByName("XcodeProj")
+	require.True(t, ok)
+	resources := target.BuildPhasesOfType(ResourcesBuildPhaseType)[0]
+	require.Equal(t, buildFile, resources.Files[len(resources.Files)-1])
+
+	_, err = project.AddFileToTarget(plist.ID, "XcodeProj", ResourcesBuildPhaseType)
+	require.EqualError(t, err, "file ("+plist.ID+") is already in the Resources build phase of target (XcodeProj)")
+
+	_, err = project.AddFileToTarget(plist.ID, "XcodeProj", HeadersBuildPhaseType)
+	require.EqualError(t, err, "target (XcodeProj) has no PBXHeadersBuildPhase build phase")
+
+	t.Log("saved project")
+	{
+		require.NoError(t, project.Save())
+
+		content, err := ioutil.ReadFile(filepath.Join(pth, "project.pbxproj"))
+		require.NoError(t, err)
+		require.Contains(t, string(content), "\t\t"+buildFile.ID+" /* GoogleService-Info.plist in Resources */ = {isa = PBXBuildFile; fileRef = "+plist.ID+" /* GoogleService-Info.plist */; };\n")
+		require.Contains(t, string(content), "\t\t"+plist.ID+" /* GoogleService-Info.plist */ = {isa = PBXFileReference; lastKnownFileType = text.plist.xml; path = \"GoogleService-Info.plist\"; sourceTree = \"<group>\"; };\n")
+
+		reopened, err := Open(pth)
+		require.NoError(t, err)
+
+		element, ok := reopened.Proj.Element(plist.ID)
+		require.True(t, ok)
+		require.Equal(t, "Config", element.(*FileReference).Parent().Path)
+	}
+}
+
+func TestXcodeProj_MoveElement(t *testing.T) {
+	project, err := parsePBXProjContent([]byte(testhelper.XcodeProjectTest))
+	require.NoError(t, err)
+
+	const (
+		frameworksGroupID = "7D03430E20F4BB070050B6A6"
+		appGroupID        = "7D5B35FE20E28EE80022BAE6"
+		infoPlistID       = "7D5B360B20E28EEA0022BAE6"
+	)
+
+	require.NoError(t, project.MoveElement(infoPlistID, frameworksGroupID))
+
+	element, ok := project.Proj.Element(infoPlistID)
+	require.True(t, ok)
+	require.Equal(t, frameworksGroupID, element.(*FileReference).Parent().ID)
+
+	appGroup, ok := project.group(appGroupID)
+	require.True(t, ok)
+	for _, child := range appGroup.Children {
+		if fileReference, ok := child.(*FileReference); ok {
+			require.NotEqual(t, infoPlistID, fileReference.ID)
+		}
+	}
+
+	require.NoError(t, project.MoveElement(frameworksGroupID, appGroupID))
+	require.EqualError(t, project.MoveElement(appGroupID, frameworksGroupID), "group ("+appGroupID+") can not be moved into itself or into its descendant")
+	require.EqualError(t, project.MoveElement(project.Proj.MainGroup.ID, appGroupID), "the main group can not be moved")
+	require.EqualError(t, project.MoveElement("NOT_EXISTING", appGroupID), "element not found: NOT_EXISTING")
+}
+
+func TestXcodeProj_RemoveFile(t *testing.T) {
+	project, err := parsePBXProjContent([]byte(testhelper.XcodeProjectTest))
+	require.NoError(t, err)
+
+	const (
+		frameworksGroupID  = "7D03430E20F4BB070050B6A6"
+		cloudKitID         = "7D03432020F4BB8D0050B6A6"
+		cloudKitBuildFile  = "7D03432120F4BB8D0050B6A6"
+		notificationCenter = "7D03430F20F4BB070050B6A6"
+	)
+
+	require.NoError(t, project.RemoveFile(cloudKitID))
+
+	objects, err := project.RawProj.Object("objects")
+	require.NoError(t, err)
+	require.NotContains(t, objects, cloudKitID)
+	require.NotContains(t, objects, cloudKitBuildFile)
+
+	_, ok := project.Proj.Element(cloudKitID)
+	require.False(t, ok)
+	for _, target := range project.Proj.Targets {
+		for _, buildPhase := range target.BuildPhases {
+			for _, buildFile := range buildPhase.Files {
+				require.NotEqual(t, cloudKitBuildFile, buildFile.ID)
+			}
+		}
+	}
+
+	require.EqualError(t, project.RemoveFile(frameworksGroupID), "not a file reference: "+frameworksGroupID)
+
+	require.NoError(t, project.RemoveGroup(frameworksGroupID))
+	require.NotContains(t, objects, frameworksGroupID)
+	require.NotContains(t, objects, notificationCenter)
+	require.NotContains(t, objects, "7D03431020F4BB070050B6A6")
+
+	require.EqualError(t, project.RemoveGroup(project.Proj.MainGroup.ID), "the main group can not be removed")
+
+	_, err = project.perObjectModify()
+	require.NoError(t, err)
+
+	t.Log("element listed by more groups")
+	{
+		const appDelegateID = "7D5B35FF20E28EE80022BAE6"
+
+		// the main group lists AppDelegate.swift (child of the XcodeProj group) and NotificationCenter.framework (child of the Frameworks group) too
+		content := strings.Replace(testhelper.XcodeProjectTest, `				7D5B35FD20E28EE80022BAE6 /* Products */,
+			);`, `				7D5B35FD20E28EE80022BAE6 /* Products */,
+				`+appDelegateID+` /* AppDelegate.swift */,
+				`+notificationCenter+` /* NotificationCenter.framework */,
+			);`, 1)
+		project, err := parsePBXProjContent([]byte(content))
+		require.NoError(t, err)
+
+		require.NoError(t, project.RemoveFile(appDelegateID))
+		require.NoError(t, project.RemoveGroup(frameworksGroupID))
+
+		objects, err := project.RawProj.Object("objects")
+		require.NoError(t, err)
+		mainGroup, err := objects.Object(project.Proj.MainGroup.ID)
+		require.NoError(t, err)
+		children, err := mainGroup.StringSlice("children")
+		require.NoError(t, err)
+		require.NotContains(t, children, appDelegateID)
+		require.NotContains(t, children, notificationCenter)
+
+		_, ok := project.Proj.Element(appDelegateID)
+		require.False(t, ok)
+
+		_, err = project.perObjectModify()
+		require.NoError(t, err)
+	}
+}
+
+func Test_lastKnownFileType(t *testing.T) {
+	for pth, want := range map[string]string{
+		"AppDelegate.swift":        "sourcecode.swift",
+		"GoogleService-Info.plist": "text.plist.xml",
+		"Config/Release.XCCONFIG":  "text.xcconfig",
+		"Assets.xcassets":          "folder.assetcatalog",
+		"Firebase.xcframework":     "wrapper.xcframework",
+		"LICENSE":                  "file",
+	} {
+		require.Equal(t, want, lastKnownFileType(pth), pth)
+	}
+}
@@ -0,0 +1,157 @@
+package xcodeproj
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/go-plist"
+	"github.com/bitrise-io/xcode-project/serialized"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroup_FileReferences(t *testing.T) {
+	var raw serialized.Object
+	_, err := plist.Unmarshal([]byte(rawProj), &raw)
+	require.NoError(t, err)
+
+	proj, err := parseProj("BA3CBE6D19F7A93800CED4D5", raw)
+	require.NoError(t, err)
+	require.NotNil(t, proj.MainGroup)
+
+	var groupNames []string
+	for _, group := range proj.MainGroup.Groups() {
+		groupNames = append(groupNames, group.DisplayName())
+	}
+	require.Equal(t, []string{"ios-simple-objc", "ios-simple-objcTests", "Products"}, groupNames)
+
+	var fileNames []string
+	for _, fileReference := range proj.FileReferences() {
+		fileNames = append(fileNames, fileReference.DisplayName())
+	}
+	require.Equal(t, []string{
+		"AppDelegate.h", "AppDelegate.m", "ViewController.h", "ViewController.m", "Base", "Images.xcassets", "Base",
+		"ios_simple_objc.xcdatamodel", "Info.plist", "main.m", "ios_simple_objcTests.m", "Info.plist",
+		"ios-simple-objc.app", "ios-simple-objcTests.xctest",
+	}, fileNames)
+
+	element, ok := proj.Element("BA3CBE8619F7A93900CED4D5")
+	require.True(t, ok)
+	fileReference := element.(*FileReference)
+	require.Equal(t, "Base.lproj/Main.storyboard", fileReference.Path)
+	require.Equal(t, "file.storyboard", fileReference.FileType())
+	require.Equal(t, VariantGroupType, fileReference.Parent().Type)
+	require.Equal(t, "Main.storyboard", fileReference.Parent().Name)
+
+	_, ok = proj.Element("NOT_EXISTING")
+	require.False(t, ok)
+}
+
+func TestParseMainGroup_SharedAndCircularGroups(t *testing.T) {
+	objects := serialized.Object{
+		"MAIN":   map[string]interface{}{"isa": "PBXGroup", "children": []interface{}{"A", "B"}, "sourceTree": "<group>"},
+		"A":      map[string]interface{}{"isa": "PBXGroup", "name": "A", "children": []interface{}{"SHARED"}, "sourceTree": "<group>"},
+		"B":      map[string]interface{}{"isa": "PBXGroup", "name": "B", "children": []interface{}{"SHARED", "MAIN"}, "sourceTree": "<group>"},
+		"SHARED": map[string]interface{}{"isa": "PBXGroup", "name": "Shared", "children": []interface{}{"SHARED"}, "sourceTree": "<group>"},
+	}
+
+	mainGroup, err := parseMainGroup(serialized.Object{"mainGroup": "MAIN"}, objects)
+	require.NoError(t, err)
+
+	groups := mainGroup.Groups()
+	require.Equal(t, 2, len(groups))
+	for _, group := range groups {
+		// the shared group is parsed under both parents, its reference to itself and B's reference to the main group are skipped
+		children := group.Groups()
+		require.Equal(t, 1, len(children))
+		require.Equal(t, "Shared", children[0].DisplayName())
+		require.Equal(t, group, children[0].Parent())
+		require.Equal(t, 0, len(children[0].Children))
+	}
+}
+
+func TestElement_AbsPath(t *testing.T) {
+	mainGroup := &Group{Type: NormalGroupType, ID: "main", SourceTree: GroupSourceTree}
+	appGroup := &Group{Type: NormalGroupType, ID: "app", Path: "App", SourceTree: GroupSourceTree, parent: mainGroup}
+	supportingFiles := &Group{Type: NormalGroupType, ID: "supporting", Name: "Supporting Files", SourceTree: GroupSourceTree, parent: appGroup}
+	mainGroup.Children = []Element{appGroup}
+	appGroup.Children = []Element{supportingFiles}
+
+	paths := SourceTreePaths{
+		SourceRoot:       "/project",
+		BuiltProductsDir: "/derived_data/Build/Products/Debug-iphoneos",
+		SDKRoot:          "/Xcode.app/Contents/Developer/Platforms/iPhoneOS.platform/Developer/SDKs/iPhoneOS.sdk",
+		DeveloperDir:     "/Xcode.app/Contents/Developer",
+	}
+
+	tests := []struct {
+		name          string
+		fileReference *FileReference
+		want          string
+		wantErr       string
+	}{
+		{
+			name:          "group relative",
+			fileReference: &FileReference{Path: "Info.plist", SourceTree: GroupSourceTree, parent: supportingFiles},
+			want:          "/project/App/Info.plist",
+		},
+		{
+			name:          "absolute",
+			fileReference: &FileReference{Path: "/Users/bitrise/Shared/Config.xcconfig", SourceTree: AbsoluteSourceTree, parent: supportingFiles},
+			want:          "/Users/bitrise/Shared/Config.xcconfig",
+		},
+		{
+			name:          "source root relative",
+			fileReference: &FileReference{Path: "Pods/Target Support Files/Pods.xcconfig", SourceTree: SourceRootSourceTree, parent: appGroup},
+			want:          "/project/Pods/Target Support Files/Pods.xcconfig",
+		},
+		{
+			name:          "built products relative",
+			fileReference: &FileReference{Path: "App.app", SourceTree: BuiltProductsDirSourceTree, parent: mainGroup},
+			want:          "/derived_data/Build/Products/Debug-iphoneos/App.app",
+		},
+		{
+			name:          "SDK relative",
+			fileReference: &FileReference{Path: "System/Library/Frameworks/UIKit.framework", SourceTree: SDKRootSourceTree, parent: mainGroup},
+			want:          "/Xcode.app/Contents/Developer/Platforms/iPhoneOS.platform/Developer/SDKs/iPhoneOS.sdk/System/Library/Frameworks/UIKit.framework",
+		},
+		{
+			name:          "developer dir relative",
+			fileReference: &FileReference{Path: "Library/Frameworks/XCTest.framework", SourceTree: DeveloperDirSourceTree, parent: mainGroup},
+			want:          "/Xcode.app/Contents/Developer/Library/Frameworks/XCTest.framework",
+		},
+		{
+			name:          "unsupported source tree",
+			fileReference: &FileReference{Path: "File.swift", SourceTree: "CUSTOM_ROOT", parent: mainGroup},
+			wantErr:       "unsupported source tree: CUSTOM_ROOT",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.fileReference.AbsPath(paths)
+			if tt.wantErr != "" {
+				require.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	_, err := appGroup.AbsPath(SourceTreePaths{})
+	require.EqualError(t, err, "path of source tree (SOURCE_ROOT) is not set")
+}
+
+func TestXcodeProj_FileLocations(t *testing.T) {
+	pth := createProjectInTmpDir(t, "BaseConfig", pbxprojWithBaseConfigurations, nil)
+	project, err := Open(pth)
+	require.NoError(t, err)
+
+	locations, err := project.FileLocations()
+	require.NoError(t, err)
+
+	projectDir := filepath.Dir(pth)
+	require.Contains(t, locations, filepath.Join(projectDir, "App", "Info.plist"))
+	require.Contains(t, locations, filepath.Join(projectDir, "Configs", "Project.xcconfig"))
+	require.Contains(t, locations, filepath.Join(projectDir, "Configs", "App.xcconfig"))
+	require.NotContains(t, locations, filepath.Join(projectDir, "App.app"))
+}
@@ -0,0 +1,360 @@
+package xcodeproj
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/xcode-project/serialized"
+)
+
+// LintRule is the stable identifier of a project integrity check.
+type LintRule string
+
+// LintRules
+const (
+	// MissingObjectLintRule: an object ID is referenced, but the object does not exist.
+	MissingObjectLintRule LintRule = "missing-object"
+	// OrphanedObjectLintRule: the object is not reachable from the project's root object.
+	OrphanedObjectLintRule LintRule = "orphaned-object"
+	// DuplicateObjectIDLintRule: more than one object is defined with the same ID, only the last one is used.
+	DuplicateObjectIDLintRule LintRule = "duplicate-object-id"
+	// MissingFileLintRule: a file of a build phase does not exist on the disk.
+	MissingFileLintRule LintRule = "missing-file"
+	// SharedSourceFileLintRule: a file is compiled by more than one target of the same product type and platform (SDKROOT),
+	// like two apps of the same platform. Sharing files between an app and its extensions, tests or the app of an other platform is common, it is not reported.
+	SharedSourceFileLintRule LintRule = "shared-source-file"
+	// MissingConfigurationLintRule: a target has no build configuration with the name of a project level build configuration.
+	MissingConfigurationLintRule LintRule = "missing-configuration"
+	// DependencyCycleLintRule: targets depend on each other.
+	DependencyCycleLintRule LintRule = "dependency-cycle"
+)
+
+// LintSeverity ...
+type LintSeverity string
+
+// LintSeverities
+const (
+	ErrorLintSeverity   LintSeverity = "error"
+	WarningLintSeverity LintSeverity = "warning"
+)
+
+var lintRuleSeverities = map[LintRule]LintSeverity{
+	MissingObjectLintRule:        ErrorLintSeverity,
+	OrphanedObjectLintRule:       WarningLintSeverity,
+	DuplicateObjectIDLintRule:    ErrorLintSeverity,
+	MissingFileLintRule:          ErrorLintSeverity,
+	SharedSourceFileLintRule:     WarningLintSeverity,
+	MissingConfigurationLintRule: WarningLintSeverity,
+	DependencyCycleLintRule:      ErrorLintSeverity,
+}
+
+// referenceKeys are the keys holding an object ID or a list of object IDs.
+var referenceKeys = map[string]bool{
+	"baseConfigurationReference": true,
+	"buildConfigurationList":     true,
+	"buildConfigurations":        true,
+	"buildPhases":                true,
+	"buildRules":                 true,
+	"children":                   true,
+	"containerPortal":            true,
+	"currentVersion":             true,
+	"dependencies":               true,
+	"fileRef":                    true,
+	"files":                      true,
+	"mainGroup":                  true,
+	"package":                    true,
+	"packageProductDependencies": true,
+	"packageReferences":          true,
+	"ProductGroup":               true,
+	"productRef":                 true,
+	"productRefGroup":            true,
+	"productReference":           true,
+	"ProjectRef":                 true,
+	"remoteGlobalIDString":       true,
+	"target":                     true,
+	"targetProxy":                true,
+	"targets":                    true,
+}
+
+// objectReference is an object ID referenced by an object under the given key.
+type objectReference struct {
+	Key string
+	ID  string
+}
+
+// objectReferences returns the object IDs referenced by the object, in the order of its sorted keys.
+// The remote objects of cross-project references (which are in the other project) are skipped.
+func objectReferences(object serialized.Object, rootID string) []objectReference {
+	var references []objectReference
+	var visit func(key string, value interface{})
+	visit = func(key string, value interface{}) {
+		switch value := value.(type) {
+		case string:
+			if !referenceKeys[key] {
+				return
+			}
+			if key == "remoteGlobalIDString" && optionalString(object, "containerPortal") != rootID {
+				return
+			}
+			references = append(references, objectReference{Key: key, ID: value})
+		case []interface{}:
+			for _, item := range value {
+				visit(key, item)
+			}
+		case map[string]interface{}:
+			for _, k := range sortedKeys(value) {
+				if k != customAnnotationKey {
+					visit(k, value[k])
+				}
+			}
+		}
+	}
+	visit("", map[string]interface{}(object))
+	return references
+}
+
+// objectDefinitionPattern matches the first line of an object's definition in the objects section: `ID /* comment */ = {`.
+var objectDefinitionPattern = regexp.MustCompile(`(?m)^\t\t([0-9A-Za-z]+) (?:/\*.*?\*/ )?= \{`)
+
+// LintFinding is a structural problem of the project.
+type LintFinding struct {
+	Rule     LintRule
+	Severity LintSeverity
+	// Object is the ID of the object the finding belongs to.
+	Object string
+	// Path is the object's path in the project graph, like `PBXProject/targets/PBXNativeTarget:App/buildPhases/PBXSourcesBuildPhase`.
+	Path    string
+	Message string
+}
+
+// String ...
+func (f LintFinding) String() string {
+	return fmt.Sprintf("%s [%s] %s: %s", f.Severity, f.Rule, f.Path, f.Message)
+}
+
+// Lint checks the integrity of the project and returns the structural problems found.
+// The files of the build phases are checked on the disk only if the project was opened from a path.
+func (p XcodeProj) Lint() ([]LintFinding, error) {
+	objects, err := p.RawProj.Object("objects")
+	if err != nil {
+		return nil, fmt.Errorf("failed to access objects: %s", err)
+	}
+
+	l := linter{
+		objects: objects,
+		rootID:  p.Proj.ID,
+		paths:   objectPaths(objects, p.Proj.ID),
+	}
+
+	l.checkDuplicateObjectIDs(p.originalContents)
+	l.checkReferences()
+	l.checkOrphanedObjects()
+	if p.Path != "" {
+		if err := l.checkMissingFiles(p); err != nil {
+			return nil, err
+		}
+	}
+	l.checkSharedSourceFiles(p)
+	l.checkConfigurations(p.Proj)
+	if err := l.checkDependencyCycles(p); err != nil {
+		return nil, err
+	}
+
+	return l.findings, nil
+}
+
+type linter struct {
+	objects  serialized.Object
+	rootID   string
+	paths    map[string]string
+	findings []LintFinding
+}
+
+func (l *linter) report(rule LintRule, id, message string) {
+	l.findings = append(l.findings, LintFinding{
+		Rule:     rule,
+		Severity: lintRuleSeverities[rule],
+		Object:   id,
+		Path:     l.path(id),
+		Message:  message,
+	})
+}
+
+// path returns the object's path in the project graph, the label of the object if it is not reachable.
+func (l linter) path(id string) string {
+	if pth, ok := l.paths[id]; ok {
+		return pth
+	}
+	if label := objectLabel(l.objects, id); label != "" {
+		return label
+	}
+	return id
+}
+
+func (l *linter) checkDuplicateObjectIDs(content []byte) {
+	counts := map[string]int{}
+	var ids []string
+	for _, match := range objectDefinitionPattern.FindAllSubmatch(content, -1) {
+		id := string(match[1])
+		if counts[id] == 1 {
+			ids = append(ids, id)
+		}
+		counts[id]++
+	}
+
+	sort.Strings(ids)
+	for _, id := range ids {
+		l.report(DuplicateObjectIDLintRule, id, fmt.Sprintf("object ID is defined %d times", counts[id]))
+	}
+}
+
+func (l *linter) checkReferences() {
+	for _, id := range sortedKeys(l.objects) {
+		object, err := l.objects.Object(id)
+		if err != nil {
+			continue
+		}
+
+		for _, reference := range objectReferences(object, l.rootID) {
+			if _, err := l.objects.Object(reference.ID); err != nil {
+				l.report(MissingObjectLintRule, id, fmt.Sprintf("referenced object does not exist: %s (%s)", reference.ID, reference.Key))
+			}
+		}
+	}
+}
+
+func (l *linter) checkOrphanedObjects() {
+	for _, id := range sortedKeys(l.objects) {
+		if _, ok := l.paths[id]; ok {
+			continue
+		}
+		l.report(OrphanedObjectLintRule, id, "object is not reachable from the project")
+	}
+}
+
+func (l *linter) checkMissingFiles(p XcodeProj) error {
+	paths, err := p.SourceTreePaths()
+	if err != nil {
+		return err
+	}
+
+	checked := map[string]bool{}
+	for _, target := range p.Proj.Targets {
+		for _, buildPhase := range target.BuildPhases {
+			for _, buildFile := range buildPhase.Files {
+				if buildFile.FileRef == "" || checked[buildFile.FileRef] {
+					continue
+				}
+				checked[buildFile.FileRef] = true
+
+				element, ok := p.Proj.Element(buildFile.FileRef)
+				if !ok {
+					continue
+				}
+				fileReference, ok := element.(*FileReference)
+				if !ok {
+					continue
+				}
+				switch fileReference.SourceTree {
+				case BuiltProductsDirSourceTree, SDKRootSourceTree, DeveloperDirSourceTree:
+					continue
+				}
+
+				pth, err := fileReference.AbsPath(paths)
+				if err != nil {
+					return fmt.Errorf("failed to resolve path of file reference (%s): %s", fileReference.ID, err)
+				}
+				if exist, err := pathutil.IsPathExists(pth); err != nil {
+					return err
+				} else if !exist {
+					l.report(MissingFileLintRule, fileReference.ID, fmt.Sprintf("file of target (%s) does not exist: %s", target.Name, pth))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// checkSharedSourceFiles reports the files compiled by more than one target of the same product type and platform.
+func (l *linter) checkSharedSourceFiles(p XcodeProj) {
+	type targetKind struct {
+		productType, platform string
+	}
+
+	targetsByFile := map[string]map[targetKind][]string{}
+	for _, target := range p.Proj.Targets {
+		kind := targetKind{productType: target.ProductType, platform: p.targetPlatform(target)}
+
+		for _, buildPhase := range target.BuildPhasesOfType(SourcesBuildPhaseType) {
+			for _, buildFile := range buildPhase.Files {
+				if buildFile.FileRef == "" {
+					continue
+				}
+				if targetsByFile[buildFile.FileRef] == nil {
+					targetsByFile[buildFile.FileRef] = map[targetKind][]string{}
+				}
+				targetsByFile[buildFile.FileRef][kind] = append(targetsByFile[buildFile.FileRef][kind], target.Name)
+			}
+		}
+	}
+
+	var ids []string
+	for id := range targetsByFile {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	for _, id := range ids {
+		var shared []string
+		for _, targets := range targetsByFile[id] {
+			if len(targets) > 1 {
+				shared = append(shared, strings.Join(targets, ", "))
+			}
+		}
+		sort.Strings(shared)
+
+		for _, targets := range shared {
+			l.report(SharedSourceFileLintRule, id, fmt.Sprintf("file is compiled by multiple targets: %s", targets))
+		}
+	}
+}
+
+// targetPlatform returns the SDKROOT of the target's default configuration, empty if it can not be resolved.
+func (p XcodeProj) targetPlatform(target Target) string {
+	buildSettings, err := p.ResolveTargetBuildSettings(target.Name, "")
+	if err != nil {
+		return ""
+	}
+	return optionalString(buildSettings, "SDKROOT")
+}
+
+func (l *linter) checkConfigurations(proj Proj) {
+	for _, target := range proj.Targets {
+		for _, buildConfiguration := range proj.BuildConfigurationList.BuildConfigurations {
+			if _, ok := buildConfigurationByName(target.BuildConfigurationList, buildConfiguration.Name); ok {
+				continue
+			}
+			l.report(MissingConfigurationLintRule, target.ID, fmt.Sprintf("target (%s) has no build configuration: %s", target.Name, buildConfiguration.Name))
+		}
+	}
+}
+
+// checkDependencyCycles reports the targets depending on each other.
+func (l *linter) checkDependencyCycles(p XcodeProj) error {
+	graph, err := p.DependencyGraph()
+	if err != nil {
+		return err
+	}
+
+	for _, cycle := range graph.Cycles() {
+		var names []string
+		for _, target := range cycle {
+			names = append(names, target.Name)
+		}
+		l.report(DependencyCycleLintRule, cycle[0].ID, fmt.Sprintf("targets depend on each other: %s", strings.Join(names, ", ")))
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package xcodeproj
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXcodeProj_Lint(t *testing.T) {
+	t.Log("valid project")
+	{
+		project, err := parsePBXProjContent([]byte(testhelper.XcodeProjectTest))
+		require.NoError(t, err)
+
+		findings, err := project.Lint()
+		require.NoError(t, err)
+		require.Equal(t, 0, len(findings))
+	}
+
+	content := testhelper.XcodeProjectTest
+	// TodayExtension depends on XcodeProj, which embeds TodayExtension
+	content = strings.Replace(content, `			dependencies = (
+			);
+			name = TodayExtension;`, `			dependencies = (
+				7D0342F720F4BA280050B6A6 /* PBXTargetDependency */,
+			);
+			name = TodayExtension;`, 1)
+	// the same build file is defined twice
+	buildFile := "\t\t7D03431020F4BB070050B6A6 /* NotificationCenter.framework in Frameworks */ = {isa = PBXBuildFile; fileRef = 7D03430F20F4BB070050B6A6 /* NotificationCenter.framework */; };\n"
+	content = strings.Replace(content, buildFile, buildFile+buildFile, 1)
+
+	project, err := parsePBXProjContent([]byte(content))
+	require.NoError(t, err)
+
+	objects, _, err := project.rawObjects()
+	require.NoError(t, err)
+
+	// TodayExtension compiles the AppDelegate of XcodeProj, which is common, but XcodeProjLite (an app of the same platform) too
+	todayExtensionSources, err := objects.Object("7D03430920F4BB070050B6A6")
+	require.NoError(t, err)
+	require.NoError(t, appendToArray(todayExtensionSources, "files", "7D5B360020E28EE80022BAE6"))
+	_, err = project.AddTarget("XcodeProjLite", ApplicationProductType)
+	require.NoError(t, err)
+	_, err = project.AddFileToTarget("7D5B35FF20E28EE80022BAE6", "XcodeProjLite", SourcesBuildPhaseType)
+	require.NoError(t, err)
+
+	// TodayExtension has no Release configuration
+	todayExtension, ok := project.Proj.TargetByName("TodayExtension")
+	require.True(t, ok)
+	release, ok := buildConfigurationByName(todayExtension.BuildConfigurationList, "Release")
+	require.True(t, ok)
+	configurationList, err := objects.Object(todayExtension.BuildConfigurationList.ID)
+	require.NoError(t, err)
+	require.NoError(t, removeFromArray(configurationList, "buildConfigurations", release.ID))
+	require.NoError(t, project.reloadProj())
+
+	// references to a missing file and an unreferenced file
+	group, err := objects.Object("7D5B35FE20E28EE80022BAE6")
+	require.NoError(t, err)
+	require.NoError(t, appendToArray(group, "children", "0123456789ABCDEF01234567"))
+	objects["0123456789ABCDEF0123ABCD"] = map[string]interface{}{"isa": "PBXFileReference", "path": "Unused.swift", "sourceTree": "<group>"}
+
+	findings, err := project.Lint()
+	require.NoError(t, err)
+
+	var lines []string
+	for _, finding := range findings {
+		lines = append(lines, finding.String())
+	}
+	require.Equal(t, []string{
+		"error [duplicate-object-id] PBXProject/targets/PBXNativeTarget:TodayExtension/buildPhases/PBXFrameworksBuildPhase/files/PBXBuildFile:NotificationCenter.framework: object ID is defined 2 times",
+		"error [missing-object] PBXProject/mainGroup/PBXGroup/children/PBXGroup:XcodeProj: referenced object does not exist: 0123456789ABCDEF01234567 (children)",
+		"warning [orphaned-object] PBXFileReference:Unused.swift: object is not reachable from the project",
+		"warning [orphaned-object] XCBuildConfiguration:Release: object is not reachable from the project",
+		"warning [shared-source-file] PBXProject/mainGroup/PBXGroup/children/PBXGroup:XcodeProj/children/PBXFileReference:AppDelegate.swift: file is compiled by multiple targets: XcodeProj, XcodeProjLite",
+		"warning [missing-configuration] PBXProject/targets/PBXNativeTarget:TodayExtension: target (TodayExtension) has no build configuration: Release",
+		"error [dependency-cycle] PBXProject/targets/PBXNativeTarget:TodayExtension: targets depend on each other: TodayExtension, XcodeProj",
+	}, lines)
+	require.Equal(t, "0123456789ABCDEF0123ABCD", findings[2].Object)
+	require.Equal(t, ErrorLintSeverity, findings[0].Severity)
+	require.Equal(t, OrphanedObjectLintRule, findings[2].Rule)
+
+	t.Log("missing files")
+	{
+		pth := createProjectInTmpDir(t, "XcodeProj", testhelper.XcodeProjectTest, map[string]string{
+			filepath.Join("XcodeProj", "AppDelegate.swift"): "",
+		})
+		project, err := Open(pth)
+		require.NoError(t, err)
+
+		findings, err := project.Lint()
+		require.NoError(t, err)
+
+		var missing []string
+		for _, finding := range findings {
+			require.Equal(t, MissingFileLintRule, finding.Rule)
+			missing = append(missing, filepath.Base(strings.TrimPrefix(finding.Message, "file of target")))
+		}
+		require.Contains(t, missing, "ViewController.swift")
+		require.NotContains(t, missing, "AppDelegate.swift")
+	}
+}
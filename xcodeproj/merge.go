@@ -0,0 +1,479 @@
+package xcodeproj
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/log"
+	"github.com/bitrise-io/xcode-project/serialized"
+)
+
+// MergeConflictType ...
+type MergeConflictType string
+
+// MergeConflictTypes
+const (
+	// ModifiedMergeConflict: the value is changed differently on both sides.
+	ModifiedMergeConflict MergeConflictType = "modified"
+	// RemovedMergeConflict: the value is removed on one side and modified on the other.
+	RemovedMergeConflict MergeConflictType = "removed"
+	// DanglingReferenceMergeConflict: the merged project references an object removed on one side,
+	// like a build file added on one side for a file removed on the other.
+	DanglingReferenceMergeConflict MergeConflictType = "dangling-reference"
+)
+
+// MergeConflict is a value changed differently on both sides of a merge,
+// or a reference to an object removed by the merge.
+type MergeConflict struct {
+	Type MergeConflictType
+	// Object is the ID of the conflicting object, empty if the conflict is outside of the objects.
+	Object string
+	// Key is the key path of the conflicting value inside the object, like `buildSettings.SWIFT_VERSION`,
+	// empty if the whole object conflicts (removed on one side, modified on the other).
+	Key                string
+	Base, Ours, Theirs interface{}
+	// Reference is the ID of the removed object referenced by a DanglingReferenceMergeConflict.
+	Reference string
+}
+
+// String ...
+func (c MergeConflict) String() string {
+	location := c.Object
+	if c.Key != "" {
+		if location != "" {
+			location += " "
+		}
+		location += c.Key
+	}
+	if c.Type == DanglingReferenceMergeConflict {
+		return fmt.Sprintf("%s: references removed object: %s", location, c.Reference)
+	}
+	return fmt.Sprintf("%s: base: %s, ours: %s, theirs: %s", location, mergeValueText(c.Base), mergeValueText(c.Ours), mergeValueText(c.Theirs))
+}
+
+func mergeValueText(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return "(none)"
+	case string:
+		return quote(value)
+	case []interface{}:
+		var items []string
+		for _, item := range value {
+			items = append(items, mergeValueText(item))
+		}
+		return "(" + strings.Join(items, ", ") + ")"
+	default:
+		if _, ok := asMergeMap(value); ok {
+			return "{...}"
+		}
+		return fmt.Sprintf("%v", value)
+	}
+}
+
+// MergePBXProj merges the changes made on two sides (ours and theirs) of a project.pbxproj
+// since their common ancestor (base), object by object:
+// objects added or removed on one side are added or removed, changed keys of an object are merged key by key,
+// dictionaries (like buildSettings) are merged recursively and independent changes of lists
+// (like children, files, targets or OTHER_LDFLAGS) are merged the same way as diff3 merges lines, see mergeList.
+//
+// A value changed differently on both sides is a conflict, the merged content keeps our side of it,
+// see MarkMergeConflicts to mark the conflicts in the merged content.
+// The references of the merged objects to an object removed on one side are conflicts too.
+// The merged content is written in-place into our content, so the unchanged objects keep their formatting.
+// projectName is used to annotate the project's configuration list, if it is modified.
+func MergePBXProj(projectName string, base, ours, theirs []byte) ([]byte, []MergeConflict, error) {
+	baseProj, err := parsePBXProjContent(base)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse base project: %s", err)
+	}
+	oursProj, err := parsePBXProjContent(ours)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse our project: %s", err)
+	}
+	theirsProj, err := parsePBXProjContent(theirs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse their project: %s", err)
+	}
+
+	merged, conflicts := mergeValue(nil, baseProj.RawProj, oursProj.RawProj, theirsProj.RawProj)
+	rawMerged, ok := asMergeMap(merged)
+	if !ok {
+		return nil, nil, fmt.Errorf("failed to merge projects: root object removed")
+	}
+	conflicts = append(conflicts, danglingReferenceConflicts(rawMerged, baseProj.RawProj, oursProj.RawProj, theirsProj.RawProj)...)
+
+	proj := *oursProj
+	proj.Name = projectName
+	proj.RawProj = serialized.Object(rawMerged)
+	if err := proj.reloadProj(); err != nil {
+		return nil, nil, err
+	}
+
+	content, err := proj.mergedContent()
+	if err != nil {
+		return nil, nil, err
+	}
+	return content, conflicts, nil
+}
+
+// danglingReferenceConflicts returns the references of the merged objects to the objects,
+// which exist on one of the sides, but were removed by the merge.
+func danglingReferenceConflicts(merged map[string]interface{}, sides ...serialized.Object) []MergeConflict {
+	mergedObjects, err := serialized.Object(merged).Object("objects")
+	if err != nil {
+		return nil
+	}
+
+	removed := map[string]bool{}
+	for _, side := range sides {
+		objects, err := side.Object("objects")
+		if err != nil {
+			continue
+		}
+		for id := range objects {
+			if _, ok := mergedObjects[id]; !ok {
+				removed[id] = true
+			}
+		}
+	}
+
+	rootID := optionalString(merged, "rootObject")
+	var conflicts []MergeConflict
+	for _, id := range sortedKeys(mergedObjects) {
+		object, err := mergedObjects.Object(id)
+		if err != nil {
+			continue
+		}
+		for _, reference := range objectReferences(object, rootID) {
+			if removed[reference.ID] {
+				conflicts = append(conflicts, MergeConflict{Type: DanglingReferenceMergeConflict, Object: id, Key: reference.Key, Reference: reference.ID})
+			}
+		}
+	}
+	return conflicts
+}
+
+// MarkMergeConflicts marks the objects with a modified or removed conflict in the merged content (see MergePBXProj)
+// the same way as git marks the conflicting lines: the object's merged definition (keeping our side of the conflicts)
+// and its definition in their content are written between conflict markers.
+// The part of a side, which removed the object, is empty.
+// Dangling references and the conflicts outside of the objects are not marked.
+func MarkMergeConflicts(merged, theirs []byte, conflicts []MergeConflict) []byte {
+	marked := map[string]bool{}
+	for _, conflict := range conflicts {
+		if conflict.Type == DanglingReferenceMergeConflict || conflict.Object == "" || marked[conflict.Object] {
+			continue
+		}
+		marked[conflict.Object] = true
+
+		var theirsDefinition []byte
+		if start, end, ok := objectDefinition(theirs, conflict.Object); ok {
+			theirsDefinition = theirs[start:end]
+		}
+
+		start, end, ok := objectDefinition(merged, conflict.Object)
+		if !ok {
+			// removed on our side: the markers are placed at the end of the object's section
+			isa := ""
+			if object, ok := asMergeMap(conflict.Theirs); ok {
+				isa, _ = object["isa"].(string)
+			}
+			start = bytes.Index(merged, []byte("/* End "+isa+" section */"))
+			if isa == "" || start == -1 {
+				continue
+			}
+			end = start
+		}
+
+		var b bytes.Buffer
+		b.Write(merged[:start])
+		b.WriteString("<<<<<<< ours\n")
+		b.Write(merged[start:end])
+		b.WriteString("=======\n")
+		b.Write(theirsDefinition)
+		b.WriteString(">>>>>>> theirs\n")
+		b.Write(merged[end:])
+		merged = b.Bytes()
+	}
+	return merged
+}
+
+// objectDefinition returns the position of the object's definition in the objects section,
+// from the beginning of its first line until the end of its last line (including the line break).
+func objectDefinition(content []byte, id string) (int, int, bool) {
+	pattern := regexp.MustCompile(`(?m)^\t\t` + regexp.QuoteMeta(id) + ` (?:/\*.*?\*/ )?= \{`)
+	loc := pattern.FindIndex(content)
+	if loc == nil {
+		return 0, 0, false
+	}
+
+	lineEnd := bytes.IndexByte(content[loc[1]:], '\n')
+	if lineEnd == -1 {
+		return 0, 0, false
+	}
+	lineEnd += loc[1] + 1
+	if bytes.HasSuffix(bytes.TrimRight(content[loc[0]:lineEnd], "\n"), []byte("};")) {
+		// single line definition
+		return loc[0], lineEnd, true
+	}
+
+	closing := []byte("\n\t\t};\n")
+	end := bytes.Index(content[loc[1]:], closing)
+	if end == -1 {
+		return 0, 0, false
+	}
+	return loc[0], loc[1] + end + len(closing), true
+}
+
+// mergedContent writes the merged project in-place, if only its objects changed compared to our side,
+// otherwise (or if the in-place modification fails) the whole project is written.
+func (p XcodeProj) mergedContent() ([]byte, error) {
+	inPlace := true
+	for _, key := range sortedKeys(p.RawProj) {
+		if key != "objects" && !reflect.DeepEqual(p.RawProj[key], p.originalPbxProj[key]) {
+			inPlace = false
+		}
+	}
+	for _, key := range sortedKeys(p.originalPbxProj) {
+		if _, ok := p.RawProj[key]; !ok {
+			inPlace = false
+		}
+	}
+
+	if inPlace {
+		content, err := p.perObjectModify()
+		if err == nil {
+			return content, nil
+		}
+		log.Warnf("failed to modify project in-place: %v", err)
+	}
+
+	content, err := p.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal .pbxproj: %v", err)
+	}
+	return content, nil
+}
+
+// mergeValue three-way merges a value, nil means the value does not exist on the given side.
+// The merged value is nil if it was removed.
+func mergeValue(keyPath []string, base, ours, theirs interface{}) (interface{}, []MergeConflict) {
+	switch {
+	case reflect.DeepEqual(ours, theirs):
+		return ours, nil
+	case reflect.DeepEqual(base, ours):
+		return theirs, nil
+	case reflect.DeepEqual(base, theirs):
+		return ours, nil
+	}
+
+	oursMap, oursIsMap := asMergeMap(ours)
+	theirsMap, theirsIsMap := asMergeMap(theirs)
+	baseMap, baseIsMap := asMergeMap(base)
+	if oursIsMap && theirsIsMap && (baseIsMap || base == nil) {
+		return mergeMap(keyPath, baseMap, oursMap, theirsMap)
+	}
+
+	oursList, oursIsList := ours.([]interface{})
+	theirsList, theirsIsList := theirs.([]interface{})
+	baseList, baseIsList := base.([]interface{})
+	if oursIsList && theirsIsList && (baseIsList || base == nil) {
+		if merged, ok := mergeList(baseList, oursList, theirsList); ok {
+			return merged, nil
+		}
+	}
+
+	return ours, []MergeConflict{newMergeConflict(keyPath, base, ours, theirs)}
+}
+
+func mergeMap(keyPath []string, base, ours, theirs map[string]interface{}) (interface{}, []MergeConflict) {
+	keys := map[string]bool{}
+	for _, object := range []map[string]interface{}{base, ours, theirs} {
+		for key := range object {
+			keys[key] = true
+		}
+	}
+
+	var sorted []string
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+
+	merged := map[string]interface{}{}
+	var conflicts []MergeConflict
+	for _, key := range sorted {
+		if key == customAnnotationKey {
+			// the position of the object in our content
+			if value, ok := ours[key]; ok {
+				merged[key] = value
+			}
+			continue
+		}
+
+		value, valueConflicts := mergeValue(append(keyPath[:len(keyPath):len(keyPath)], key), base[key], ours[key], theirs[key])
+		conflicts = append(conflicts, valueConflicts...)
+		if value != nil {
+			merged[key] = value
+		}
+	}
+	return merged, conflicts
+}
+
+// mergeList merges lists of strings the same way as diff3 merges lines, so repeated items
+// (like the `-framework` flags of OTHER_LDFLAGS) and reordered items are merged by their positions:
+// the base items kept by both sides (by the longest common subsequences) are the stable items,
+// the items between them are taken from the side, which changed them.
+// If both sides only added items between the same stable items (like two new files of a group),
+// our items are followed by theirs.
+// If the list does not exist in base (nil), the items common to both sides are the base.
+// It returns false if an item is not a string, or if both sides changed the items between the same stable items differently.
+func mergeList(base, ours, theirs []interface{}) ([]interface{}, bool) {
+	for _, list := range [][]interface{}{base, ours, theirs} {
+		for _, item := range list {
+			if _, ok := item.(string); !ok {
+				return nil, false
+			}
+		}
+	}
+
+	if base == nil {
+		common := commonSubsequence(ours, theirs)
+		base = []interface{}{}
+		for i, item := range ours {
+			if _, ok := common[i]; ok {
+				base = append(base, item)
+			}
+		}
+	}
+
+	inOurs := commonSubsequence(base, ours)
+	inTheirs := commonSubsequence(base, theirs)
+
+	merged := []interface{}{}
+	baseStart, oursStart, theirsStart := 0, 0, 0
+	for i := 0; i <= len(base); i++ {
+		oursEnd, theirsEnd := len(ours), len(theirs)
+		if i < len(base) {
+			var kept bool
+			if oursEnd, kept = inOurs[i]; !kept {
+				continue
+			}
+			if theirsEnd, kept = inTheirs[i]; !kept {
+				continue
+			}
+		}
+
+		chunk, ok := mergeListChunk(base[baseStart:i], ours[oursStart:oursEnd], theirs[theirsStart:theirsEnd])
+		if !ok {
+			return nil, false
+		}
+		merged = append(merged, chunk...)
+		if i < len(base) {
+			merged = append(merged, base[i])
+		}
+
+		baseStart, oursStart, theirsStart = i+1, oursEnd+1, theirsEnd+1
+	}
+
+	return merged, true
+}
+
+// mergeListChunk merges the items between two stable items of the lists (see mergeList).
+func mergeListChunk(base, ours, theirs []interface{}) ([]interface{}, bool) {
+	switch {
+	case equalItems(base, ours):
+		return theirs, true
+	case equalItems(base, theirs), equalItems(ours, theirs):
+		return ours, true
+	case len(base) == 0:
+		// additions on both sides, the same item added on both sides would be duplicated
+		for _, item := range theirs {
+			for _, ourItem := range ours {
+				if item == ourItem {
+					return nil, false
+				}
+			}
+		}
+		return append(append([]interface{}{}, ours...), theirs...), true
+	default:
+		return nil, false
+	}
+}
+
+func equalItems(a, b []interface{}) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// commonSubsequence returns the positions of the items of a longest common subsequence of the lists:
+// the position in b by the position in a.
+func commonSubsequence(a, b []interface{}) map[int]int {
+	// lengths[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lengths := make([][]int, len(a)+1)
+	for i := range lengths {
+		lengths[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lengths[i][j] = lengths[i+1][j+1] + 1
+			} else if lengths[i+1][j] >= lengths[i][j+1] {
+				lengths[i][j] = lengths[i+1][j]
+			} else {
+				lengths[i][j] = lengths[i][j+1]
+			}
+		}
+	}
+
+	positions := map[int]int{}
+	for i, j := 0, 0; i < len(a) && j < len(b); {
+		switch {
+		case a[i] == b[j]:
+			positions[i] = j
+			i++
+			j++
+		case lengths[i+1][j] >= lengths[i][j+1]:
+			i++
+		default:
+			j++
+		}
+	}
+	return positions
+}
+
+func asMergeMap(value interface{}) (map[string]interface{}, bool) {
+	switch value := value.(type) {
+	case serialized.Object:
+		return value, true
+	case map[string]interface{}:
+		return value, true
+	default:
+		return nil, false
+	}
+}
+
+func newMergeConflict(keyPath []string, base, ours, theirs interface{}) MergeConflict {
+	conflict := MergeConflict{Type: ModifiedMergeConflict, Base: base, Ours: ours, Theirs: theirs}
+	if ours == nil || theirs == nil {
+		conflict.Type = RemovedMergeConflict
+	}
+	if len(keyPath) >= 2 && keyPath[0] == "objects" {
+		conflict.Object = keyPath[1]
+		keyPath = keyPath[2:]
+	}
+	conflict.Key = strings.Join(keyPath, ".")
+	return conflict
+}
@@ -0,0 +1,270 @@
+package xcodeproj
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func fileNames(t *testing.T, project *XcodeProj, groupID string) []string {
+	group, ok := project.group(groupID)
+	require.True(t, ok)
+
+	var names []string
+	for _, child := range group.Children {
+		if fileReference, ok := child.(*FileReference); ok {
+			names = append(names, fileReference.DisplayName())
+		}
+	}
+	return names
+}
+
+func TestMergePBXProj(t *testing.T) {
+	const groupID = "7D5B35FE20E28EE80022BAE6"
+	base := []byte(testhelper.XcodeProjectTest)
+
+	ours, err := parsePBXProjContent(base)
+	require.NoError(t, err)
+	file, err := ours.AddFile(groupID, "Ours.swift")
+	require.NoError(t, err)
+	_, err = ours.AddFileToTarget(file.ID, "XcodeProj", SourcesBuildPhaseType)
+	require.NoError(t, err)
+	_, err = ours.AddTarget("Widget", AppExtensionProductType)
+	require.NoError(t, err)
+	require.NoError(t, ours.AppendBuildSetting("XcodeProj", "Debug", "OTHER_LDFLAGS", "-ObjC"))
+	oursContent, err := ours.perObjectModify()
+	require.NoError(t, err)
+
+	theirs, err := parsePBXProjContent(base)
+	require.NoError(t, err)
+	file, err = theirs.AddFile(groupID, "Theirs.swift")
+	require.NoError(t, err)
+	_, err = theirs.AddFileToTarget(file.ID, "XcodeProj", SourcesBuildPhaseType)
+	require.NoError(t, err)
+	require.NoError(t, theirs.AppendBuildSetting("XcodeProj", "Debug", "OTHER_LDFLAGS", "-lz"))
+	require.NoError(t, theirs.SetBuildSetting("XcodeProj", "Release", "SWIFT_VERSION", NewStringBuildSettingValue("5.0")))
+	theirsContent, err := theirs.perObjectModify()
+	require.NoError(t, err)
+
+	t.Log("independent changes")
+	{
+		content, conflicts, err := MergePBXProj("XcodeProj", base, oursContent, theirsContent)
+		require.NoError(t, err)
+		require.Equal(t, 0, len(conflicts))
+
+		merged, err := parsePBXProjContent(content)
+		require.NoError(t, err)
+
+		require.Equal(t, []string{"AppDelegate.swift", "ViewController.swift", "Assets.xcassets", "Info.plist", "Ours.swift", "Theirs.swift"}, fileNames(t, merged, groupID))
+
+		_, ok := merged.Proj.TargetByName("Widget")
+		require.True(t, ok)
+
+		target, ok := merged.Proj.TargetByName("XcodeProj")
+		require.True(t, ok)
+		var sources []string
+		for _, buildFile := range target.BuildPhasesOfType(SourcesBuildPhaseType)[0].Files {
+			sources = append(sources, elementTreePath(merged.Proj, buildFile.FileRef))
+		}
+		require.Equal(t, []string{"XcodeProj/ViewController.swift", "XcodeProj/AppDelegate.swift", "XcodeProj/Ours.swift", "XcodeProj/Theirs.swift"}, sources)
+
+		value, _, err := merged.BuildSetting("XcodeProj", "Debug", "OTHER_LDFLAGS")
+		require.NoError(t, err)
+		require.Equal(t, NewListBuildSettingValue("$(inherited)", "-ObjC", "-lz"), value)
+		value, _, err = merged.BuildSetting("XcodeProj", "Release", "SWIFT_VERSION")
+		require.NoError(t, err)
+		require.Equal(t, NewStringBuildSettingValue("5.0"), value)
+
+		require.Contains(t, string(content), "/* Begin PBXBuildFile section */")
+	}
+
+	t.Log("conflicting changes")
+	{
+		require.NoError(t, ours.SetBuildSetting("XcodeProj", "Release", "SWIFT_VERSION", NewStringBuildSettingValue("5.1")))
+		oursContent, err := ours.perObjectModify()
+		require.NoError(t, err)
+
+		content, conflicts, err := MergePBXProj("XcodeProj", base, oursContent, theirsContent)
+		require.NoError(t, err)
+		require.Equal(t, []MergeConflict{
+			{Type: ModifiedMergeConflict, Object: "7D5B361020E28EEA0022BAE6", Key: "buildSettings.SWIFT_VERSION", Base: "4.0", Ours: "5.1", Theirs: "5.0"},
+		}, conflicts)
+		require.Equal(t, `7D5B361020E28EEA0022BAE6 buildSettings.SWIFT_VERSION: base: 4.0, ours: 5.1, theirs: 5.0`, conflicts[0].String())
+
+		merged, err := parsePBXProjContent(content)
+		require.NoError(t, err)
+		value, _, err := merged.BuildSetting("XcodeProj", "Release", "SWIFT_VERSION")
+		require.NoError(t, err)
+		require.Equal(t, NewStringBuildSettingValue("5.1"), value)
+	}
+}
+
+func TestMergePBXProj_RemovedObjects(t *testing.T) {
+	const fileID = "7D5B360120E28EE80022BAE6"
+	base := []byte(testhelper.XcodeProjectTest)
+
+	ours, err := parsePBXProjContent(base)
+	require.NoError(t, err)
+	require.NoError(t, ours.RemoveFile(fileID))
+	oursContent, err := ours.perObjectModify()
+	require.NoError(t, err)
+
+	theirs, err := parsePBXProjContent(base)
+	require.NoError(t, err)
+	buildFile, err := theirs.AddFileToTarget(fileID, "TodayExtension", SourcesBuildPhaseType)
+	require.NoError(t, err)
+	objects, _, err := theirs.rawObjects()
+	require.NoError(t, err)
+	fileReference, err := objects.Object(fileID)
+	require.NoError(t, err)
+	fileReference["lastKnownFileType"] = "text"
+	theirsContent, err := theirs.perObjectModify()
+	require.NoError(t, err)
+
+	content, conflicts, err := MergePBXProj("XcodeProj", base, oursContent, theirsContent)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(conflicts))
+	require.Equal(t, RemovedMergeConflict, conflicts[0].Type)
+	require.Equal(t, fileID, conflicts[0].Object)
+	require.Nil(t, conflicts[0].Ours)
+	require.Equal(t, MergeConflict{Type: DanglingReferenceMergeConflict, Object: buildFile.ID, Key: "fileRef", Reference: fileID}, conflicts[1])
+	require.Equal(t, buildFile.ID+" fileRef: references removed object: "+fileID, conflicts[1].String())
+
+	marked := string(MarkMergeConflicts(content, theirsContent, conflicts))
+	require.Contains(t, marked, "<<<<<<< ours\n=======\n\t\t"+fileID+" /* ViewController.swift */ = {isa = PBXFileReference; lastKnownFileType = text; path = ViewController.swift; sourceTree = \"<group>\"; };\n>>>>>>> theirs\n/* End PBXFileReference section */")
+}
+
+func TestMarkMergeConflicts(t *testing.T) {
+	merged := []byte("\t\tA /* App */ = {\n\t\t\tisa = PBXGroup;\n\t\t\tname = Ours;\n\t\t};\n\t\tB = {isa = PBXGroup; };\n")
+	theirs := []byte("\t\tA /* App */ = {\n\t\t\tisa = PBXGroup;\n\t\t\tname = Theirs;\n\t\t};\n")
+
+	conflicts := []MergeConflict{
+		{Type: ModifiedMergeConflict, Object: "A", Key: "name"},
+		{Type: ModifiedMergeConflict, Object: "A", Key: "path"},
+		{Type: RemovedMergeConflict, Object: "B"},
+	}
+	require.Equal(t, "<<<<<<< ours\n\t\tA /* App */ = {\n\t\t\tisa = PBXGroup;\n\t\t\tname = Ours;\n\t\t};\n=======\n\t\tA /* App */ = {\n\t\t\tisa = PBXGroup;\n\t\t\tname = Theirs;\n\t\t};\n>>>>>>> theirs\n"+
+		"<<<<<<< ours\n\t\tB = {isa = PBXGroup; };\n=======\n>>>>>>> theirs\n", string(MarkMergeConflicts(merged, theirs, conflicts)))
+}
+
+func Test_mergeValue(t *testing.T) {
+	base := map[string]interface{}{
+		"isa":      "PBXGroup",
+		"children": []interface{}{"A", "B", "C"},
+		"name":     "Sources",
+	}
+	ours := map[string]interface{}{
+		"isa":      "PBXGroup",
+		"children": []interface{}{"A", "C", "D"},
+		"name":     "Sources",
+	}
+	theirs := map[string]interface{}{
+		"isa":      "PBXGroup",
+		"children": []interface{}{"E", "A", "B", "C"},
+		"path":     "Sources",
+	}
+
+	merged, conflicts := mergeValue(nil, base, ours, theirs)
+	require.Equal(t, 0, len(conflicts))
+	require.Equal(t, map[string]interface{}{
+		"isa":      "PBXGroup",
+		"children": []interface{}{"E", "A", "C", "D"},
+		"path":     "Sources",
+	}, merged)
+
+	t.Log("removed on one side, modified on the other")
+	{
+		merged, conflicts := mergeValue([]string{"objects", "ID"}, base, nil, theirs)
+		require.Nil(t, merged)
+		require.Equal(t, []MergeConflict{{Type: RemovedMergeConflict, Object: "ID", Base: base, Theirs: theirs}}, conflicts)
+		require.Equal(t, "ID: base: {...}, ours: (none), theirs: {...}", conflicts[0].String())
+	}
+}
+
+func Test_mergeList(t *testing.T) {
+	list := func(items ...string) []interface{} {
+		l := []interface{}{}
+		for _, item := range items {
+			l = append(l, item)
+		}
+		return l
+	}
+
+	tests := []struct {
+		name               string
+		base, ours, theirs []interface{}
+		want               []interface{}
+		wantOK             bool
+	}{
+		{
+			name:   "repeated items: insertion between the repeated items",
+			base:   list("-framework", "A", "-framework", "B"),
+			ours:   list("-framework", "A", "-framework", "B", "-ObjC"),
+			theirs: list("-framework", "A", "-framework", "C", "-framework", "B"),
+			want:   list("-framework", "A", "-framework", "C", "-framework", "B", "-ObjC"),
+			wantOK: true,
+		},
+		{
+			name:   "repeated items: removal keeps the other copies",
+			base:   list("-framework", "A", "-framework", "B"),
+			ours:   list("-framework", "A"),
+			theirs: list("-ObjC", "-framework", "A", "-framework", "B"),
+			want:   list("-ObjC", "-framework", "A"),
+			wantOK: true,
+		},
+		{
+			name:   "repeated items: different removals conflict",
+			base:   list("-framework", "A", "-framework", "B"),
+			ours:   list("-framework", "B"),
+			theirs: list("-framework", "A"),
+			wantOK: false,
+		},
+		{
+			name:   "reordering and an independent addition",
+			base:   list("A", "B", "C", "D"),
+			ours:   list("B", "A", "C", "D"),
+			theirs: list("A", "B", "C", "D", "E"),
+			want:   list("B", "A", "C", "D", "E"),
+			wantOK: true,
+		},
+		{
+			name:   "different reorderings conflict",
+			base:   list("A", "B", "C"),
+			ours:   list("B", "A", "C"),
+			theirs: list("A", "C", "B"),
+			wantOK: false,
+		},
+		{
+			name:   "additions at the same position",
+			base:   list("A"),
+			ours:   list("A", "B"),
+			theirs: list("A", "C"),
+			want:   list("A", "B", "C"),
+			wantOK: true,
+		},
+		{
+			name:   "the same item added at the same position differently conflicts",
+			base:   list("A"),
+			ours:   list("A", "B", "C"),
+			theirs: list("A", "C"),
+			wantOK: false,
+		},
+		{
+			name:   "added on both sides",
+			ours:   list("$(inherited)", "-ObjC"),
+			theirs: list("$(inherited)", "-lz"),
+			want:   list("$(inherited)", "-ObjC", "-lz"),
+			wantOK: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := mergeList(tt.base, tt.ours, tt.theirs)
+			require.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				require.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,458 @@
+package xcodeproj
+
+import (
+	"crypto/md5"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/xcode-project/serialized"
+	"github.com/bitrise-io/xcode-project/xcscheme"
+)
+
+// objectLabelKeys are the keys naming an object, in order of precedence.
+var objectLabelKeys = []string{"name", "path", "productName", "remoteInfo", "repositoryURL", "relativePath"}
+
+// sortedBuildPhaseTypes are the build phases, whose files are sorted by NormalizeObjectIDs.
+// The order of the linked frameworks and of the copied files is kept, as it can matter.
+var sortedBuildPhaseTypes = map[string]bool{
+	string(SourcesBuildPhaseType):   true,
+	string(ResourcesBuildPhaseType): true,
+	string(HeadersBuildPhaseType):   true,
+}
+
+// NormalizeObjectIDs replaces the random object IDs generated by Xcode with deterministic ones,
+// derived from the object's path in the project graph (like `PBXProject/targets/PBXNativeTarget:App/buildConfigurationList/XCConfigurationList`),
+// so the same change made on different machines results in the same project content.
+// Every reference to the objects is rewritten, including the BlueprintIdentifiers of the project's schemes
+// and of the schemes in containerPaths (like the workspace containing the project or the other projects
+// of the workspace), which reference the project's targets. The projects in containerPaths referencing the project
+// (cross-project target dependencies and product references) get the new remote IDs in their PBXContainerItemProxy objects,
+// the changed projects are saved. Objects not referenced from the project keep their IDs.
+// The same named elements of a group (or build phase) are identified by their source tree and path too.
+//
+// If sortLists is true, the children of the groups and the files of the sources, resources and headers build phases
+// are sorted by name (and by source tree and path) too.
+//
+// The project is saved, it has to be opened from a path.
+func (p *XcodeProj) NormalizeObjectIDs(sortLists bool, containerPaths ...string) error {
+	if p.Path == "" {
+		return fmt.Errorf("project was not opened from a path")
+	}
+
+	ids, err := p.normalizeObjectIDs(sortLists)
+	if err != nil {
+		return err
+	}
+
+	for _, containerPth := range append([]string{p.Path}, containerPaths...) {
+		if err := p.replaceBlueprintIdentifiersInSchemes(containerPth, ids); err != nil {
+			return err
+		}
+	}
+
+	for _, containerPth := range containerPaths {
+		if !IsXcodeProj(containerPth) {
+			continue
+		}
+		if err := p.replaceRemoteGlobalIDsInProject(containerPth, ids); err != nil {
+			return err
+		}
+	}
+
+	content, err := p.Marshal()
+	if err != nil {
+		return fmt.Errorf("failed to marshal .pbxproj: %s", err)
+	}
+
+	pth := filepath.Join(p.Path, "project.pbxproj")
+	if err := fileutil.WriteBytesToFile(pth, content); err != nil {
+		return fmt.Errorf("failed to write project (%s): %s", pth, err)
+	}
+
+	// every object changed, the written content is the base of the later in-place modifications
+	saved, err := parsePBXProjContent(content)
+	if err != nil {
+		return fmt.Errorf("failed to parse normalized project: %s", err)
+	}
+	p.RawProj = saved.RawProj
+	p.originalContents = saved.originalContents
+	p.originalPbxProj = saved.originalPbxProj
+	p.annotatedPbxProj = saved.annotatedPbxProj
+	return p.reloadProj()
+}
+
+// normalizeObjectIDs replaces the object IDs in the project and returns the new IDs by the old ones.
+func (p *XcodeProj) normalizeObjectIDs(sortLists bool) (map[string]string, error) {
+	objects, _, err := p.rawObjects()
+	if err != nil {
+		return nil, err
+	}
+
+	if sortLists {
+		if err := sortObjectLists(objects); err != nil {
+			return nil, err
+		}
+	}
+
+	ids := normalizedObjectIDs(objects, p.Proj.ID)
+
+	rawProj, ok := replaceObjectIDs(p.RawProj, ids, p.Proj.ID).(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("failed to replace object IDs")
+	}
+	p.RawProj = rawProj
+	p.Proj.ID = ids[p.Proj.ID]
+
+	return ids, p.reloadProj()
+}
+
+// normalizedObjectIDs returns the new IDs by the old ones, the new ID of an object is the MD5 hash of its path in the project graph.
+func normalizedObjectIDs(objects serialized.Object, rootID string) map[string]string {
+	ids := map[string]string{}
+	for id, pth := range objectPaths(objects, rootID) {
+		ids[id] = fmt.Sprintf("%X", md5.Sum([]byte(pth)))[:24]
+	}
+	return ids
+}
+
+// objectPaths walks the project graph breadth first from the root object and returns the first path of the reachable objects by their IDs,
+// like `PBXProject/targets/PBXNativeTarget:App/buildConfigurationList/XCConfigurationList`.
+// The labels of the same named objects of a list are followed by their location (see objectLocation),
+// like `PBXFileReference:Info.plist(<group>/App/Info.plist)`.
+// The paths are unique, the same path of different objects is suffixed with a counter (like `#2`).
+func objectPaths(objects serialized.Object, rootID string) map[string]string {
+	type node struct {
+		id, path string
+	}
+
+	paths := map[string]string{}
+	used := map[string]bool{}
+	assign := func(id, pth string) node {
+		unique := pth
+		for i := 2; used[unique]; i++ {
+			unique = fmt.Sprintf("%s#%d", pth, i)
+		}
+		used[unique] = true
+		paths[id] = unique
+		return node{id: id, path: unique}
+	}
+
+	queue := []node{assign(rootID, objectLabel(objects, rootID))}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		enqueue := func(pth, id string, located bool) {
+			if _, ok := paths[id]; ok {
+				return
+			}
+			if _, err := objects.Object(id); err != nil {
+				return
+			}
+
+			label := objectLabel(objects, id)
+			if location := objectLocation(objects, id); located && location != "" {
+				label += "(" + location + ")"
+			}
+			queue = append(queue, assign(id, pth+"/"+label))
+		}
+
+		var visit func(pth string, value interface{})
+		visit = func(pth string, value interface{}) {
+			switch value := value.(type) {
+			case string:
+				enqueue(pth, value, false)
+			case []interface{}:
+				labels := map[string]int{}
+				for _, item := range value {
+					if id, ok := item.(string); ok {
+						labels[objectLabel(objects, id)]++
+					}
+				}
+
+				for _, item := range value {
+					if id, ok := item.(string); ok {
+						enqueue(pth, id, labels[objectLabel(objects, id)] > 1)
+						continue
+					}
+					visit(pth, item)
+				}
+			case map[string]interface{}:
+				for _, key := range sortedKeys(value) {
+					if key == "isa" || key == customAnnotationKey {
+						continue
+					}
+					if key == "remoteGlobalIDString" && isRemoteProxy(value, rootID) {
+						continue
+					}
+					// the path of the values keyed by an object ID (like the TargetAttributes) would depend on the ID
+					if _, err := objects.Object(key); err == nil {
+						continue
+					}
+					visit(pth+"/"+key, value[key])
+				}
+			}
+		}
+
+		object, err := objects.Object(current.id)
+		if err != nil {
+			continue
+		}
+		visit(current.path, map[string]interface{}(object))
+	}
+
+	return paths
+}
+
+// isRemoteProxy reports whether the object is a PBXContainerItemProxy pointing at an object of another project.
+func isRemoteProxy(object map[string]interface{}, rootID string) bool {
+	isa, _ := object["isa"].(string)
+	containerPortal, _ := object["containerPortal"].(string)
+	return isa == "PBXContainerItemProxy" && containerPortal != rootID
+}
+
+// objectLabel returns the isa of the object followed by its name (or the name of the object it refers to).
+func objectLabel(objects serialized.Object, id string) string {
+	object, err := objects.Object(id)
+	if err != nil {
+		return ""
+	}
+
+	isa := optionalString(object, "isa")
+	if name := objectName(objects, object); name != "" {
+		return isa + ":" + name
+	}
+	return isa
+}
+
+func objectName(objects serialized.Object, object serialized.Object) string {
+	for _, key := range objectLabelKeys {
+		if name := optionalString(object, key); name != "" {
+			return name
+		}
+	}
+
+	// build files and target dependencies are named by the referenced object
+	for _, key := range []string{"fileRef", "productRef", "target", "targetProxy"} {
+		if id := optionalString(object, key); id != "" {
+			if referenced, err := objects.Object(id); err == nil {
+				return objectName(objects, referenced)
+			}
+		}
+	}
+	return ""
+}
+
+// objectLocation returns the source tree and path of the element (or of the element referenced by the build file),
+// like `<group>/App/Info.plist`, it distinguishes the same named elements.
+func objectLocation(objects serialized.Object, id string) string {
+	object, err := objects.Object(id)
+	if err != nil {
+		return ""
+	}
+
+	if pth := optionalString(object, "path"); pth != "" {
+		return optionalString(object, "sourceTree") + "/" + pth
+	}
+	if fileRef := optionalString(object, "fileRef"); fileRef != "" {
+		return objectLocation(objects, fileRef)
+	}
+	return ""
+}
+
+// replaceObjectIDs returns a copy of the value with the object IDs (string values and dictionary keys) replaced.
+func replaceObjectIDs(value interface{}, ids map[string]string, rootID string) interface{} {
+	switch value := value.(type) {
+	case string:
+		if id, ok := ids[value]; ok {
+			return id
+		}
+		return value
+	case serialized.Object:
+		return replaceObjectIDs(map[string]interface{}(value), ids, rootID)
+	case map[string]interface{}:
+		replaced := map[string]interface{}{}
+		for key, v := range value {
+			// the remote object of a cross-project reference is in the other project, it keeps its ID
+			if key == customAnnotationKey || (key == "remoteGlobalIDString" && isRemoteProxy(value, rootID)) {
+				replaced[key] = v
+				continue
+			}
+			if id, ok := ids[key]; ok {
+				key = id
+			}
+			replaced[key] = replaceObjectIDs(v, ids, rootID)
+		}
+		return replaced
+	case []interface{}:
+		replaced := make([]interface{}, len(value))
+		for i, item := range value {
+			replaced[i] = replaceObjectIDs(item, ids, rootID)
+		}
+		return replaced
+	default:
+		return value
+	}
+}
+
+// sortObjectLists sorts the children of the groups and the files of the sources, resources and headers build phases
+// by name (case-insensitively), then by location (see objectLocation).
+func sortObjectLists(objects serialized.Object) error {
+	for _, id := range sortedKeys(objects) {
+		object, err := objects.Object(id)
+		if err != nil {
+			return fmt.Errorf("failed to access object (%s): %s", id, err)
+		}
+
+		var key string
+		isa := optionalString(object, "isa")
+		switch {
+		case isa == "PBXGroup" || isa == "PBXVariantGroup" || isa == "XCVersionGroup":
+			key = "children"
+		case sortedBuildPhaseTypes[isa]:
+			key = "files"
+		default:
+			continue
+		}
+
+		list, err := object.StringSlice(key)
+		if err != nil {
+			if serialized.IsKeyNotFoundError(err) {
+				continue
+			}
+			return fmt.Errorf("failed to access %s of object (%s): %s", key, id, err)
+		}
+
+		names := map[string]string{}
+		for _, itemID := range list {
+			if item, err := objects.Object(itemID); err == nil {
+				names[itemID] = strings.ToLower(displayName(objects, item))
+			}
+		}
+
+		sort.SliceStable(list, func(i, j int) bool {
+			if names[list[i]] != names[list[j]] {
+				return names[list[i]] < names[list[j]]
+			}
+			return objectLocation(objects, list[i]) < objectLocation(objects, list[j])
+		})
+
+		var values []interface{}
+		for _, itemID := range list {
+			values = append(values, itemID)
+		}
+		object[key] = values
+	}
+	return nil
+}
+
+// displayName returns the name of the element (or of the element referenced by the build file) as shown in Xcode.
+func displayName(objects serialized.Object, object serialized.Object) string {
+	if id := optionalString(object, "fileRef"); id != "" {
+		if referenced, err := objects.Object(id); err == nil {
+			return displayName(objects, referenced)
+		}
+	}
+	if name := optionalString(object, "name"); name != "" {
+		return name
+	}
+	if pth := optionalString(object, "path"); pth != "" {
+		return filepath.Base(pth)
+	}
+	return optionalString(object, "productName")
+}
+
+// replaceRemoteGlobalIDsInProject replaces the remote object IDs of the PBXContainerItemProxy objects
+// of the project at projectPth, which point at the project's objects. The other project is saved if it changed.
+func (p XcodeProj) replaceRemoteGlobalIDsInProject(projectPth string, ids map[string]string) error {
+	projectPth, err := pathutil.AbsPath(projectPth)
+	if err != nil {
+		return err
+	}
+	if projectPth == filepath.Clean(p.Path) {
+		return nil
+	}
+
+	other, err := Open(projectPth)
+	if err != nil {
+		return err
+	}
+
+	objects, _, err := other.rawObjects()
+	if err != nil {
+		return err
+	}
+
+	changed := false
+	for _, id := range sortedKeys(objects) {
+		proxy, err := objects.Object(id)
+		if err != nil || optionalString(proxy, "isa") != "PBXContainerItemProxy" {
+			continue
+		}
+
+		remotePth, ok, err := other.proxyProjectPath(proxy)
+		if err != nil {
+			return err
+		}
+		if !ok || remotePth != filepath.Clean(p.Path) {
+			continue
+		}
+
+		if newID, ok := ids[optionalString(proxy, "remoteGlobalIDString")]; ok {
+			proxy["remoteGlobalIDString"] = newID
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+	if err := other.Save(); err != nil {
+		return fmt.Errorf("failed to save project (%s): %s", projectPth, err)
+	}
+	return nil
+}
+
+// replaceBlueprintIdentifiersInSchemes replaces the BlueprintIdentifiers in the schemes of the container (project or workspace),
+// which reference the project's targets.
+func (p XcodeProj) replaceBlueprintIdentifiersInSchemes(containerPth string, ids map[string]string) error {
+	projectPth, err := pathutil.AbsPath(p.Path)
+	if err != nil {
+		return err
+	}
+
+	schemes, err := xcscheme.FindSchemesIn(containerPth)
+	if err != nil {
+		return err
+	}
+
+	isProject := func(referencedContainer string) bool {
+		pth, err := xcscheme.BuildableReference{ReferencedContainer: referencedContainer}.ReferencedContainerAbsPath(filepath.Dir(containerPth))
+		return err == nil && pth == projectPth
+	}
+
+	for _, scheme := range schemes {
+		content, err := fileutil.ReadBytesFromFile(scheme.Path)
+		if err != nil {
+			return err
+		}
+
+		content, changes, err := xcscheme.ReplaceBlueprintIdentifiers(content, ids, isProject)
+		if err != nil {
+			return fmt.Errorf("failed to replace blueprint identifiers in scheme (%s): %s", scheme.Path, err)
+		}
+		if len(changes) == 0 {
+			continue
+		}
+
+		if err := fileutil.WriteBytesToFile(scheme.Path, content); err != nil {
+			return fmt.Errorf("failed to write scheme (%s): %s", scheme.Path, err)
+		}
+	}
+
+	return nil
+}
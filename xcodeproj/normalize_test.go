@@ -0,0 +1,140 @@
+package xcodeproj
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXcodeProj_NormalizeObjectIDs(t *testing.T) {
+	const appTargetID = "7D5B35FB20E28EE80022BAE6"
+	schemePth := filepath.Join("XcodeProj.xcodeproj", "xcshareddata", "xcschemes", "XcodeProj.xcscheme")
+
+	normalized := func(sortLists bool) (XcodeProj, string, string) {
+		pth := createProjectInTmpDir(t, "XcodeProj", testhelper.XcodeProjectTest, map[string]string{schemePth: testSchemeContent(appTargetID)})
+		project, err := Open(pth)
+		require.NoError(t, err)
+
+		// the same change made on two machines
+		file, err := project.AddFile("7D5B35FE20E28EE80022BAE6", "Model.swift")
+		require.NoError(t, err)
+		_, err = project.AddFileToTarget(file.ID, "XcodeProj", SourcesBuildPhaseType)
+		require.NoError(t, err)
+
+		require.NoError(t, project.NormalizeObjectIDs(sortLists))
+
+		content, err := ioutil.ReadFile(filepath.Join(pth, "project.pbxproj"))
+		require.NoError(t, err)
+		scheme, err := ioutil.ReadFile(filepath.Join(filepath.Dir(pth), schemePth))
+		require.NoError(t, err)
+		return project, string(content), string(scheme)
+	}
+
+	project, content, scheme := normalized(false)
+	_, otherContent, otherScheme := normalized(false)
+	require.Equal(t, content, otherContent)
+	require.Equal(t, scheme, otherScheme)
+
+	require.NotContains(t, content, appTargetID)
+	target, ok := project.Proj.TargetByName("XcodeProj")
+	require.True(t, ok)
+	require.NotEqual(t, appTargetID, target.ID)
+	require.Contains(t, scheme, `BlueprintIdentifier = "`+target.ID+`"`)
+
+	uiTestTarget, ok := project.Proj.TargetByName("XcodeProjUITests")
+	require.True(t, ok)
+	require.Equal(t, target.ID, uiTestTarget.Dependencies[0].Target.ID)
+	require.Equal(t, target.ID, project.Proj.Attributes.TargetAttributes[uiTestTarget.ID].(map[string]interface{})["TestTargetID"])
+
+	var sources []string
+	for _, buildFile := range target.BuildPhasesOfType(SourcesBuildPhaseType)[0].Files {
+		sources = append(sources, elementTreePath(project.Proj, buildFile.FileRef))
+	}
+	require.Equal(t, []string{"XcodeProj/ViewController.swift", "XcodeProj/AppDelegate.swift", "XcodeProj/Model.swift"}, sources)
+
+	t.Log("normalizing again keeps the IDs")
+	{
+		require.NoError(t, project.NormalizeObjectIDs(false))
+		again, err := ioutil.ReadFile(filepath.Join(project.Path, "project.pbxproj"))
+		require.NoError(t, err)
+		require.Equal(t, content, string(again))
+	}
+
+	t.Log("sort lists")
+	{
+		project, _, _ := normalized(true)
+		target, ok := project.Proj.TargetByName("XcodeProj")
+		require.True(t, ok)
+
+		var sources []string
+		for _, buildFile := range target.BuildPhasesOfType(SourcesBuildPhaseType)[0].Files {
+			sources = append(sources, elementTreePath(project.Proj, buildFile.FileRef))
+		}
+		require.Equal(t, []string{"XcodeProj/AppDelegate.swift", "XcodeProj/Model.swift", "XcodeProj/ViewController.swift"}, sources)
+	}
+}
+
+func TestXcodeProj_NormalizeObjectIDs_SameNamedElements(t *testing.T) {
+	const groupID = "7D5B35FE20E28EE80022BAE6"
+
+	normalizedIDs := func(paths ...string) map[string]string {
+		pth := createProjectInTmpDir(t, "XcodeProj", testhelper.XcodeProjectTest, nil)
+		project, err := Open(pth)
+		require.NoError(t, err)
+
+		for _, pth := range paths {
+			_, err := project.AddFile(groupID, pth)
+			require.NoError(t, err)
+		}
+
+		require.NoError(t, project.NormalizeObjectIDs(false))
+
+		// the group's ID is normalized too
+		var group *Group
+		for _, element := range project.Proj.MainGroup.Children {
+			if child, ok := element.(*Group); ok && child.DisplayName() == "XcodeProj" {
+				group = child
+			}
+		}
+		require.NotNil(t, group)
+
+		ids := map[string]string{}
+		for _, child := range group.Children {
+			if fileReference, ok := child.(*FileReference); ok {
+				ids[fileReference.Path] = fileReference.ID
+			}
+		}
+		return ids
+	}
+
+	ids := normalizedIDs("A/Info.plist", "B/Info.plist")
+	require.NotEqual(t, ids["A/Info.plist"], ids["B/Info.plist"])
+	require.Equal(t, ids, normalizedIDs("B/Info.plist", "A/Info.plist"))
+}
+
+func TestXcodeProj_NormalizeObjectIDs_ProjectReferences(t *testing.T) {
+	const remoteTargetID = "7D03430C20F4BB070050B6A6"
+
+	t.Log("the remote object IDs of the cross-project references are kept")
+	{
+		pth := createProjectInTmpDir(t, "XcodeProj", testhelper.XcodeProjectTestWithProjectDependency("Lib/Lib.xcodeproj"), nil)
+		project, err := Open(pth)
+		require.NoError(t, err)
+		require.NoError(t, project.NormalizeObjectIDs(false))
+
+		graph, err := project.DependencyGraph()
+		require.NoError(t, err)
+		require.Equal(t, 1, len(graph.Unresolved()))
+		require.Equal(t, remoteTargetID, graph.Unresolved()[0].ID)
+	}
+
+	t.Log("the project has to be opened from a path")
+	{
+		project, err := parsePBXProjContent([]byte(testhelper.XcodeProjectTest))
+		require.NoError(t, err)
+		require.EqualError(t, project.NormalizeObjectIDs(false), "project was not opened from a path")
+	}
+}
@@ -0,0 +1,122 @@
+package xcodeproj
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+)
+
+// newObjectID returns a random 24 character hexadecimal object ID (like the ones generated by Xcode),
+// which is not used by any of the given objects.
+func newObjectID(objects serialized.Object) (string, error) {
+	for {
+		b := make([]byte, 12)
+		if _, err := rand.Read(b); err != nil {
+			return "", fmt.Errorf("failed to generate object ID: %s", err)
+		}
+
+		id := strings.ToUpper(hex.EncodeToString(b))
+		if _, ok := objects[id]; !ok {
+			return id, nil
+		}
+	}
+}
+
+// addObject adds the object to the project's objects with a new ID and returns the ID.
+func addObject(objects serialized.Object, object serialized.Object) (string, error) {
+	id, err := newObjectID(objects)
+	if err != nil {
+		return "", err
+	}
+
+	objects[id] = map[string]interface{}(object)
+	return id, nil
+}
+
+// rawObjects returns the objects of the raw project and the raw PBXProject object.
+func (p XcodeProj) rawObjects() (serialized.Object, serialized.Object, error) {
+	objects, err := p.RawProj.Object("objects")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to access objects: %s", err)
+	}
+
+	rawPBXProj, err := objects.Object(p.Proj.ID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to access project object (%s): %s", p.Proj.ID, err)
+	}
+
+	return objects, rawPBXProj, nil
+}
+
+// reloadProj parses the Proj again from the RawProj, after the raw objects were modified.
+func (p *XcodeProj) reloadProj() error {
+	objects, err := p.RawProj.Object("objects")
+	if err != nil {
+		return fmt.Errorf("failed to access objects: %s", err)
+	}
+
+	proj, err := parseProj(p.Proj.ID, objects)
+	if err != nil {
+		return fmt.Errorf("failed to parse modified project: %s", err)
+	}
+	p.Proj = proj
+
+	if p.Path != "" {
+		p.loadBaseConfigurations()
+	}
+	return nil
+}
+
+// appendToArray appends the values to the array stored under the key, the array is created if it does not exist.
+func appendToArray(object serialized.Object, key string, values ...interface{}) error {
+	array, err := object.Value(key)
+	if err != nil {
+		if !serialized.IsKeyNotFoundError(err) {
+			return err
+		}
+		array = []interface{}{}
+	}
+
+	items, ok := array.([]interface{})
+	if !ok {
+		return serialized.NewTypeCastError(key, array, []interface{}{})
+	}
+
+	object[key] = append(items, values...)
+	return nil
+}
+
+// removeFromArray removes the values from the array stored under the key, a missing array is not an error.
+func removeFromArray(object serialized.Object, key string, values ...string) error {
+	array, err := object.Value(key)
+	if err != nil {
+		if serialized.IsKeyNotFoundError(err) {
+			return nil
+		}
+		return err
+	}
+
+	items, ok := array.([]interface{})
+	if !ok {
+		return serialized.NewTypeCastError(key, array, []interface{}{})
+	}
+
+	remove := map[string]bool{}
+	for _, value := range values {
+		remove[value] = true
+	}
+
+	kept := []interface{}{}
+	for _, item := range items {
+		if s, ok := item.(string); ok && remove[s] {
+			continue
+		}
+		kept = append(kept, item)
+	}
+
+	object[key] = kept
+	return nil
+}
@@ -0,0 +1,214 @@
+package xcodeproj
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/xcode-project/swiftpm"
+)
+
+// PackageResolutionIssueType ...
+type PackageResolutionIssueType string
+
+// PackageResolutionIssueTypes
+const (
+	// NotPinnedPackageIssue: the package is referenced by the project, but it is missing from Package.resolved.
+	NotPinnedPackageIssue PackageResolutionIssueType = "not-pinned"
+	// NotDeclaredPackageIssue: the package is pinned in Package.resolved, but it is neither referenced by the project
+	// nor a dependency of a referenced package (a stale pin). It is a warning, the stale pin does not break the resolution.
+	NotDeclaredPackageIssue PackageResolutionIssueType = "not-declared"
+	// RequirementViolatedPackageIssue: the pinned version does not satisfy the project's version requirement.
+	RequirementViolatedPackageIssue PackageResolutionIssueType = "requirement-violated"
+)
+
+// PackageResolutionIssue is an inconsistency between the Swift package references of a project and its Package.resolved.
+type PackageResolutionIssue struct {
+	Type     PackageResolutionIssueType
+	Identity string
+	// Reference is the project's package reference, nil for NotDeclaredPackageIssue.
+	Reference *SwiftPackageReference
+	// Pin is the resolved package, nil for NotPinnedPackageIssue.
+	Pin *swiftpm.Pin
+}
+
+// String ...
+func (i PackageResolutionIssue) String() string {
+	switch i.Type {
+	case NotPinnedPackageIssue:
+		return fmt.Sprintf("package %s (%s) is not pinned in %s", i.Identity, i.Reference.RepositoryURL, swiftpm.ResolvedFileName)
+	case NotDeclaredPackageIssue:
+		return fmt.Sprintf("package %s (%s) is pinned, but not declared in the project", i.Identity, i.Pin.Location)
+	case RequirementViolatedPackageIssue:
+		return fmt.Sprintf("package %s is pinned at %s, which does not satisfy the requirement: %s", i.Identity, pinDescription(*i.Pin), i.Reference.Requirement)
+	default:
+		return fmt.Sprintf("package %s: %s", i.Identity, i.Type)
+	}
+}
+
+func pinDescription(pin swiftpm.Pin) string {
+	switch {
+	case pin.Version != "":
+		return pin.Version
+	case pin.Branch != "":
+		return fmt.Sprintf("branch %s (%s)", pin.Branch, pin.Revision)
+	default:
+		return fmt.Sprintf("revision %s", pin.Revision)
+	}
+}
+
+// String ...
+func (r VersionRequirement) String() string {
+	switch r.Kind {
+	case ExactVersionRequirementKind:
+		return fmt.Sprintf("exactly %s", r.Version)
+	case VersionRangeRequirementKind:
+		return fmt.Sprintf("%s..<%s", r.MinimumVersion, r.MaximumVersion)
+	case UpToNextMajorVersionRequirementKind:
+		return fmt.Sprintf("up to next major from %s", r.MinimumVersion)
+	case UpToNextMinorVersionRequirementKind:
+		return fmt.Sprintf("up to next minor from %s", r.MinimumVersion)
+	case BranchRequirementKind:
+		return fmt.Sprintf("branch %s", r.Branch)
+	case RevisionRequirementKind:
+		return fmt.Sprintf("revision %s", r.Revision)
+	default:
+		return string(r.Kind)
+	}
+}
+
+// SatisfiedBy returns true if the pinned package fulfills the version requirement.
+func (r VersionRequirement) SatisfiedBy(pin swiftpm.Pin) (bool, error) {
+	switch r.Kind {
+	case BranchRequirementKind:
+		return pin.Branch == r.Branch, nil
+	case RevisionRequirementKind:
+		return pin.Revision == r.Revision, nil
+	case ExactVersionRequirementKind, VersionRangeRequirementKind, UpToNextMajorVersionRequirementKind, UpToNextMinorVersionRequirementKind:
+	default:
+		return false, fmt.Errorf("unknown version requirement kind: %s", r.Kind)
+	}
+
+	if pin.Version == "" {
+		return false, nil
+	}
+
+	version, err := swiftpm.ParseVersion(pin.Version)
+	if err != nil {
+		return false, err
+	}
+
+	if r.Kind == ExactVersionRequirementKind {
+		exact, err := swiftpm.ParseVersion(r.Version)
+		if err != nil {
+			return false, err
+		}
+		return version.Compare(exact) == 0, nil
+	}
+
+	lower, err := swiftpm.ParseVersion(r.MinimumVersion)
+	if err != nil {
+		return false, err
+	}
+
+	var upper swiftpm.Version
+	switch r.Kind {
+	case VersionRangeRequirementKind:
+		upper, err = swiftpm.ParseVersion(r.MaximumVersion)
+		if err != nil {
+			return false, err
+		}
+	case UpToNextMajorVersionRequirementKind:
+		upper = swiftpm.Version{Major: lower.Major + 1}
+	case UpToNextMinorVersionRequirementKind:
+		upper = swiftpm.Version{Major: lower.Major, Minor: lower.Minor + 1}
+	}
+
+	return version.Compare(lower) >= 0 && version.Compare(upper) < 0, nil
+}
+
+// CheckPackageResolution compares the remote Swift package references with the pins of a Package.resolved file.
+// Local packages are not pinned, they are skipped.
+// Package.resolved pins the transitive dependencies too, the pins, which are neither referenced
+// nor dependencies of the referenced packages (by the given dependencies), are reported as not declared.
+// If dependencies is nil, the transitive dependencies can not be told apart, so the not declared pins are not reported.
+func CheckPackageResolution(references []SwiftPackageReference, resolved swiftpm.Resolved, dependencies swiftpm.Dependencies) ([]PackageResolutionIssue, error) {
+	var issues []PackageResolutionIssue
+	var declared []string
+
+	for i := range references {
+		reference := references[i]
+		if reference.Type != RemoteSwiftPackageReferenceType {
+			continue
+		}
+
+		identity := swiftpm.Identity(reference.RepositoryURL)
+		declared = append(declared, identity)
+
+		pin, ok := resolved.Pin(identity)
+		if !ok {
+			issues = append(issues, PackageResolutionIssue{Type: NotPinnedPackageIssue, Identity: identity, Reference: &reference})
+			continue
+		}
+
+		satisfied, err := reference.Requirement.SatisfiedBy(pin)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check requirement of package (%s): %s", identity, err)
+		}
+		if !satisfied {
+			issues = append(issues, PackageResolutionIssue{Type: RequirementViolatedPackageIssue, Identity: identity, Reference: &reference, Pin: &pin})
+		}
+	}
+
+	if dependencies != nil {
+		required := dependencies.Transitive(declared...)
+		for i := range resolved.Pins {
+			pin := resolved.Pins[i]
+			if !required[pin.Identity] {
+				issues = append(issues, PackageResolutionIssue{Type: NotDeclaredPackageIssue, Identity: pin.Identity, Pin: &pin})
+			}
+		}
+	}
+
+	return issues, nil
+}
+
+// PackageResolvedPath returns the path of the project's Package.resolved file,
+// used when the project is opened on its own (not as part of a workspace).
+func (p XcodeProj) PackageResolvedPath() string {
+	return filepath.Join(p.Path, "project.xcworkspace", "xcshareddata", "swiftpm", swiftpm.ResolvedFileName)
+}
+
+// CheckPackageResolution compares the project's Swift package references with its Package.resolved file.
+// If the project has remote packages, but the Package.resolved file does not exist, every remote package is reported as not pinned.
+// sourcePackagesDir is the directory of the resolved packages (like DerivedData/<project>/SourcePackages
+// or the -clonedSourcePackagesDirPath of xcodebuild), the dependencies of the referenced packages are read from its checkouts
+// to report the stale pins (see CheckPackageResolution). If it is empty, the stale pins are not reported.
+func (p XcodeProj) CheckPackageResolution(sourcePackagesDir string) ([]PackageResolutionIssue, error) {
+	return CheckPackageResolutionAt(p.PackageResolvedPath(), p.Proj.SwiftPackageReferences, sourcePackagesDir)
+}
+
+// CheckPackageResolutionAt compares the given Swift package references with the Package.resolved file at pth,
+// see XcodeProj.CheckPackageResolution for sourcePackagesDir.
+func CheckPackageResolutionAt(pth string, references []SwiftPackageReference, sourcePackagesDir string) ([]PackageResolutionIssue, error) {
+	var dependencies swiftpm.Dependencies
+	if sourcePackagesDir != "" {
+		var err error
+		dependencies, err = swiftpm.OpenCheckoutDependencies(sourcePackagesDir)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read package dependencies from %s: %s", sourcePackagesDir, err)
+		}
+	}
+
+	var resolved swiftpm.Resolved
+	if exist, err := pathutil.IsPathExists(pth); err != nil {
+		return nil, fmt.Errorf("failed to check if %s exists: %s", pth, err)
+	} else if exist {
+		resolved, err = swiftpm.Open(pth)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return CheckPackageResolution(references, resolved, dependencies)
+}
@@ -0,0 +1,221 @@
+package xcodeproj
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/swiftpm"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionRequirement_SatisfiedBy(t *testing.T) {
+	tests := []struct {
+		name        string
+		requirement VersionRequirement
+		pin         swiftpm.Pin
+		want        bool
+	}{
+		{
+			name:        "exact version",
+			requirement: VersionRequirement{Kind: ExactVersionRequirementKind, Version: "7.1.2"},
+			pin:         swiftpm.Pin{Version: "7.1.2"},
+			want:        true,
+		},
+		{
+			name:        "exact version mismatch",
+			requirement: VersionRequirement{Kind: ExactVersionRequirementKind, Version: "7.1.2"},
+			pin:         swiftpm.Pin{Version: "7.1.3"},
+		},
+		{
+			name:        "up to next major",
+			requirement: VersionRequirement{Kind: UpToNextMajorVersionRequirementKind, MinimumVersion: "5.4.0"},
+			pin:         swiftpm.Pin{Version: "5.9.1"},
+			want:        true,
+		},
+		{
+			name:        "up to next major, next major pinned",
+			requirement: VersionRequirement{Kind: UpToNextMajorVersionRequirementKind, MinimumVersion: "5.4.0"},
+			pin:         swiftpm.Pin{Version: "6.0.0"},
+		},
+		{
+			name:        "up to next major, lower version pinned",
+			requirement: VersionRequirement{Kind: UpToNextMajorVersionRequirementKind, MinimumVersion: "5.4.0"},
+			pin:         swiftpm.Pin{Version: "5.3.9"},
+		},
+		{
+			name:        "up to next minor",
+			requirement: VersionRequirement{Kind: UpToNextMinorVersionRequirementKind, MinimumVersion: "1.2.0"},
+			pin:         swiftpm.Pin{Version: "1.2.5"},
+			want:        true,
+		},
+		{
+			name:        "up to next minor, next minor pinned",
+			requirement: VersionRequirement{Kind: UpToNextMinorVersionRequirementKind, MinimumVersion: "1.2.0"},
+			pin:         swiftpm.Pin{Version: "1.3.0"},
+		},
+		{
+			name:        "range, maximum is exclusive",
+			requirement: VersionRequirement{Kind: VersionRangeRequirementKind, MinimumVersion: "1.0.0", MaximumVersion: "1.5.0"},
+			pin:         swiftpm.Pin{Version: "1.5.0"},
+		},
+		{
+			name:        "range",
+			requirement: VersionRequirement{Kind: VersionRangeRequirementKind, MinimumVersion: "1.0.0", MaximumVersion: "1.5.0"},
+			pin:         swiftpm.Pin{Version: "1.4.2"},
+			want:        true,
+		},
+		{
+			name:        "version requirement, branch pinned",
+			requirement: VersionRequirement{Kind: UpToNextMajorVersionRequirementKind, MinimumVersion: "1.0.0"},
+			pin:         swiftpm.Pin{Branch: "main", Revision: "f8a9c997c3c1dab4e216a8ec9014e23144cbab37"},
+		},
+		{
+			name:        "branch",
+			requirement: VersionRequirement{Kind: BranchRequirementKind, Branch: "main"},
+			pin:         swiftpm.Pin{Branch: "main", Revision: "f8a9c997c3c1dab4e216a8ec9014e23144cbab37"},
+			want:        true,
+		},
+		{
+			name:        "revision",
+			requirement: VersionRequirement{Kind: RevisionRequirementKind, Revision: "180d94132758dd183124ab1e63d6aa8e10023ec2"},
+			pin:         swiftpm.Pin{Revision: "f8a9c997c3c1dab4e216a8ec9014e23144cbab37"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.requirement.SatisfiedBy(tt.pin)
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+const rawSwiftPackagesResolved = `{
+  "pins" : [
+    {
+      "identity" : "alamofire",
+      "kind" : "remoteSourceControl",
+      "location" : "https://github.com/Alamofire/Alamofire.git",
+      "state" : {
+        "revision" : "354dda32d89fc8cd4f5c46487f64957d355f53d8",
+        "version" : "5.6.1"
+      }
+    },
+    {
+      "identity" : "kingfisher",
+      "kind" : "remoteSourceControl",
+      "location" : "https://github.com/onevcat/Kingfisher",
+      "state" : {
+        "revision" : "44e891bdb61426a95e31492a67c7c0dfad1f87c5",
+        "version" : "7.0.0"
+      }
+    },
+    {
+      "identity" : "rxswift",
+      "kind" : "remoteSourceControl",
+      "location" : "https://github.com/ReactiveX/RxSwift.git",
+      "state" : {
+        "revision" : "b4307ba0b6425c0ba4178e138799946c3da594f8",
+        "version" : "6.5.0"
+      }
+    },
+    {
+      "identity" : "swift-argument-parser",
+      "kind" : "remoteSourceControl",
+      "location" : "https://github.com/apple/swift-argument-parser.git",
+      "state" : {
+        "revision" : "fddd1c00396eed152c45a46bea9f47b98e59301d",
+        "version" : "1.2.0"
+      }
+    },
+    {
+      "identity" : "swift-snapshot-testing",
+      "kind" : "remoteSourceControl",
+      "location" : "https://github.com/pointfreeco/swift-snapshot-testing",
+      "state" : {
+        "branch" : "main",
+        "revision" : "f8a9c997c3c1dab4e216a8ec9014e23144cbab37"
+      }
+    },
+    {
+      "identity" : "swiftlint",
+      "kind" : "remoteSourceControl",
+      "location" : "https://github.com/realm/SwiftLint",
+      "state" : {
+        "revision" : "180d94132758dd183124ab1e63d6aa8e10023ec2"
+      }
+    }
+  ],
+  "version" : 2
+}
+`
+
+func TestXcodeProj_CheckPackageResolution(t *testing.T) {
+	t.Log("Package.resolved exists")
+	{
+		pth := createProjectInTmpDir(t, "Packages", rawSwiftPackages, map[string]string{
+			"Packages.xcodeproj/project.xcworkspace/xcshareddata/swiftpm/Package.resolved": rawSwiftPackagesResolved,
+		})
+		project, err := Open(pth)
+		require.NoError(t, err)
+
+		issues, err := project.CheckPackageResolution("")
+		require.NoError(t, err)
+
+		// the dependencies of the packages are unknown, the pins not referenced by the project are not reported
+		var messages []string
+		for _, issue := range issues {
+			messages = append(messages, issue.String())
+		}
+		require.Equal(t, []string{
+			"package kingfisher is pinned at 7.0.0, which does not satisfy the requirement: exactly 7.1.2",
+			"package swift-log (https://github.com/apple/swift-log.git) is not pinned in Package.resolved",
+		}, messages)
+	}
+
+	t.Log("stale pin")
+	{
+		pth := createProjectInTmpDir(t, "Packages", rawSwiftPackages, map[string]string{
+			"Packages.xcodeproj/project.xcworkspace/xcshareddata/swiftpm/Package.resolved": rawSwiftPackagesResolved,
+			"SourcePackages/checkouts/SwiftLint/Package.swift": `let package = Package(
+    name: "SwiftLint",
+    dependencies: [
+        .package(url: "https://github.com/apple/swift-argument-parser.git", .upToNextMinor(from: "1.2.0")),
+    ]
+)
+`,
+		})
+		project, err := Open(pth)
+		require.NoError(t, err)
+
+		issues, err := project.CheckPackageResolution(filepath.Join(filepath.Dir(pth), "SourcePackages"))
+		require.NoError(t, err)
+
+		// swift-argument-parser is a dependency of SwiftLint, RxSwift is not required by any package
+		var messages []string
+		for _, issue := range issues {
+			messages = append(messages, issue.String())
+		}
+		require.Equal(t, []string{
+			"package kingfisher is pinned at 7.0.0, which does not satisfy the requirement: exactly 7.1.2",
+			"package swift-log (https://github.com/apple/swift-log.git) is not pinned in Package.resolved",
+			"package rxswift (https://github.com/ReactiveX/RxSwift.git) is pinned, but not declared in the project",
+		}, messages)
+		require.Equal(t, NotDeclaredPackageIssue, issues[2].Type)
+		require.Nil(t, issues[2].Reference)
+	}
+
+	t.Log("Package.resolved does not exist")
+	{
+		pth := createProjectInTmpDir(t, "Packages", rawSwiftPackages, nil)
+		project, err := Open(pth)
+		require.NoError(t, err)
+
+		issues, err := project.CheckPackageResolution("")
+		require.NoError(t, err)
+		require.Equal(t, 5, len(issues))
+		for _, issue := range issues {
+			require.Equal(t, NotPinnedPackageIssue, issue.Type)
+		}
+	}
+}
@@ -0,0 +1,399 @@
+package xcodeproj
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+)
+
+// singleLineObjectTypes are written on a single line by Xcode.
+var singleLineObjectTypes = map[string]bool{
+	"PBXBuildFile":     true,
+	"PBXFileReference": true,
+}
+
+// uncommentedReferenceKeys hold object IDs, which are not annotated with a comment by Xcode.
+var uncommentedReferenceKeys = map[string]bool{
+	"remoteGlobalIDString": true,
+	// the project attributes (like TargetAttributes) are not annotated
+	"attributes": true,
+}
+
+// pbxprojEncoder writes a project.pbxproj file the same way as Xcode does:
+// objects are grouped into sections by isa and sorted by ID, references are annotated with the referenced object's name.
+type pbxprojEncoder struct {
+	objects     serialized.Object
+	projectName string
+	comments    map[string]string
+}
+
+func newPBXProjEncoder(objects serialized.Object, projectName string) pbxprojEncoder {
+	e := pbxprojEncoder{
+		objects:     objects,
+		projectName: projectName,
+	}
+	e.comments = e.objectComments()
+	return e
+}
+
+// Marshal returns the contents of the project.pbxproj file in Xcode's format.
+func (p XcodeProj) Marshal() ([]byte, error) {
+	objects, err := p.RawProj.Object("objects")
+	if err != nil {
+		return nil, fmt.Errorf("failed to access objects: %s", err)
+	}
+
+	e := newPBXProjEncoder(objects, p.Name)
+
+	var b strings.Builder
+	b.WriteString("// !$*UTF8*$!\n{\n")
+	for _, key := range sortedKeys(p.RawProj) {
+		if key == customAnnotationKey {
+			continue
+		}
+
+		if key == "objects" {
+			b.WriteString("\tobjects = {\n")
+			if err := e.writeObjects(&b); err != nil {
+				return nil, err
+			}
+			b.WriteString("\t};\n")
+			continue
+		}
+
+		b.WriteString("\t" + quote(key) + " = ")
+		e.writeValue(&b, key, p.RawProj[key], 1, false)
+		b.WriteString(";\n")
+	}
+	b.WriteString("}\n")
+
+	return []byte(b.String()), nil
+}
+
+func (e pbxprojEncoder) writeObjects(b *strings.Builder) error {
+	idsByISA := map[string][]string{}
+	for id := range e.objects {
+		if id == customAnnotationKey {
+			continue
+		}
+
+		object, err := e.objects.Object(id)
+		if err != nil {
+			return fmt.Errorf("failed to access object (%s): %s", id, err)
+		}
+
+		isa, err := object.String("isa")
+		if err != nil {
+			return fmt.Errorf("failed to read isa of object (%s): %s", id, err)
+		}
+
+		idsByISA[isa] = append(idsByISA[isa], id)
+	}
+
+	var isas []string
+	for isa := range idsByISA {
+		isas = append(isas, isa)
+	}
+	sort.Strings(isas)
+
+	for _, isa := range isas {
+		ids := idsByISA[isa]
+		sort.Strings(ids)
+
+		b.WriteString("\n/* Begin " + isa + " section */\n")
+		for _, id := range ids {
+			entry, err := e.objectEntry(id)
+			if err != nil {
+				return err
+			}
+			b.WriteString(entry)
+		}
+		b.WriteString("/* End " + isa + " section */\n")
+	}
+
+	return nil
+}
+
+// objectEntry returns the object's line(s) in the objects section: `ID /* comment */ = {...};`
+func (e pbxprojEncoder) objectEntry(id string) (string, error) {
+	value, err := e.objectValue(id)
+	if err != nil {
+		return "", err
+	}
+	return "\t\t" + e.reference(id) + " = " + value + ";\n", nil
+}
+
+// objectValue returns the object's dictionary (from `{` to `}`) as written in the objects section.
+func (e pbxprojEncoder) objectValue(id string) (string, error) {
+	object, err := e.objects.Object(id)
+	if err != nil {
+		return "", fmt.Errorf("failed to access object (%s): %s", id, err)
+	}
+
+	isa, err := object.String("isa")
+	if err != nil {
+		return "", fmt.Errorf("failed to read isa of object (%s): %s", id, err)
+	}
+
+	var b strings.Builder
+	e.writeDictionary(&b, object, 2, singleLineObjectTypes[isa], true)
+	return b.String(), nil
+}
+
+func (e pbxprojEncoder) writeValue(b *strings.Builder, key string, value interface{}, indent int, singleLine bool) {
+	if object, ok := value.(serialized.Object); ok {
+		value = map[string]interface{}(object)
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		if uncommentedReferenceKeys[key] {
+			e.withoutComments().writeDictionary(b, value, indent, singleLine, false)
+			return
+		}
+		e.writeDictionary(b, value, indent, singleLine, false)
+	case []interface{}:
+		e.writeArray(b, key, value, indent, singleLine)
+	case string:
+		if uncommentedReferenceKeys[key] {
+			b.WriteString(quote(value))
+			return
+		}
+		b.WriteString(e.reference(value))
+	default:
+		b.WriteString(quote(fmt.Sprint(value)))
+	}
+}
+
+func (e pbxprojEncoder) writeDictionary(b *strings.Builder, dict map[string]interface{}, indent int, singleLine, isaFirst bool) {
+	keys := sortedKeys(dict)
+	if isaFirst {
+		for i, key := range keys {
+			if key == "isa" {
+				keys = append([]string{key}, append(keys[:i:i], keys[i+1:]...)...)
+				break
+			}
+		}
+	}
+
+	b.WriteString("{")
+	if !singleLine {
+		b.WriteString("\n")
+	}
+	for _, key := range keys {
+		if key == customAnnotationKey {
+			continue
+		}
+
+		if !singleLine {
+			b.WriteString(strings.Repeat("\t", indent+1))
+		}
+		b.WriteString(quote(key) + " = ")
+		e.writeValue(b, key, dict[key], indent+1, singleLine)
+		if singleLine {
+			b.WriteString("; ")
+		} else {
+			b.WriteString(";\n")
+		}
+	}
+	if !singleLine {
+		b.WriteString(strings.Repeat("\t", indent))
+	}
+	b.WriteString("}")
+}
+
+func (e pbxprojEncoder) writeArray(b *strings.Builder, key string, array []interface{}, indent int, singleLine bool) {
+	b.WriteString("(")
+	if !singleLine {
+		b.WriteString("\n")
+	}
+	for _, item := range array {
+		if !singleLine {
+			b.WriteString(strings.Repeat("\t", indent+1))
+		}
+		e.writeValue(b, key, item, indent+1, singleLine)
+		if singleLine {
+			b.WriteString(", ")
+		} else {
+			b.WriteString(",\n")
+		}
+	}
+	if !singleLine {
+		b.WriteString(strings.Repeat("\t", indent))
+	}
+	b.WriteString(")")
+}
+
+func (e pbxprojEncoder) withoutComments() pbxprojEncoder {
+	return pbxprojEncoder{objects: e.objects, projectName: e.projectName}
+}
+
+// reference returns the value quoted if needed, followed by the referenced object's comment if the value is an object ID.
+func (e pbxprojEncoder) reference(value string) string {
+	if comment, ok := e.comments[value]; ok && comment != "" {
+		return quote(value) + " /* " + comment + " */"
+	}
+	return quote(value)
+}
+
+// objectComments returns the comments Xcode writes after the object IDs, by object ID.
+func (e pbxprojEncoder) objectComments() map[string]string {
+	phaseNameByBuildFile := map[string]string{}
+	configurationListOwners := map[string]serialized.Object{}
+	for id := range e.objects {
+		object, err := e.objects.Object(id)
+		if err != nil {
+			continue
+		}
+
+		if configurationListID, err := object.String("buildConfigurationList"); err == nil {
+			configurationListOwners[configurationListID] = object
+		}
+
+		isa := optionalString(object, "isa")
+		if !strings.HasSuffix(isa, "BuildPhase") {
+			continue
+		}
+
+		phase := BuildPhase{Type: BuildPhaseType(isa), Name: optionalString(object, "name")}
+		files, err := object.StringSlice("files")
+		if err != nil {
+			continue
+		}
+		for _, file := range files {
+			phaseNameByBuildFile[file] = phase.DisplayName()
+		}
+	}
+
+	comments := map[string]string{}
+	for id := range e.objects {
+		object, err := e.objects.Object(id)
+		if err != nil {
+			continue
+		}
+
+		isa := optionalString(object, "isa")
+		switch {
+		case isa == "PBXProject":
+			comments[id] = "Project object"
+		case isa == "PBXBuildFile":
+			name := e.buildFileName(object)
+			if phaseName, ok := phaseNameByBuildFile[id]; ok {
+				name = fmt.Sprintf("%s in %s", name, phaseName)
+			}
+			comments[id] = name
+		case strings.HasSuffix(isa, "BuildPhase"):
+			comments[id] = BuildPhase{Type: BuildPhaseType(isa), Name: optionalString(object, "name")}.DisplayName()
+		case isa == "XCConfigurationList":
+			owner, ok := configurationListOwners[id]
+			if !ok {
+				comments[id] = isa
+				continue
+			}
+
+			ownerISA := optionalString(owner, "isa")
+			ownerName := optionalString(owner, "name")
+			if ownerISA == "PBXProject" {
+				ownerName = e.projectName
+			}
+			comments[id] = fmt.Sprintf("Build configuration list for %s \"%s\"", ownerISA, ownerName)
+		case isa == "XCRemoteSwiftPackageReference":
+			comments[id] = fmt.Sprintf("%s \"%s\"", isa, packageName(optionalString(object, "repositoryURL")))
+		case isa == "XCLocalSwiftPackageReference":
+			comments[id] = fmt.Sprintf("%s \"%s\"", isa, optionalString(object, "relativePath"))
+		case isa == "XCSwiftPackageProductDependency":
+			comments[id] = optionalString(object, "productName")
+		case isFileElementType(isa):
+			comments[id] = fileElementName(object)
+		case isa == "XCBuildConfiguration" || strings.HasSuffix(isa, "Target"):
+			comments[id] = optionalString(object, "name")
+		default:
+			comments[id] = isa
+		}
+	}
+
+	return comments
+}
+
+func (e pbxprojEncoder) buildFileName(buildFile serialized.Object) string {
+	if fileRef, err := buildFile.String("fileRef"); err == nil {
+		if fileElement, err := e.objects.Object(fileRef); err == nil {
+			return fileElementName(fileElement)
+		}
+	}
+	if productRef, err := buildFile.String("productRef"); err == nil {
+		if product, err := e.objects.Object(productRef); err == nil {
+			return optionalString(product, "productName")
+		}
+	}
+	return "(null)"
+}
+
+func isFileElementType(isa string) bool {
+	return isGroupType(isa) || isa == fileReferenceElementType || isa == "PBXReferenceProxy"
+}
+
+// fileElementName returns the name of a file tree element: its name or the last component of its path.
+func fileElementName(element serialized.Object) string {
+	if name := optionalString(element, "name"); name != "" {
+		return name
+	}
+	if pth := optionalString(element, "path"); pth != "" {
+		return filepath.Base(pth)
+	}
+	return ""
+}
+
+// packageName returns the name of a remote package: https://github.com/Alamofire/Alamofire.git -> Alamofire
+func packageName(repositoryURL string) string {
+	return strings.TrimSuffix(filepath.Base(strings.TrimRight(repositoryURL, "/")), ".git")
+}
+
+// quote returns the string as written by Xcode: strings containing only letters, digits and `_$./`
+// are written as they are (unless they contain `//` or `___`), the others are quoted and escaped.
+func quote(s string) string {
+	if s != "" && !strings.Contains(s, "//") && !strings.Contains(s, "___") {
+		plain := true
+		for _, r := range s {
+			if !(unicode.IsLetter(r) || (r >= '.' && r <= '9') || r == '_' || r == '$') {
+				plain = false
+				break
+			}
+		}
+		if plain {
+			return s
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(`"`)
+	for _, r := range s {
+		switch r {
+		case '\\':
+			b.WriteString(`\\`)
+		case '"':
+			b.WriteString(`\"`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\n':
+			b.WriteString(`\n`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteString(`"`)
+	return b.String()
+}
+
+func sortedKeys(dict map[string]interface{}) []string {
+	var keys []string
+	for key := range dict {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,87 @@
+package xcodeproj
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/go-plist"
+	"github.com/bitrise-io/xcode-project/serialized"
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXcodeProj_Marshal(t *testing.T) {
+	catalystProj := "// !$*UTF8*$!\n{\n\tarchiveVersion = 1;\n\tclasses = {\n\t};\n\tobjectVersion = 52;\n\tobjects = " +
+		rawCatalystProj[:len(rawCatalystProj)-2] + "/* End XCConfigurationList section */\n\t};\n\trootObject = 13917C0A243F43D00087912B /* Project object */;\n}\n"
+
+	tests := []struct {
+		name        string
+		projectName string
+		content     string
+	}{
+		{
+			name:        "project saved by Xcode",
+			projectName: "XcodeProj",
+			content:     testhelper.XcodeProjectTest,
+		},
+		{
+			name:        "project with spaces in the name",
+			projectName: "Catalyst Sample",
+			content:     catalystProj,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proj, err := parsePBXProjContent([]byte(tt.content))
+			require.NoError(t, err)
+			proj.Name = tt.projectName
+
+			got, err := proj.Marshal()
+			require.NoError(t, err)
+			require.Equal(t, tt.content, string(got))
+		})
+	}
+}
+
+func TestPBXProjEncoder_objectComments(t *testing.T) {
+	var raw serialized.Object
+	_, err := plist.Unmarshal([]byte(rawSwiftPackages), &raw)
+	require.NoError(t, err)
+
+	objects, err := raw.Object("objects")
+	require.NoError(t, err)
+
+	comments := newPBXProjEncoder(objects, "App").objectComments()
+	for id, want := range map[string]string{
+		"2C1A2F5025F8D38000D1A2B1": "Project object",
+		"2C1A2F5125F8D38000D1A2B1": `Build configuration list for PBXProject "App"`,
+		"2C1A2F5625F8D38000D1A2B1": `Build configuration list for PBXNativeTarget "App"`,
+		"2C1A2F5325F8D38000D1A2B1": "App",
+		"2C1A2F5525F8D38000D1A2B1": "Debug",
+		"2C1A2F5225F8D38000D1A2B1": "",
+		"2C1A2F6A25F8D3A900D1A2B1": `XCRemoteSwiftPackageReference "Alamofire"`,
+		"2C1A2F7325F8D3E800D1A2B1": `XCRemoteSwiftPackageReference "swift-snapshot-testing"`,
+		"2C1A2F7925F8D40E00D1A2B1": `XCLocalSwiftPackageReference "Packages/Core"`,
+		"2C1A2F7425F8D3E800D1A2B1": "SnapshotTesting",
+	} {
+		require.Equal(t, want, comments[id], id)
+	}
+}
+
+func TestQuote(t *testing.T) {
+	for value, want := range map[string]string{
+		"":                                   `""`,
+		"PBXBuildFile":                       "PBXBuildFile",
+		"14.0":                               "14.0",
+		"Target/Info.plist":                  "Target/Info.plist",
+		"$SRCROOT":                           "$SRCROOT",
+		"com.apple.product-type.application": `"com.apple.product-type.application"`,
+		"$(inherited)":                       `"$(inherited)"`,
+		"<group>":                            `"<group>"`,
+		"Krisztián":                          "Krisztián",
+		"http://bitrise.io":                  `"http://bitrise.io"`,
+		"A___B":                              `"A___B"`,
+		"say \"hi\"\n\tbye\\":                `"say \"hi\"\n\tbye\\"`,
+	} {
+		require.Equal(t, want, quote(value), value)
+	}
+}
@@ -12,6 +12,10 @@ type Proj struct {
 	BuildConfigurationList ConfigurationList
 	Targets                []Target
 	Attributes             ProjectAtributes
+	// MainGroup is the root of the project's file tree, nil if the project has no main group.
+	MainGroup *Group
+	// SwiftPackageReferences are the Swift packages added to the project.
+	SwiftPackageReferences []SwiftPackageReference
 }
 
 func parseProj(id string, objects serialized.Object) (Proj, error) {
@@ -40,6 +44,7 @@ func parseProj(id string, objects serialized.Object) (Proj, error) {
 		return Proj{}, fmt.Errorf("failed to access targets: %s", err)
 	}
 
+	parser := newTargetParser(objects)
 	var targets []Target
 	for _, targetID := range rawTargets {
 		// rawTargets can contain more target IDs than the project configuration has
@@ -52,21 +57,62 @@ func parseProj(id string, objects serialized.Object) (Proj, error) {
 			continue
 		}
 
-		target, err := parseTarget(targetID, objects)
+		target, err := parser.parse(targetID)
 		if err != nil {
 			return Proj{}, fmt.Errorf("failed to parse target with id: %s: %s", targetID, err)
 		}
 		targets = append(targets, target)
 	}
 
+	mainGroup, err := parseMainGroup(rawPBXProj, objects)
+	if err != nil {
+		return Proj{}, fmt.Errorf("failed to parse main group: %s", err)
+	}
+
+	packageReferenceIDs, err := rawPBXProj.StringSlice("packageReferences")
+	if err != nil && !serialized.IsKeyNotFoundError(err) {
+		return Proj{}, fmt.Errorf("failed to access package references: %s", err)
+	}
+
+	var packageReferences []SwiftPackageReference
+	for _, packageReferenceID := range packageReferenceIDs {
+		packageReference, err := parseSwiftPackageReference(packageReferenceID, objects)
+		if err != nil {
+			return Proj{}, fmt.Errorf("failed to parse package reference (%s): %s", packageReferenceID, err)
+		}
+		packageReferences = append(packageReferences, packageReference)
+	}
+
 	return Proj{
 		ID:                     id,
 		BuildConfigurationList: buildConfigurationList,
 		Targets:                targets,
 		Attributes:             projectAttributes,
+		MainGroup:              mainGroup,
+		SwiftPackageReferences: packageReferences,
 	}, nil
 }
 
+// parseMainGroup returns nil if the project has no main group or the group object is missing.
+func parseMainGroup(rawPBXProj, objects serialized.Object) (*Group, error) {
+	mainGroupID, err := rawPBXProj.String("mainGroup")
+	if err != nil {
+		if serialized.IsKeyNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	if _, err := objects.Object(mainGroupID); err != nil {
+		if serialized.IsKeyNotFoundError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return parseGroup(mainGroupID, objects, nil, map[string]bool{})
+}
+
 func hasTargetNode(id string, objects serialized.Object) (bool, error) {
 	if _, err := objects.Object(id); err != nil {
 		if serialized.IsKeyNotFoundError(err) {
@@ -96,3 +142,19 @@ func (p Proj) TargetByName(name string) (Target, bool) {
 	}
 	return Target{}, false
 }
+
+// FileReferences returns all the file references of the project's file tree.
+func (p Proj) FileReferences() []*FileReference {
+	if p.MainGroup == nil {
+		return nil
+	}
+	return p.MainGroup.FileReferences()
+}
+
+// Element returns the project file tree element with the given ID.
+func (p Proj) Element(id string) (Element, bool) {
+	if p.MainGroup == nil {
+		return nil, false
+	}
+	return p.MainGroup.Element(id)
+}
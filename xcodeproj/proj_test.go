@@ -544,7 +544,9 @@ const expectedProj = `{
 					"ONLY_ACTIVE_ARCH": "YES",
 					"SDKROOT": "iphoneos",
 					"TARGETED_DEVICE_FAMILY": "1,2"
-				}
+				},
+				"BaseConfigurationReference": "",
+				"BaseConfiguration": null
 			},
 			{
 				"ID": "BA3CBE9A19F7A93900CED4D5",
@@ -583,7 +585,9 @@ const expectedProj = `{
 					"SDKROOT": "iphoneos",
 					"TARGETED_DEVICE_FAMILY": "1,2",
 					"VALIDATE_PRODUCT": "YES"
-				}
+				},
+				"BaseConfigurationReference": "",
+				"BaseConfiguration": null
 			}
 		]
 	},
@@ -610,7 +614,9 @@ const expectedProj = `{
 							"PRODUCT_NAME": "$(TARGET_NAME)",
 							"PROVISIONING_PROFILE": "",
 							"PROVISIONING_PROFILE_SPECIFIER": "BitriseBot-Wildcard"
-						}
+						},
+						"BaseConfigurationReference": "",
+						"BaseConfiguration": null
 					},
 					{
 						"ID": "BA3CBE9D19F7A93900CED4D5",
@@ -626,7 +632,9 @@ const expectedProj = `{
 							"PRODUCT_NAME": "$(TARGET_NAME)",
 							"PROVISIONING_PROFILE": "",
 							"PROVISIONING_PROFILE_SPECIFIER": "BitriseBot-Wildcard"
-						}
+						},
+						"BaseConfigurationReference": "",
+						"BaseConfiguration": null
 					}
 				]
 			},
@@ -634,7 +642,105 @@ const expectedProj = `{
 			"ProductReference": {
 				"Path": "ios-simple-objc.app"
 			},
-			"ProductType": "com.apple.product-type.application"
+			"ProductType": "com.apple.product-type.application",
+			"BuildPhases": [
+				{
+					"Type": "PBXSourcesBuildPhase",
+					"ID": "BA3CBE7119F7A93800CED4D5",
+					"Name": "",
+					"Files": [
+						{
+							"ID": "BA3CBE7E19F7A93900CED4D5",
+							"FileRef": "BA3CBE7D19F7A93900CED4D5",
+							"ProductRef": "",
+							"Settings": null
+						},
+						{
+							"ID": "BA3CBE7B19F7A93800CED4D5",
+							"FileRef": "BA3CBE7A19F7A93800CED4D5",
+							"ProductRef": "",
+							"Settings": null
+						},
+						{
+							"ID": "BA3CBE8419F7A93900CED4D5",
+							"FileRef": "BA3CBE8319F7A93900CED4D5",
+							"ProductRef": "",
+							"Settings": null
+						},
+						{
+							"ID": "BA3CBE8119F7A93900CED4D5",
+							"FileRef": "BA3CBE7F19F7A93900CED4D5",
+							"ProductRef": "",
+							"Settings": null
+						}
+					],
+					"RunOnlyForDeploymentPostprocessing": false,
+					"DstSubfolderSpec": 0,
+					"DstPath": "",
+					"ShellPath": "",
+					"ShellScript": "",
+					"InputPaths": null,
+					"OutputPaths": null,
+					"InputFileListPaths": null,
+					"OutputFileListPaths": null,
+					"ShowEnvVarsInLog": false,
+					"AlwaysOutOfDate": false
+				},
+				{
+					"Type": "PBXFrameworksBuildPhase",
+					"ID": "BA3CBE7219F7A93800CED4D5",
+					"Name": "",
+					"Files": null,
+					"RunOnlyForDeploymentPostprocessing": false,
+					"DstSubfolderSpec": 0,
+					"DstPath": "",
+					"ShellPath": "",
+					"ShellScript": "",
+					"InputPaths": null,
+					"OutputPaths": null,
+					"InputFileListPaths": null,
+					"OutputFileListPaths": null,
+					"ShowEnvVarsInLog": false,
+					"AlwaysOutOfDate": false
+				},
+				{
+					"Type": "PBXResourcesBuildPhase",
+					"ID": "BA3CBE7319F7A93800CED4D5",
+					"Name": "",
+					"Files": [
+						{
+							"ID": "BA3CBE8719F7A93900CED4D5",
+							"FileRef": "BA3CBE8519F7A93900CED4D5",
+							"ProductRef": "",
+							"Settings": null
+						},
+						{
+							"ID": "BA3CBE8C19F7A93900CED4D5",
+							"FileRef": "BA3CBE8A19F7A93900CED4D5",
+							"ProductRef": "",
+							"Settings": null
+						},
+						{
+							"ID": "BA3CBE8919F7A93900CED4D5",
+							"FileRef": "BA3CBE8819F7A93900CED4D5",
+							"ProductRef": "",
+							"Settings": null
+						}
+					],
+					"RunOnlyForDeploymentPostprocessing": false,
+					"DstSubfolderSpec": 0,
+					"DstPath": "",
+					"ShellPath": "",
+					"ShellScript": "",
+					"InputPaths": null,
+					"OutputPaths": null,
+					"InputFileListPaths": null,
+					"OutputFileListPaths": null,
+					"ShowEnvVarsInLog": false,
+					"AlwaysOutOfDate": false
+				}
+			],
+			"PackageProductDependencies": null
 		},
 		{
 			"Type": "PBXNativeTarget",
@@ -662,7 +768,9 @@ const expectedProj = `{
 							"PRODUCT_BUNDLE_IDENTIFIER": "Bitrise.$(PRODUCT_NAME:rfc1034identifier)",
 							"PRODUCT_NAME": "$(TARGET_NAME)",
 							"TEST_HOST": "$(BUILT_PRODUCTS_DIR)/ios-simple-objc.app/ios-simple-objc"
-						}
+						},
+						"BaseConfigurationReference": "",
+						"BaseConfiguration": null
 					},
 					{
 						"ID": "BA3CBEA019F7A93900CED4D5",
@@ -678,7 +786,9 @@ const expectedProj = `{
 							"PRODUCT_BUNDLE_IDENTIFIER": "Bitrise.$(PRODUCT_NAME:rfc1034identifier)",
 							"PRODUCT_NAME": "$(TARGET_NAME)",
 							"TEST_HOST": "$(BUILT_PRODUCTS_DIR)/ios-simple-objc.app/ios-simple-objc"
-						}
+						},
+						"BaseConfigurationReference": "",
+						"BaseConfiguration": null
 					}
 				]
 			},
@@ -707,7 +817,9 @@ const expectedProj = `{
 										"PRODUCT_NAME": "$(TARGET_NAME)",
 										"PROVISIONING_PROFILE": "",
 										"PROVISIONING_PROFILE_SPECIFIER": "BitriseBot-Wildcard"
-									}
+									},
+									"BaseConfigurationReference": "",
+									"BaseConfiguration": null
 								},
 								{
 									"ID": "BA3CBE9D19F7A93900CED4D5",
@@ -723,7 +835,9 @@ const expectedProj = `{
 										"PRODUCT_NAME": "$(TARGET_NAME)",
 										"PROVISIONING_PROFILE": "",
 										"PROVISIONING_PROFILE_SPECIFIER": "BitriseBot-Wildcard"
-									}
+									},
+									"BaseConfigurationReference": "",
+									"BaseConfiguration": null
 								}
 							]
 						},
@@ -731,14 +845,173 @@ const expectedProj = `{
 						"ProductReference": {
 							"Path": "ios-simple-objc.app"
 						},
-						"ProductType": "com.apple.product-type.application"
+						"ProductType": "com.apple.product-type.application",
+						"BuildPhases": [
+							{
+								"Type": "PBXSourcesBuildPhase",
+								"ID": "BA3CBE7119F7A93800CED4D5",
+								"Name": "",
+								"Files": [
+									{
+										"ID": "BA3CBE7E19F7A93900CED4D5",
+										"FileRef": "BA3CBE7D19F7A93900CED4D5",
+										"ProductRef": "",
+										"Settings": null
+									},
+									{
+										"ID": "BA3CBE7B19F7A93800CED4D5",
+										"FileRef": "BA3CBE7A19F7A93800CED4D5",
+										"ProductRef": "",
+										"Settings": null
+									},
+									{
+										"ID": "BA3CBE8419F7A93900CED4D5",
+										"FileRef": "BA3CBE8319F7A93900CED4D5",
+										"ProductRef": "",
+										"Settings": null
+									},
+									{
+										"ID": "BA3CBE8119F7A93900CED4D5",
+										"FileRef": "BA3CBE7F19F7A93900CED4D5",
+										"ProductRef": "",
+										"Settings": null
+									}
+								],
+								"RunOnlyForDeploymentPostprocessing": false,
+								"DstSubfolderSpec": 0,
+								"DstPath": "",
+								"ShellPath": "",
+								"ShellScript": "",
+								"InputPaths": null,
+								"OutputPaths": null,
+								"InputFileListPaths": null,
+								"OutputFileListPaths": null,
+								"ShowEnvVarsInLog": false,
+								"AlwaysOutOfDate": false
+							},
+							{
+								"Type": "PBXFrameworksBuildPhase",
+								"ID": "BA3CBE7219F7A93800CED4D5",
+								"Name": "",
+								"Files": null,
+								"RunOnlyForDeploymentPostprocessing": false,
+								"DstSubfolderSpec": 0,
+								"DstPath": "",
+								"ShellPath": "",
+								"ShellScript": "",
+								"InputPaths": null,
+								"OutputPaths": null,
+								"InputFileListPaths": null,
+								"OutputFileListPaths": null,
+								"ShowEnvVarsInLog": false,
+								"AlwaysOutOfDate": false
+							},
+							{
+								"Type": "PBXResourcesBuildPhase",
+								"ID": "BA3CBE7319F7A93800CED4D5",
+								"Name": "",
+								"Files": [
+									{
+										"ID": "BA3CBE8719F7A93900CED4D5",
+										"FileRef": "BA3CBE8519F7A93900CED4D5",
+										"ProductRef": "",
+										"Settings": null
+									},
+									{
+										"ID": "BA3CBE8C19F7A93900CED4D5",
+										"FileRef": "BA3CBE8A19F7A93900CED4D5",
+										"ProductRef": "",
+										"Settings": null
+									},
+									{
+										"ID": "BA3CBE8919F7A93900CED4D5",
+										"FileRef": "BA3CBE8819F7A93900CED4D5",
+										"ProductRef": "",
+										"Settings": null
+									}
+								],
+								"RunOnlyForDeploymentPostprocessing": false,
+								"DstSubfolderSpec": 0,
+								"DstPath": "",
+								"ShellPath": "",
+								"ShellScript": "",
+								"InputPaths": null,
+								"OutputPaths": null,
+								"InputFileListPaths": null,
+								"OutputFileListPaths": null,
+								"ShowEnvVarsInLog": false,
+								"AlwaysOutOfDate": false
+							}
+						],
+						"PackageProductDependencies": null
 					}
 				}
 			],
 			"ProductReference": {
 				"Path": "ios-simple-objcTests.xctest"
 			},
-			"ProductType": "com.apple.product-type.bundle.unit-test"
+			"ProductType": "com.apple.product-type.bundle.unit-test",
+			"BuildPhases": [
+				{
+					"Type": "PBXSourcesBuildPhase",
+					"ID": "BA3CBE8D19F7A93900CED4D5",
+					"Name": "",
+					"Files": [
+						{
+							"ID": "BA3CBE9819F7A93900CED4D5",
+							"FileRef": "BA3CBE9719F7A93900CED4D5",
+							"ProductRef": "",
+							"Settings": null
+						}
+					],
+					"RunOnlyForDeploymentPostprocessing": false,
+					"DstSubfolderSpec": 0,
+					"DstPath": "",
+					"ShellPath": "",
+					"ShellScript": "",
+					"InputPaths": null,
+					"OutputPaths": null,
+					"InputFileListPaths": null,
+					"OutputFileListPaths": null,
+					"ShowEnvVarsInLog": false,
+					"AlwaysOutOfDate": false
+				},
+				{
+					"Type": "PBXFrameworksBuildPhase",
+					"ID": "BA3CBE8E19F7A93900CED4D5",
+					"Name": "",
+					"Files": null,
+					"RunOnlyForDeploymentPostprocessing": false,
+					"DstSubfolderSpec": 0,
+					"DstPath": "",
+					"ShellPath": "",
+					"ShellScript": "",
+					"InputPaths": null,
+					"OutputPaths": null,
+					"InputFileListPaths": null,
+					"OutputFileListPaths": null,
+					"ShowEnvVarsInLog": false,
+					"AlwaysOutOfDate": false
+				},
+				{
+					"Type": "PBXResourcesBuildPhase",
+					"ID": "BA3CBE8F19F7A93900CED4D5",
+					"Name": "",
+					"Files": null,
+					"RunOnlyForDeploymentPostprocessing": false,
+					"DstSubfolderSpec": 0,
+					"DstPath": "",
+					"ShellPath": "",
+					"ShellScript": "",
+					"InputPaths": null,
+					"OutputPaths": null,
+					"InputFileListPaths": null,
+					"OutputFileListPaths": null,
+					"ShowEnvVarsInLog": false,
+					"AlwaysOutOfDate": false
+				}
+			],
+			"PackageProductDependencies": null
 		}
 	],
 	"Attributes": {
@@ -753,5 +1026,213 @@ const expectedProj = `{
 				"TestTargetID": "BA3CBE7419F7A93800CED4D5"
 			}
 		}
-	}
+	},
+	"MainGroup": {
+		"Type": "PBXGroup",
+		"ID": "BA3CBE6C19F7A93800CED4D5",
+		"Name": "",
+		"Path": "",
+		"SourceTree": "\u003cgroup\u003e",
+		"Children": [
+			{
+				"Type": "PBXGroup",
+				"ID": "BA3CBE7719F7A93800CED4D5",
+				"Name": "",
+				"Path": "ios-simple-objc",
+				"SourceTree": "\u003cgroup\u003e",
+				"Children": [
+					{
+						"ID": "BA3CBE7C19F7A93800CED4D5",
+						"Name": "",
+						"Path": "AppDelegate.h",
+						"SourceTree": "\u003cgroup\u003e",
+						"LastKnownFileType": "sourcecode.c.h",
+						"ExplicitFileType": "",
+						"FileEncoding": ""
+					},
+					{
+						"ID": "BA3CBE7D19F7A93900CED4D5",
+						"Name": "",
+						"Path": "AppDelegate.m",
+						"SourceTree": "\u003cgroup\u003e",
+						"LastKnownFileType": "sourcecode.c.objc",
+						"ExplicitFileType": "",
+						"FileEncoding": ""
+					},
+					{
+						"ID": "BA3CBE8219F7A93900CED4D5",
+						"Name": "",
+						"Path": "ViewController.h",
+						"SourceTree": "\u003cgroup\u003e",
+						"LastKnownFileType": "sourcecode.c.h",
+						"ExplicitFileType": "",
+						"FileEncoding": ""
+					},
+					{
+						"ID": "BA3CBE8319F7A93900CED4D5",
+						"Name": "",
+						"Path": "ViewController.m",
+						"SourceTree": "\u003cgroup\u003e",
+						"LastKnownFileType": "sourcecode.c.objc",
+						"ExplicitFileType": "",
+						"FileEncoding": ""
+					},
+					{
+						"Type": "PBXVariantGroup",
+						"ID": "BA3CBE8519F7A93900CED4D5",
+						"Name": "Main.storyboard",
+						"Path": "",
+						"SourceTree": "\u003cgroup\u003e",
+						"Children": [
+							{
+								"ID": "BA3CBE8619F7A93900CED4D5",
+								"Name": "Base",
+								"Path": "Base.lproj/Main.storyboard",
+								"SourceTree": "\u003cgroup\u003e",
+								"LastKnownFileType": "file.storyboard",
+								"ExplicitFileType": "",
+								"FileEncoding": ""
+							}
+						]
+					},
+					{
+						"ID": "BA3CBE8819F7A93900CED4D5",
+						"Name": "",
+						"Path": "Images.xcassets",
+						"SourceTree": "\u003cgroup\u003e",
+						"LastKnownFileType": "folder.assetcatalog",
+						"ExplicitFileType": "",
+						"FileEncoding": ""
+					},
+					{
+						"Type": "PBXVariantGroup",
+						"ID": "BA3CBE8A19F7A93900CED4D5",
+						"Name": "LaunchScreen.xib",
+						"Path": "",
+						"SourceTree": "\u003cgroup\u003e",
+						"Children": [
+							{
+								"ID": "BA3CBE8B19F7A93900CED4D5",
+								"Name": "Base",
+								"Path": "Base.lproj/LaunchScreen.xib",
+								"SourceTree": "\u003cgroup\u003e",
+								"LastKnownFileType": "file.xib",
+								"ExplicitFileType": "",
+								"FileEncoding": ""
+							}
+						]
+					},
+					{
+						"Type": "XCVersionGroup",
+						"ID": "BA3CBE7F19F7A93900CED4D5",
+						"Name": "",
+						"Path": "ios_simple_objc.xcdatamodeld",
+						"SourceTree": "\u003cgroup\u003e",
+						"Children": [
+							{
+								"ID": "BA3CBE8019F7A93900CED4D5",
+								"Name": "",
+								"Path": "ios_simple_objc.xcdatamodel",
+								"SourceTree": "\u003cgroup\u003e",
+								"LastKnownFileType": "wrapper.xcdatamodel",
+								"ExplicitFileType": "",
+								"FileEncoding": ""
+							}
+						]
+					},
+					{
+						"Type": "PBXGroup",
+						"ID": "BA3CBE7819F7A93800CED4D5",
+						"Name": "Supporting Files",
+						"Path": "",
+						"SourceTree": "\u003cgroup\u003e",
+						"Children": [
+							{
+								"ID": "BA3CBE7919F7A93800CED4D5",
+								"Name": "",
+								"Path": "Info.plist",
+								"SourceTree": "\u003cgroup\u003e",
+								"LastKnownFileType": "text.plist.xml",
+								"ExplicitFileType": "",
+								"FileEncoding": ""
+							},
+							{
+								"ID": "BA3CBE7A19F7A93800CED4D5",
+								"Name": "",
+								"Path": "main.m",
+								"SourceTree": "\u003cgroup\u003e",
+								"LastKnownFileType": "sourcecode.c.objc",
+								"ExplicitFileType": "",
+								"FileEncoding": ""
+							}
+						]
+					}
+				]
+			},
+			{
+				"Type": "PBXGroup",
+				"ID": "BA3CBE9419F7A93900CED4D5",
+				"Name": "",
+				"Path": "ios-simple-objcTests",
+				"SourceTree": "\u003cgroup\u003e",
+				"Children": [
+					{
+						"ID": "BA3CBE9719F7A93900CED4D5",
+						"Name": "",
+						"Path": "ios_simple_objcTests.m",
+						"SourceTree": "\u003cgroup\u003e",
+						"LastKnownFileType": "sourcecode.c.objc",
+						"ExplicitFileType": "",
+						"FileEncoding": ""
+					},
+					{
+						"Type": "PBXGroup",
+						"ID": "BA3CBE9519F7A93900CED4D5",
+						"Name": "Supporting Files",
+						"Path": "",
+						"SourceTree": "\u003cgroup\u003e",
+						"Children": [
+							{
+								"ID": "BA3CBE9619F7A93900CED4D5",
+								"Name": "",
+								"Path": "Info.plist",
+								"SourceTree": "\u003cgroup\u003e",
+								"LastKnownFileType": "text.plist.xml",
+								"ExplicitFileType": "",
+								"FileEncoding": ""
+							}
+						]
+					}
+				]
+			},
+			{
+				"Type": "PBXGroup",
+				"ID": "BA3CBE7619F7A93800CED4D5",
+				"Name": "Products",
+				"Path": "",
+				"SourceTree": "\u003cgroup\u003e",
+				"Children": [
+					{
+						"ID": "BA3CBE7519F7A93800CED4D5",
+						"Name": "",
+						"Path": "ios-simple-objc.app",
+						"SourceTree": "BUILT_PRODUCTS_DIR",
+						"LastKnownFileType": "",
+						"ExplicitFileType": "wrapper.application",
+						"FileEncoding": ""
+					},
+					{
+						"ID": "BA3CBE9119F7A93900CED4D5",
+						"Name": "",
+						"Path": "ios-simple-objcTests.xctest",
+						"SourceTree": "BUILT_PRODUCTS_DIR",
+						"LastKnownFileType": "",
+						"ExplicitFileType": "wrapper.cfbundle",
+						"FileEncoding": ""
+					}
+				]
+			}
+		]
+	},
+	"SwiftPackageReferences": null
 }`
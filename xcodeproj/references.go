@@ -0,0 +1,42 @@
+package xcodeproj
+
+import "github.com/bitrise-io/xcode-project/serialized"
+
+// reachableObjectIDs returns the IDs of the objects reachable from the object with id,
+// following every string value (in dictionaries and arrays) which is an object ID.
+// Dictionary keys are not followed (like the target IDs of the TargetAttributes).
+func reachableObjectIDs(objects serialized.Object, id string) map[string]bool {
+	reachable := map[string]bool{}
+
+	var visit func(value interface{})
+	visit = func(value interface{}) {
+		switch value := value.(type) {
+		case string:
+			if reachable[value] {
+				return
+			}
+			object, err := objects.Object(value)
+			if err != nil {
+				return
+			}
+			reachable[value] = true
+			visit(map[string]interface{}(object))
+		case serialized.Object:
+			visit(map[string]interface{}(value))
+		case map[string]interface{}:
+			for key, v := range value {
+				if key == "isa" || key == customAnnotationKey {
+					continue
+				}
+				visit(v)
+			}
+		case []interface{}:
+			for _, v := range value {
+				visit(v)
+			}
+		}
+	}
+
+	visit(id)
+	return reachable
+}
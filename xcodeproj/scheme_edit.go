@@ -0,0 +1,84 @@
+package xcodeproj
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/bitrise-io/xcode-project/xcscheme"
+)
+
+// CreateSharedScheme creates a shared scheme named after the target, the same way as Xcode creates the scheme of a new target:
+// the scheme builds, launches, profiles and archives the target and tests the unit and UI test targets depending on it.
+// The scheme's LastUpgradeVersion is the project's LastUpgradeCheck attribute.
+func (p XcodeProj) CreateSharedScheme(targetName string) (xcscheme.Scheme, error) {
+	target, ok := p.Proj.TargetByName(targetName)
+	if !ok {
+		return xcscheme.Scheme{}, fmt.Errorf("target not found: %s", targetName)
+	}
+
+	pth := filepath.Join(p.Path, "xcshareddata", "xcschemes", targetName+".xcscheme")
+	if exist, err := pathutil.IsPathExists(pth); err != nil {
+		return xcscheme.Scheme{}, err
+	} else if exist {
+		return xcscheme.Scheme{}, fmt.Errorf("scheme already exists: %s", pth)
+	}
+
+	template := xcscheme.SchemeTemplate{
+		Target:               p.buildableReference(target),
+		Runnable:             target.IsExecutableProduct() || filepath.Ext(target.ProductType) == ".tool",
+		DebugConfiguration:   p.schemeConfiguration("Debug"),
+		ReleaseConfiguration: p.schemeConfiguration("Release"),
+	}
+	if attributes, err := p.Attributes(); err == nil {
+		template.LastUpgradeVersion = optionalString(attributes, "LastUpgradeCheck")
+	}
+
+	for _, testTarget := range p.Proj.Targets {
+		if !testTarget.IsTestProduct() && !testTarget.IsUITestProduct() {
+			continue
+		}
+		for _, dependency := range testTarget.Dependencies {
+			if dependency.Target.ID == target.ID {
+				template.TestTargets = append(template.TestTargets, p.buildableReference(testTarget))
+				break
+			}
+		}
+	}
+
+	if err := os.MkdirAll(filepath.Dir(pth), 0755); err != nil {
+		return xcscheme.Scheme{}, fmt.Errorf("failed to create schemes directory: %s", err)
+	}
+	if err := xcscheme.NewSchemeDocument(template).Write(pth); err != nil {
+		return xcscheme.Scheme{}, fmt.Errorf("failed to write scheme (%s): %s", pth, err)
+	}
+
+	return xcscheme.Open(pth)
+}
+
+// buildableReference returns the BuildableReference of the target in the project's schemes.
+func (p XcodeProj) buildableReference(target Target) xcscheme.BuildableReference {
+	buildableName := filepath.Base(target.ProductReference.Path)
+	if target.ProductReference.Path == "" {
+		// aggregate targets have no product
+		buildableName = target.Name
+	}
+
+	return xcscheme.BuildableReference{
+		BuildableIdentifier: "primary",
+		BlueprintIdentifier: target.ID,
+		BuildableName:       buildableName,
+		BlueprintName:       target.Name,
+		ReferencedContainer: "container:" + filepath.Base(p.Path),
+	}
+}
+
+// schemeConfiguration returns the project's build configuration with the given name,
+// or the project's default build configuration if the project has no such configuration.
+func (p XcodeProj) schemeConfiguration(name string) string {
+	if _, ok := buildConfigurationByName(p.Proj.BuildConfigurationList, name); ok {
+		return name
+	}
+	return p.Proj.BuildConfigurationList.DefaultConfigurationName
+}
@@ -0,0 +1,44 @@
+package xcodeproj
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXcodeProj_CreateSharedScheme(t *testing.T) {
+	pth := createProjectInTmpDir(t, "XcodeProj", testhelper.XcodeProjectTest, nil)
+	project, err := Open(pth)
+	require.NoError(t, err)
+
+	scheme, err := project.CreateSharedScheme("XcodeProj")
+	require.NoError(t, err)
+	require.Equal(t, "XcodeProj", scheme.Name)
+	require.Equal(t, filepath.Join(pth, "xcshareddata", "xcschemes", "XcodeProj.xcscheme"), scheme.Path)
+	require.Equal(t, "0940", scheme.LastUpgradeVersion)
+	require.Equal(t, "1.7", scheme.Version)
+
+	entry, ok := scheme.AppBuildActionEntry()
+	require.True(t, ok)
+	require.Equal(t, "7D5B35FB20E28EE80022BAE6", entry.BuildableReference.BlueprintIdentifier)
+	require.Equal(t, "XcodeProj.app", entry.BuildableReference.BuildableName)
+	require.Equal(t, "container:XcodeProj.xcodeproj", entry.BuildableReference.ReferencedContainer)
+
+	require.Equal(t, "Debug", scheme.TestAction.BuildConfiguration)
+	require.Equal(t, 1, len(scheme.TestAction.Testables))
+	require.Equal(t, "XcodeProjUITests", scheme.TestAction.Testables[0].BuildableReference.BlueprintName)
+	require.Equal(t, "XcodeProj.app", scheme.LaunchAction.BuildableProductRunnable.BuildableReference.BuildableName)
+	require.Equal(t, "Release", scheme.ArchiveAction.BuildConfiguration)
+
+	schemes, err := project.Schemes()
+	require.NoError(t, err)
+	require.Equal(t, 1, len(schemes))
+
+	_, err = project.CreateSharedScheme("XcodeProj")
+	require.EqualError(t, err, "scheme already exists: "+scheme.Path)
+
+	_, err = project.CreateSharedScheme("NotExisting")
+	require.EqualError(t, err, "target not found: NotExisting")
+}
@@ -0,0 +1,177 @@
+package xcodeproj
+
+import (
+	"fmt"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+)
+
+// SwiftPackageReferenceType ...
+type SwiftPackageReferenceType string
+
+// SwiftPackageReferenceTypes
+const (
+	RemoteSwiftPackageReferenceType SwiftPackageReferenceType = "XCRemoteSwiftPackageReference"
+	LocalSwiftPackageReferenceType  SwiftPackageReferenceType = "XCLocalSwiftPackageReference"
+)
+
+// VersionRequirementKind ...
+type VersionRequirementKind string
+
+// VersionRequirementKinds
+const (
+	ExactVersionRequirementKind         VersionRequirementKind = "exactVersion"
+	VersionRangeRequirementKind         VersionRequirementKind = "versionRange"
+	UpToNextMajorVersionRequirementKind VersionRequirementKind = "upToNextMajorVersion"
+	UpToNextMinorVersionRequirementKind VersionRequirementKind = "upToNextMinorVersion"
+	BranchRequirementKind               VersionRequirementKind = "branch"
+	RevisionRequirementKind             VersionRequirementKind = "revision"
+)
+
+// VersionRequirement is the dependency rule of a remote Swift package
+// requirement = {kind = upToNextMajorVersion; minimumVersion = 5.4.0; };
+type VersionRequirement struct {
+	Kind VersionRequirementKind
+	// Version is set for the exactVersion kind.
+	Version string
+	// MinimumVersion is set for the versionRange, upToNextMajorVersion and upToNextMinorVersion kinds.
+	MinimumVersion string
+	// MaximumVersion is set for the versionRange kind (exclusive).
+	MaximumVersion string
+	Branch         string
+	Revision       string
+}
+
+// SwiftPackageReference represents an XCRemoteSwiftPackageReference or an XCLocalSwiftPackageReference element.
+type SwiftPackageReference struct {
+	Type SwiftPackageReferenceType
+	ID   string
+	// RepositoryURL is set for remote packages.
+	RepositoryURL string
+	// Requirement is set for remote packages.
+	Requirement VersionRequirement
+	// RelativePath is set for local packages.
+	RelativePath string
+}
+
+// SwiftPackageProductDependency represents an XCSwiftPackageProductDependency element, a package product linked by a target.
+type SwiftPackageProductDependency struct {
+	ID          string
+	ProductName string
+	// Package is the ID of the product's package reference, empty for local packages added to the project's file tree.
+	Package string
+}
+
+// PackageProduct is a Swift package product and the names of the targets depending on it.
+type PackageProduct struct {
+	Name    string
+	Targets []string
+}
+
+// PackageReference is a Swift package referenced by the project and its products used by the project's targets.
+type PackageReference struct {
+	Reference SwiftPackageReference
+	Products  []PackageProduct
+}
+
+// PackageReferences returns the Swift packages referenced by the project,
+// with the products linked by the project's targets.
+func (p Proj) PackageReferences() []PackageReference {
+	var packageReferences []PackageReference
+	for _, reference := range p.SwiftPackageReferences {
+		packageReference := PackageReference{Reference: reference}
+
+		productIdxByName := map[string]int{}
+		for _, target := range p.Targets {
+			for _, dependency := range target.PackageProductDependencies {
+				if dependency.Package != reference.ID {
+					continue
+				}
+
+				idx, ok := productIdxByName[dependency.ProductName]
+				if !ok {
+					idx = len(packageReference.Products)
+					productIdxByName[dependency.ProductName] = idx
+					packageReference.Products = append(packageReference.Products, PackageProduct{Name: dependency.ProductName})
+				}
+				packageReference.Products[idx].Targets = append(packageReference.Products[idx].Targets, target.Name)
+			}
+		}
+
+		packageReferences = append(packageReferences, packageReference)
+	}
+	return packageReferences
+}
+
+func parseSwiftPackageReference(id string, objects serialized.Object) (SwiftPackageReference, error) {
+	raw, err := objects.Object(id)
+	if err != nil {
+		return SwiftPackageReference{}, err
+	}
+
+	isa, err := raw.String("isa")
+	if err != nil {
+		return SwiftPackageReference{}, err
+	}
+
+	switch SwiftPackageReferenceType(isa) {
+	case RemoteSwiftPackageReferenceType:
+		repositoryURL, err := raw.String("repositoryURL")
+		if err != nil {
+			return SwiftPackageReference{}, err
+		}
+
+		var requirement VersionRequirement
+		rawRequirement, err := raw.Object("requirement")
+		if err != nil && !serialized.IsKeyNotFoundError(err) {
+			return SwiftPackageReference{}, err
+		} else if err == nil {
+			requirement = VersionRequirement{
+				Kind:           VersionRequirementKind(optionalString(rawRequirement, "kind")),
+				Version:        optionalString(rawRequirement, "version"),
+				MinimumVersion: optionalString(rawRequirement, "minimumVersion"),
+				MaximumVersion: optionalString(rawRequirement, "maximumVersion"),
+				Branch:         optionalString(rawRequirement, "branch"),
+				Revision:       optionalString(rawRequirement, "revision"),
+			}
+		}
+
+		return SwiftPackageReference{
+			Type:          RemoteSwiftPackageReferenceType,
+			ID:            id,
+			RepositoryURL: repositoryURL,
+			Requirement:   requirement,
+		}, nil
+	case LocalSwiftPackageReferenceType:
+		relativePath, err := raw.String("relativePath")
+		if err != nil {
+			return SwiftPackageReference{}, err
+		}
+
+		return SwiftPackageReference{
+			Type:         LocalSwiftPackageReferenceType,
+			ID:           id,
+			RelativePath: relativePath,
+		}, nil
+	default:
+		return SwiftPackageReference{}, fmt.Errorf("unknown package reference type: %s", isa)
+	}
+}
+
+func parseSwiftPackageProductDependency(id string, objects serialized.Object) (SwiftPackageProductDependency, error) {
+	raw, err := objects.Object(id)
+	if err != nil {
+		return SwiftPackageProductDependency{}, err
+	}
+
+	productName, err := raw.String("productName")
+	if err != nil {
+		return SwiftPackageProductDependency{}, err
+	}
+
+	return SwiftPackageProductDependency{
+		ID:          id,
+		ProductName: productName,
+		Package:     optionalString(raw, "package"),
+	}, nil
+}
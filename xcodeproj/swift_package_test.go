@@ -0,0 +1,338 @@
+package xcodeproj
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/go-plist"
+	"github.com/bitrise-io/xcode-project/serialized"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSwiftPackageReference(t *testing.T) {
+	var raw serialized.Object
+	_, err := plist.Unmarshal([]byte(rawSwiftPackages), &raw)
+	require.NoError(t, err)
+
+	objects, err := raw.Object("objects")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		id      string
+		want    SwiftPackageReference
+		wantErr bool
+	}{
+		{
+			name: "up to next major version",
+			id:   "2C1A2F6A25F8D3A900D1A2B1",
+			want: SwiftPackageReference{
+				Type:          RemoteSwiftPackageReferenceType,
+				ID:            "2C1A2F6A25F8D3A900D1A2B1",
+				RepositoryURL: "https://github.com/Alamofire/Alamofire.git",
+				Requirement:   VersionRequirement{Kind: UpToNextMajorVersionRequirementKind, MinimumVersion: "5.4.0"},
+			},
+		},
+		{
+			name: "exact version",
+			id:   "2C1A2F6D25F8D3C200D1A2B1",
+			want: SwiftPackageReference{
+				Type:          RemoteSwiftPackageReferenceType,
+				ID:            "2C1A2F6D25F8D3C200D1A2B1",
+				RepositoryURL: "https://github.com/onevcat/Kingfisher",
+				Requirement:   VersionRequirement{Kind: ExactVersionRequirementKind, Version: "7.1.2"},
+			},
+		},
+		{
+			name: "version range",
+			id:   "2C1A2F7025F8D3D500D1A2B1",
+			want: SwiftPackageReference{
+				Type:          RemoteSwiftPackageReferenceType,
+				ID:            "2C1A2F7025F8D3D500D1A2B1",
+				RepositoryURL: "https://github.com/apple/swift-log.git",
+				Requirement:   VersionRequirement{Kind: VersionRangeRequirementKind, MinimumVersion: "1.0.0", MaximumVersion: "1.5.0"},
+			},
+		},
+		{
+			name: "branch",
+			id:   "2C1A2F7325F8D3E800D1A2B1",
+			want: SwiftPackageReference{
+				Type:          RemoteSwiftPackageReferenceType,
+				ID:            "2C1A2F7325F8D3E800D1A2B1",
+				RepositoryURL: "https://github.com/pointfreeco/swift-snapshot-testing",
+				Requirement:   VersionRequirement{Kind: BranchRequirementKind, Branch: "main"},
+			},
+		},
+		{
+			name: "revision",
+			id:   "2C1A2F7625F8D3FB00D1A2B1",
+			want: SwiftPackageReference{
+				Type:          RemoteSwiftPackageReferenceType,
+				ID:            "2C1A2F7625F8D3FB00D1A2B1",
+				RepositoryURL: "https://github.com/realm/SwiftLint",
+				Requirement:   VersionRequirement{Kind: RevisionRequirementKind, Revision: "180d94132758dd183124ab1e63d6aa8e10023ec2"},
+			},
+		},
+		{
+			name: "local package",
+			id:   "2C1A2F7925F8D40E00D1A2B1",
+			want: SwiftPackageReference{
+				Type:         LocalSwiftPackageReferenceType,
+				ID:           "2C1A2F7925F8D40E00D1A2B1",
+				RelativePath: "Packages/Core",
+			},
+		},
+		{
+			name:    "not a package reference",
+			id:      "2C1A2F6B25F8D3A900D1A2B1",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSwiftPackageReference(tt.id, objects)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestProj_PackageReferences(t *testing.T) {
+	var raw serialized.Object
+	_, err := plist.Unmarshal([]byte(rawSwiftPackages), &raw)
+	require.NoError(t, err)
+
+	objects, err := raw.Object("objects")
+	require.NoError(t, err)
+
+	proj, err := parseProj("2C1A2F5025F8D38000D1A2B1", objects)
+	require.NoError(t, err)
+
+	app, ok := proj.TargetByName("App")
+	require.True(t, ok)
+	require.Equal(t, []SwiftPackageProductDependency{
+		{ID: "2C1A2F6B25F8D3A900D1A2B1", ProductName: "Alamofire", Package: "2C1A2F6A25F8D3A900D1A2B1"},
+		{ID: "2C1A2F6E25F8D3C200D1A2B1", ProductName: "Kingfisher", Package: "2C1A2F6D25F8D3C200D1A2B1"},
+		{ID: "2C1A2F7A25F8D40E00D1A2B1", ProductName: "Core"},
+	}, app.PackageProductDependencies)
+
+	packageReferences := proj.PackageReferences()
+	require.Equal(t, 6, len(packageReferences))
+
+	require.Equal(t, "https://github.com/Alamofire/Alamofire.git", packageReferences[0].Reference.RepositoryURL)
+	require.Equal(t, []PackageProduct{
+		{Name: "Alamofire", Targets: []string{"App", "AppTests"}},
+	}, packageReferences[0].Products)
+
+	require.Equal(t, []PackageProduct{
+		{Name: "Kingfisher", Targets: []string{"App"}},
+	}, packageReferences[1].Products)
+
+	require.Equal(t, []PackageProduct{
+		{Name: "SnapshotTesting", Targets: []string{"AppTests"}},
+	}, packageReferences[3].Products)
+
+	t.Log("packages without linked products")
+	{
+		require.Equal(t, "https://github.com/apple/swift-log.git", packageReferences[2].Reference.RepositoryURL)
+		require.Nil(t, packageReferences[2].Products)
+		require.Nil(t, packageReferences[4].Products)
+		require.Equal(t, LocalSwiftPackageReferenceType, packageReferences[5].Reference.Type)
+		require.Nil(t, packageReferences[5].Products)
+	}
+}
+
+const rawSwiftPackages = `// !$*UTF8*$!
+{
+	archiveVersion = 1;
+	classes = {
+	};
+	objectVersion = 52;
+	objects = {
+		2C1A2F5025F8D38000D1A2B1 /* Project object */ = {
+			isa = PBXProject;
+			attributes = {
+				LastUpgradeCheck = 1240;
+			};
+			buildConfigurationList = 2C1A2F5125F8D38000D1A2B1 /* Build configuration list for PBXProject "App" */;
+			compatibilityVersion = "Xcode 9.3";
+			developmentRegion = en;
+			hasScannedForEncodings = 0;
+			mainGroup = 2C1A2F5225F8D38000D1A2B1;
+			packageReferences = (
+				2C1A2F6A25F8D3A900D1A2B1 /* XCRemoteSwiftPackageReference "Alamofire" */,
+				2C1A2F6D25F8D3C200D1A2B1 /* XCRemoteSwiftPackageReference "Kingfisher" */,
+				2C1A2F7025F8D3D500D1A2B1 /* XCRemoteSwiftPackageReference "swift-log" */,
+				2C1A2F7325F8D3E800D1A2B1 /* XCRemoteSwiftPackageReference "swift-snapshot-testing" */,
+				2C1A2F7625F8D3FB00D1A2B1 /* XCRemoteSwiftPackageReference "SwiftLint" */,
+				2C1A2F7925F8D40E00D1A2B1 /* XCLocalSwiftPackageReference "Packages/Core" */,
+			);
+			projectDirPath = "";
+			projectRoot = "";
+			targets = (
+				2C1A2F5325F8D38000D1A2B1 /* App */,
+				2C1A2F5425F8D38000D1A2B1 /* AppTests */,
+			);
+		};
+		2C1A2F5125F8D38000D1A2B1 /* Build configuration list for PBXProject "App" */ = {
+			isa = XCConfigurationList;
+			buildConfigurations = (
+				2C1A2F5525F8D38000D1A2B1 /* Debug */,
+			);
+			defaultConfigurationIsVisible = 0;
+			defaultConfigurationName = Debug;
+		};
+		2C1A2F5525F8D38000D1A2B1 /* Debug */ = {
+			isa = XCBuildConfiguration;
+			buildSettings = {
+				SDKROOT = iphoneos;
+			};
+			name = Debug;
+		};
+		2C1A2F5225F8D38000D1A2B1 = {
+			isa = PBXGroup;
+			children = (
+			);
+			sourceTree = "<group>";
+		};
+		2C1A2F5325F8D38000D1A2B1 /* App */ = {
+			isa = PBXNativeTarget;
+			buildConfigurationList = 2C1A2F5625F8D38000D1A2B1 /* Build configuration list for PBXNativeTarget "App" */;
+			buildPhases = (
+			);
+			buildRules = (
+			);
+			dependencies = (
+			);
+			name = App;
+			packageProductDependencies = (
+				2C1A2F6B25F8D3A900D1A2B1 /* Alamofire */,
+				2C1A2F6E25F8D3C200D1A2B1 /* Kingfisher */,
+				2C1A2F7A25F8D40E00D1A2B1 /* Core */,
+			);
+			productName = App;
+			productType = "com.apple.product-type.application";
+		};
+		2C1A2F5625F8D38000D1A2B1 /* Build configuration list for PBXNativeTarget "App" */ = {
+			isa = XCConfigurationList;
+			buildConfigurations = (
+				2C1A2F5725F8D38000D1A2B1 /* Debug */,
+			);
+			defaultConfigurationIsVisible = 0;
+			defaultConfigurationName = Debug;
+		};
+		2C1A2F5725F8D38000D1A2B1 /* Debug */ = {
+			isa = XCBuildConfiguration;
+			buildSettings = {
+				PRODUCT_BUNDLE_IDENTIFIER = io.bitrise.App;
+			};
+			name = Debug;
+		};
+		2C1A2F5425F8D38000D1A2B1 /* AppTests */ = {
+			isa = PBXNativeTarget;
+			buildConfigurationList = 2C1A2F5825F8D38000D1A2B1 /* Build configuration list for PBXNativeTarget "AppTests" */;
+			buildPhases = (
+			);
+			buildRules = (
+			);
+			dependencies = (
+			);
+			name = AppTests;
+			packageProductDependencies = (
+				2C1A2F6C25F8D3A900D1A2B1 /* Alamofire */,
+				2C1A2F7425F8D3E800D1A2B1 /* SnapshotTesting */,
+			);
+			productName = AppTests;
+			productType = "com.apple.product-type.bundle.unit-test";
+		};
+		2C1A2F5825F8D38000D1A2B1 /* Build configuration list for PBXNativeTarget "AppTests" */ = {
+			isa = XCConfigurationList;
+			buildConfigurations = (
+				2C1A2F5925F8D38000D1A2B1 /* Debug */,
+			);
+			defaultConfigurationIsVisible = 0;
+			defaultConfigurationName = Debug;
+		};
+		2C1A2F5925F8D38000D1A2B1 /* Debug */ = {
+			isa = XCBuildConfiguration;
+			buildSettings = {
+				PRODUCT_BUNDLE_IDENTIFIER = io.bitrise.AppTests;
+			};
+			name = Debug;
+		};
+		2C1A2F6A25F8D3A900D1A2B1 /* XCRemoteSwiftPackageReference "Alamofire" */ = {
+			isa = XCRemoteSwiftPackageReference;
+			repositoryURL = "https://github.com/Alamofire/Alamofire.git";
+			requirement = {
+				kind = upToNextMajorVersion;
+				minimumVersion = 5.4.0;
+			};
+		};
+		2C1A2F6D25F8D3C200D1A2B1 /* XCRemoteSwiftPackageReference "Kingfisher" */ = {
+			isa = XCRemoteSwiftPackageReference;
+			repositoryURL = "https://github.com/onevcat/Kingfisher";
+			requirement = {
+				kind = exactVersion;
+				version = 7.1.2;
+			};
+		};
+		2C1A2F7025F8D3D500D1A2B1 /* XCRemoteSwiftPackageReference "swift-log" */ = {
+			isa = XCRemoteSwiftPackageReference;
+			repositoryURL = "https://github.com/apple/swift-log.git";
+			requirement = {
+				kind = versionRange;
+				maximumVersion = 1.5.0;
+				minimumVersion = 1.0.0;
+			};
+		};
+		2C1A2F7325F8D3E800D1A2B1 /* XCRemoteSwiftPackageReference "swift-snapshot-testing" */ = {
+			isa = XCRemoteSwiftPackageReference;
+			repositoryURL = "https://github.com/pointfreeco/swift-snapshot-testing";
+			requirement = {
+				branch = main;
+				kind = branch;
+			};
+		};
+		2C1A2F7625F8D3FB00D1A2B1 /* XCRemoteSwiftPackageReference "SwiftLint" */ = {
+			isa = XCRemoteSwiftPackageReference;
+			repositoryURL = "https://github.com/realm/SwiftLint";
+			requirement = {
+				kind = revision;
+				revision = 180d94132758dd183124ab1e63d6aa8e10023ec2;
+			};
+		};
+		2C1A2F7925F8D40E00D1A2B1 /* XCLocalSwiftPackageReference "Packages/Core" */ = {
+			isa = XCLocalSwiftPackageReference;
+			relativePath = Packages/Core;
+		};
+		2C1A2F6B25F8D3A900D1A2B1 /* Alamofire */ = {
+			isa = XCSwiftPackageProductDependency;
+			package = 2C1A2F6A25F8D3A900D1A2B1 /* XCRemoteSwiftPackageReference "Alamofire" */;
+			productName = Alamofire;
+		};
+		2C1A2F6C25F8D3A900D1A2B1 /* Alamofire */ = {
+			isa = XCSwiftPackageProductDependency;
+			package = 2C1A2F6A25F8D3A900D1A2B1 /* XCRemoteSwiftPackageReference "Alamofire" */;
+			productName = Alamofire;
+		};
+		2C1A2F6E25F8D3C200D1A2B1 /* Kingfisher */ = {
+			isa = XCSwiftPackageProductDependency;
+			package = 2C1A2F6D25F8D3C200D1A2B1 /* XCRemoteSwiftPackageReference "Kingfisher" */;
+			productName = Kingfisher;
+		};
+		2C1A2F7425F8D3E800D1A2B1 /* SnapshotTesting */ = {
+			isa = XCSwiftPackageProductDependency;
+			package = 2C1A2F7325F8D3E800D1A2B1 /* XCRemoteSwiftPackageReference "swift-snapshot-testing" */;
+			productName = SnapshotTesting;
+		};
+		2C1A2F7A25F8D40E00D1A2B1 /* Core */ = {
+			isa = XCSwiftPackageProductDependency;
+			productName = Core;
+		};
+	};
+	rootObject = 2C1A2F5025F8D38000D1A2B1 /* Project object */;
+}
+`
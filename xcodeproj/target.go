@@ -26,41 +26,53 @@ type Target struct {
 	Dependencies           []TargetDependency
 	ProductReference       ProductReference
 	ProductType            string
-	buildPhaseIDs          []string
+	BuildPhases            []BuildPhase
+	// PackageProductDependencies are the Swift package products linked by the target.
+	PackageProductDependencies []SwiftPackageProductDependency
 }
 
-// DependentTargets ...
+// DependentTargets returns the targets the target depends on, directly or indirectly.
+// A target reached through more dependencies is returned once.
 func (t Target) DependentTargets() []Target {
-	var targets []Target
-	for _, targetDependency := range t.Dependencies {
-		childTarget := targetDependency.Target
-		targets = append(targets, childTarget)
-
-		childDependentTargets := childTarget.DependentTargets()
-		targets = append(targets, childDependentTargets...)
-	}
-
-	return targets
+	return t.dependentTargets(map[string]bool{t.ID: true}, func(Target) bool { return true })
 }
 
-// DependentExecutableProductTargets ...
+// DependentExecutableProductTargets returns the app and app extension targets (and the UI test targets if includeUITest is true)
+// the target depends on, directly or through other executable product targets.
 func (t Target) DependentExecutableProductTargets(includeUITest bool) []Target {
+	return t.dependentTargets(map[string]bool{t.ID: true}, func(target Target) bool {
+		return target.IsExecutableProduct() || (includeUITest && target.IsUITestProduct())
+	})
+}
+
+// dependentTargets walks the dependencies depth first, following the targets matching the filter.
+func (t Target) dependentTargets(visited map[string]bool, filter func(Target) bool) []Target {
 	var targets []Target
 	for _, targetDependency := range t.Dependencies {
 		childTarget := targetDependency.Target
-		if !childTarget.IsExecutableProduct() && (!includeUITest || !childTarget.IsUITestProduct()) {
+		if visited[childTarget.ID] || !filter(childTarget) {
 			continue
 		}
+		visited[childTarget.ID] = true
 
 		targets = append(targets, childTarget)
-
-		childDependentTargets := childTarget.DependentExecutableProductTargets(includeUITest)
-		targets = append(targets, childDependentTargets...)
+		targets = append(targets, childTarget.dependentTargets(visited, filter)...)
 	}
 
 	return targets
 }
 
+// BuildPhasesOfType returns the target's build phases with the given type, in build order.
+func (t Target) BuildPhasesOfType(buildPhaseType BuildPhaseType) []BuildPhase {
+	var buildPhases []BuildPhase
+	for _, buildPhase := range t.BuildPhases {
+		if buildPhase.Type == buildPhaseType {
+			buildPhases = append(buildPhases, buildPhase)
+		}
+	}
+	return buildPhases
+}
+
 // IsAppProduct ...
 func (t Target) IsAppProduct() bool {
 	return filepath.Ext(t.ProductReference.Path) == ".app"
@@ -87,7 +99,51 @@ func (t Target) IsUITestProduct() bool {
 }
 
 func parseTarget(id string, objects serialized.Object) (Target, error) {
-	rawTarget, err := objects.Object(id)
+	return newTargetParser(objects).parse(id)
+}
+
+// targetParser parses the targets with their dependencies,
+// a target depended on by more targets (like in a diamond dependency) is parsed once.
+type targetParser struct {
+	objects serialized.Object
+	parsed  map[string]Target
+	// dependents are the IDs of the targets being parsed, which depend on the currently parsed target.
+	dependents map[string]bool
+	// skippedDependencies is the number of the dependencies skipped so far, as they closed a dependency cycle.
+	skippedDependencies *int
+}
+
+func newTargetParser(objects serialized.Object) targetParser {
+	return targetParser{
+		objects:             objects,
+		parsed:              map[string]Target{},
+		dependents:          map[string]bool{},
+		skippedDependencies: new(int),
+	}
+}
+
+// parse parses the target, a dependency on one of the dependents (closing a dependency cycle) is skipped.
+// The target's own dependencies are always complete, the cycle is cut at the dependency leading back to a dependent.
+// So a target is parsed once only if no dependency of it (or of its dependencies) was skipped,
+// otherwise its dependencies would depend on the target the parsing started from.
+func (p targetParser) parse(id string) (Target, error) {
+	if target, ok := p.parsed[id]; ok {
+		return target, nil
+	}
+
+	skipped := *p.skippedDependencies
+	target, err := p.parseTarget(id)
+	if err != nil {
+		return Target{}, err
+	}
+	if *p.skippedDependencies == skipped {
+		p.parsed[id] = target
+	}
+	return target, nil
+}
+
+func (p targetParser) parseTarget(id string) (Target, error) {
+	rawTarget, err := p.objects.Object(id)
 	if err != nil {
 		return Target{}, err
 	}
@@ -124,7 +180,7 @@ func parseTarget(id string, objects serialized.Object) (Target, error) {
 		return Target{}, err
 	}
 
-	buildConfigurationList, err := parseConfigurationList(buildConfigurationListID, objects)
+	buildConfigurationList, err := parseConfigurationList(buildConfigurationListID, p.objects)
 	if err != nil {
 		return Target{}, err
 	}
@@ -134,9 +190,12 @@ func parseTarget(id string, objects serialized.Object) (Target, error) {
 		return Target{}, err
 	}
 
+	p.dependents[id] = true
+	defer delete(p.dependents, id)
+
 	var dependencies []TargetDependency
 	for _, dependencyID := range dependencyIDs {
-		dependency, err := parseTargetDependency(dependencyID, objects)
+		dependency, ok, err := p.parseDependency(dependencyID)
 		if err != nil {
 			// KeyNotFoundError can be only raised if the 'target' property not found on the raw target dependency object
 			// we only care about target dependency, which points to a target
@@ -146,6 +205,9 @@ func parseTarget(id string, objects serialized.Object) (Target, error) {
 				return Target{}, err
 			}
 		}
+		if !ok {
+			continue
+		}
 
 		dependencies = append(dependencies, dependency)
 	}
@@ -157,7 +219,7 @@ func parseTarget(id string, objects serialized.Object) (Target, error) {
 			return Target{}, err
 		}
 	} else {
-		productReference, err = parseProductReference(productReferenceID, objects)
+		productReference, err = parseProductReference(productReferenceID, p.objects)
 		if err != nil {
 			return Target{}, err
 		}
@@ -168,14 +230,43 @@ func parseTarget(id string, objects serialized.Object) (Target, error) {
 		return Target{}, err
 	}
 
+	var buildPhases []BuildPhase
+	for _, buildPhaseID := range buildPhaseIDs {
+		// buildPhases can contain IDs without build phase object
+		if _, err := p.objects.Object(buildPhaseID); err != nil && serialized.IsKeyNotFoundError(err) {
+			continue
+		}
+
+		buildPhase, err := parseBuildPhase(buildPhaseID, p.objects)
+		if err != nil {
+			return Target{}, fmt.Errorf("failed to parse build phase (%s): %s", buildPhaseID, err)
+		}
+		buildPhases = append(buildPhases, buildPhase)
+	}
+
+	packageProductDependencyIDs, err := rawTarget.StringSlice("packageProductDependencies")
+	if err != nil && !serialized.IsKeyNotFoundError(err) {
+		return Target{}, err
+	}
+
+	var packageProductDependencies []SwiftPackageProductDependency
+	for _, dependencyID := range packageProductDependencyIDs {
+		dependency, err := parseSwiftPackageProductDependency(dependencyID, p.objects)
+		if err != nil {
+			return Target{}, fmt.Errorf("failed to parse package product dependency (%s): %s", dependencyID, err)
+		}
+		packageProductDependencies = append(packageProductDependencies, dependency)
+	}
+
 	return Target{
-		Type:                   targetType,
-		ID:                     id,
-		Name:                   name,
-		BuildConfigurationList: buildConfigurationList,
-		Dependencies:           dependencies,
-		ProductReference:       productReference,
-		ProductType:            productType,
-		buildPhaseIDs:          buildPhaseIDs,
+		Type:                       targetType,
+		ID:                         id,
+		Name:                       name,
+		BuildConfigurationList:     buildConfigurationList,
+		Dependencies:               dependencies,
+		ProductReference:           productReference,
+		ProductType:                productType,
+		BuildPhases:                buildPhases,
+		PackageProductDependencies: packageProductDependencies,
 	}, nil
 }
@@ -9,23 +9,35 @@ type TargetDependency struct {
 }
 
 func parseTargetDependency(id string, objects serialized.Object) (TargetDependency, error) {
-	rawTargetDependency, err := objects.Object(id)
+	dependency, _, err := newTargetParser(objects).parseDependency(id)
+	return dependency, err
+}
+
+// parseDependency parses the target dependency,
+// it returns false if the dependency's target is one of the dependents (the dependency closes a cycle).
+func (p targetParser) parseDependency(id string) (TargetDependency, bool, error) {
+	rawTargetDependency, err := p.objects.Object(id)
 	if err != nil {
-		return TargetDependency{}, err
+		return TargetDependency{}, false, err
 	}
 
 	targetID, err := rawTargetDependency.String("target")
 	if err != nil {
-		return TargetDependency{}, err
+		return TargetDependency{}, false, err
+	}
+
+	if p.dependents[targetID] {
+		*p.skippedDependencies++
+		return TargetDependency{}, false, nil
 	}
 
-	target, err := parseTarget(targetID, objects)
+	target, err := p.parse(targetID)
 	if err != nil {
-		return TargetDependency{}, err
+		return TargetDependency{}, false, err
 	}
 
 	return TargetDependency{
 		ID:     id,
 		Target: target,
-	}, nil
+	}, true, nil
 }
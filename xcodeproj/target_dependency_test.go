@@ -73,6 +73,8 @@ const expectedTargetDependency = `{
 		"ProductReference": {
 			"Path": "share-extension.appex"
 		},
-		"ProductType": "com.apple.product-type.app-extension"
+		"ProductType": "com.apple.product-type.app-extension",
+		"BuildPhases": null,
+		"PackageProductDependencies": null
 	}
 }`
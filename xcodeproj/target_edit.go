@@ -0,0 +1,753 @@
+package xcodeproj
+
+import (
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/xcode-project/serialized"
+	"github.com/bitrise-io/xcode-project/xcscheme"
+)
+
+// ProductType is the type of a native target's product.
+type ProductType string
+
+// ProductTypes
+// Widget, notification service and the other app extensions are all AppExtensionProductType targets,
+// the kind of the extension is defined by the NSExtensionPointIdentifier key of its Info.plist.
+const (
+	ApplicationProductType        ProductType = "com.apple.product-type.application"
+	AppExtensionProductType       ProductType = "com.apple.product-type.app-extension"
+	FrameworkProductType          ProductType = "com.apple.product-type.framework"
+	UnitTestBundleProductType     ProductType = "com.apple.product-type.bundle.unit-test"
+	UITestBundleProductType       ProductType = "com.apple.product-type.bundle.ui-testing"
+	WatchApp2ProductType          ProductType = "com.apple.product-type.application.watchapp2"
+	WatchKit2ExtensionProductType ProductType = "com.apple.product-type.watchkit2-extension"
+)
+
+// explicitFileTypeByProductType maps the product types to the file type of their product reference.
+var explicitFileTypeByProductType = map[ProductType]string{
+	ApplicationProductType:        "wrapper.application",
+	AppExtensionProductType:       "wrapper.app-extension",
+	FrameworkProductType:          "wrapper.framework",
+	UnitTestBundleProductType:     "wrapper.cfbundle",
+	UITestBundleProductType:       "wrapper.cfbundle",
+	WatchApp2ProductType:          "wrapper.application",
+	WatchKit2ExtensionProductType: "wrapper.app-extension",
+}
+
+// defaultTargetBuildSettings returns the build settings of a new target, based on Xcode's target templates.
+func defaultTargetBuildSettings(productType ProductType) map[string]interface{} {
+	buildSettings := map[string]interface{}{
+		"CODE_SIGN_STYLE":         "Automatic",
+		"CURRENT_PROJECT_VERSION": "1",
+		"GENERATE_INFOPLIST_FILE": "YES",
+		"MARKETING_VERSION":       "1.0",
+		"PRODUCT_NAME":            "$(TARGET_NAME)",
+		"SWIFT_VERSION":           "5.0",
+	}
+
+	switch productType {
+	case ApplicationProductType:
+		buildSettings["ASSETCATALOG_COMPILER_APPICON_NAME"] = "AppIcon"
+		buildSettings["LD_RUNPATH_SEARCH_PATHS"] = []interface{}{"$(inherited)", "@executable_path/Frameworks"}
+	case AppExtensionProductType:
+		buildSettings["SKIP_INSTALL"] = "YES"
+		buildSettings["LD_RUNPATH_SEARCH_PATHS"] = []interface{}{"$(inherited)", "@executable_path/Frameworks", "@executable_path/../../Frameworks"}
+	case FrameworkProductType:
+		buildSettings["DEFINES_MODULE"] = "YES"
+		buildSettings["DYLIB_COMPATIBILITY_VERSION"] = "1"
+		buildSettings["DYLIB_CURRENT_VERSION"] = "1"
+		buildSettings["DYLIB_INSTALL_NAME_BASE"] = "@rpath"
+		buildSettings["INSTALL_PATH"] = "$(LOCAL_LIBRARY_DIR)/Frameworks"
+		buildSettings["SKIP_INSTALL"] = "YES"
+		buildSettings["VERSIONING_SYSTEM"] = "apple-generic"
+		buildSettings["LD_RUNPATH_SEARCH_PATHS"] = []interface{}{"$(inherited)", "@executable_path/Frameworks", "@loader_path/Frameworks"}
+	case UnitTestBundleProductType, UITestBundleProductType:
+		buildSettings["LD_RUNPATH_SEARCH_PATHS"] = []interface{}{"$(inherited)", "@executable_path/Frameworks", "@loader_path/Frameworks"}
+	case WatchApp2ProductType:
+		buildSettings["ASSETCATALOG_COMPILER_APPICON_NAME"] = "AppIcon"
+		buildSettings["SDKROOT"] = "watchos"
+		buildSettings["SKIP_INSTALL"] = "YES"
+		buildSettings["TARGETED_DEVICE_FAMILY"] = "4"
+	case WatchKit2ExtensionProductType:
+		buildSettings["SDKROOT"] = "watchos"
+		buildSettings["SKIP_INSTALL"] = "YES"
+		buildSettings["TARGETED_DEVICE_FAMILY"] = "4"
+		buildSettings["LD_RUNPATH_SEARCH_PATHS"] = []interface{}{"$(inherited)", "@executable_path/Frameworks", "@executable_path/../../Frameworks"}
+	}
+
+	return buildSettings
+}
+
+// AddTarget adds a native target to the project with:
+// - a product reference in the project's Products group
+// - a build configuration for each of the project's build configurations
+// - empty Sources, Frameworks and Resources build phases (and a Headers build phase for frameworks)
+// - an entry in the project's TargetAttributes
+//
+// Test targets created by AddTarget have no host application, use AddTestTarget to test an app.
+//
+// The project is not saved, call Save to write the changes.
+func (p *XcodeProj) AddTarget(name string, productType ProductType) (Target, error) {
+	return p.addTarget(name, productType, nil)
+}
+
+// AddTestTarget adds a unit or UI test target to the project (see AddTarget), which tests the given host target:
+// - unit tests are injected into the host (TEST_HOST and BUNDLE_LOADER build settings)
+// - UI tests run the host (TEST_TARGET_NAME build setting)
+// - the test target depends on the host and its TargetAttributes entry has the host's TestTargetID
+//
+// The project is not saved, call Save to write the changes.
+func (p *XcodeProj) AddTestTarget(name string, productType ProductType, hostTargetName string) (Target, error) {
+	if productType != UnitTestBundleProductType && productType != UITestBundleProductType {
+		return Target{}, fmt.Errorf("not a test product type: %s", productType)
+	}
+
+	host, ok := p.Proj.TargetByName(hostTargetName)
+	if !ok {
+		return Target{}, fmt.Errorf("host target not found: %s", hostTargetName)
+	}
+
+	return p.addTarget(name, productType, &host)
+}
+
+func (p *XcodeProj) addTarget(name string, productType ProductType, host *Target) (Target, error) {
+	if _, ok := p.Proj.TargetByName(name); ok {
+		return Target{}, fmt.Errorf("target already exists: %s", name)
+	}
+
+	explicitFileType, ok := explicitFileTypeByProductType[productType]
+	if !ok {
+		return Target{}, fmt.Errorf("unsupported product type: %s", productType)
+	}
+
+	objects, rawPBXProj, err := p.rawObjects()
+	if err != nil {
+		return Target{}, err
+	}
+
+	productReferenceID, err := addObject(objects, serialized.Object{
+		"isa":              fileReferenceElementType,
+		"explicitFileType": explicitFileType,
+		"includeInIndex":   "0",
+		"path":             name + "." + wrapperExtensionByProductType[string(productType)],
+		"sourceTree":       string(BuiltProductsDirSourceTree),
+	})
+	if err != nil {
+		return Target{}, err
+	}
+
+	if productRefGroupID, err := rawPBXProj.String("productRefGroup"); err == nil {
+		productRefGroup, err := objects.Object(productRefGroupID)
+		if err != nil {
+			return Target{}, fmt.Errorf("failed to access products group (%s): %s", productRefGroupID, err)
+		}
+		if err := appendToArray(productRefGroup, "children", productReferenceID); err != nil {
+			return Target{}, fmt.Errorf("failed to add product reference to the products group: %s", err)
+		}
+	} else if !serialized.IsKeyNotFoundError(err) {
+		return Target{}, err
+	}
+
+	buildConfigurationListID, err := p.addTargetConfigurationList(objects, productType, host)
+	if err != nil {
+		return Target{}, err
+	}
+
+	buildPhaseTypes := []BuildPhaseType{SourcesBuildPhaseType, FrameworksBuildPhaseType, ResourcesBuildPhaseType}
+	if productType == FrameworkProductType {
+		buildPhaseTypes = append([]BuildPhaseType{HeadersBuildPhaseType}, buildPhaseTypes...)
+	}
+
+	var buildPhaseIDs []interface{}
+	for _, buildPhaseType := range buildPhaseTypes {
+		buildPhaseID, err := addObject(objects, serialized.Object{
+			"isa":                                string(buildPhaseType),
+			"buildActionMask":                    "2147483647",
+			"files":                              []interface{}{},
+			"runOnlyForDeploymentPostprocessing": "0",
+		})
+		if err != nil {
+			return Target{}, err
+		}
+		buildPhaseIDs = append(buildPhaseIDs, buildPhaseID)
+	}
+
+	dependencyIDs := []interface{}{}
+	if host != nil {
+		dependencyID, err := p.addTargetDependency(objects, *host)
+		if err != nil {
+			return Target{}, err
+		}
+		dependencyIDs = append(dependencyIDs, dependencyID)
+	}
+
+	targetID, err := addObject(objects, serialized.Object{
+		"isa":                    string(NativeTargetType),
+		"buildConfigurationList": buildConfigurationListID,
+		"buildPhases":            buildPhaseIDs,
+		"buildRules":             []interface{}{},
+		"dependencies":           dependencyIDs,
+		"name":                   name,
+		"productName":            name,
+		"productReference":       productReferenceID,
+		"productType":            string(productType),
+	})
+	if err != nil {
+		return Target{}, err
+	}
+
+	if err := appendToArray(rawPBXProj, "targets", targetID); err != nil {
+		return Target{}, fmt.Errorf("failed to add target to the project: %s", err)
+	}
+
+	testTargetID := ""
+	if host != nil {
+		testTargetID = host.ID
+	}
+	if err := addTargetAttributes(rawPBXProj, targetID, testTargetID); err != nil {
+		return Target{}, err
+	}
+
+	if err := p.reloadProj(); err != nil {
+		return Target{}, err
+	}
+
+	target, ok := p.Proj.Target(targetID)
+	if !ok {
+		return Target{}, fmt.Errorf("failed to find added target: %s", name)
+	}
+	return target, nil
+}
+
+// addTargetConfigurationList adds a configuration list for a new target,
+// with a build configuration for each of the project's build configurations.
+// The build settings of a test target refer to its host target, if it has one.
+func (p XcodeProj) addTargetConfigurationList(objects serialized.Object, productType ProductType, host *Target) (string, error) {
+	projectConfigurationList := p.Proj.BuildConfigurationList
+
+	var buildConfigurationIDs []interface{}
+	for _, projectConfiguration := range projectConfigurationList.BuildConfigurations {
+		buildSettings := defaultTargetBuildSettings(productType)
+		if projectConfiguration.Name == "Debug" {
+			buildSettings["SWIFT_ACTIVE_COMPILATION_CONDITIONS"] = "DEBUG"
+		}
+		if host != nil {
+			for key, value := range testHostBuildSettings(productType, *host) {
+				buildSettings[key] = value
+			}
+		}
+
+		buildConfigurationID, err := addObject(objects, serialized.Object{
+			"isa":           "XCBuildConfiguration",
+			"buildSettings": buildSettings,
+			"name":          projectConfiguration.Name,
+		})
+		if err != nil {
+			return "", err
+		}
+		buildConfigurationIDs = append(buildConfigurationIDs, buildConfigurationID)
+	}
+
+	defaultConfigurationName := projectConfigurationList.DefaultConfigurationName
+	if defaultConfigurationName == "" && len(projectConfigurationList.BuildConfigurations) > 0 {
+		defaultConfigurationName = projectConfigurationList.BuildConfigurations[len(projectConfigurationList.BuildConfigurations)-1].Name
+	}
+
+	return addObject(objects, serialized.Object{
+		"isa":                           "XCConfigurationList",
+		"buildConfigurations":           buildConfigurationIDs,
+		"defaultConfigurationIsVisible": "0",
+		"defaultConfigurationName":      defaultConfigurationName,
+	})
+}
+
+// testHostBuildSettings returns the build settings connecting a test target to its host target.
+func testHostBuildSettings(productType ProductType, host Target) map[string]interface{} {
+	if productType == UITestBundleProductType {
+		return map[string]interface{}{"TEST_TARGET_NAME": host.Name}
+	}
+
+	productName := host.Name
+	if host.ProductReference.Path != "" {
+		productName = strings.TrimSuffix(filepath.Base(host.ProductReference.Path), filepath.Ext(host.ProductReference.Path))
+	}
+	return map[string]interface{}{
+		"BUNDLE_LOADER": "$(TEST_HOST)",
+		"TEST_HOST":     fmt.Sprintf("$(BUILT_PRODUCTS_DIR)/%s.app/$(BUNDLE_EXECUTABLE_FOLDER_PATH)/%s", productName, productName),
+	}
+}
+
+// addTargetDependency adds a PBXTargetDependency (and its PBXContainerItemProxy) on a target of the project.
+func (p XcodeProj) addTargetDependency(objects serialized.Object, target Target) (string, error) {
+	proxyID, err := addObject(objects, serialized.Object{
+		"isa":                  "PBXContainerItemProxy",
+		"containerPortal":      p.Proj.ID,
+		"proxyType":            "1",
+		"remoteGlobalIDString": target.ID,
+		"remoteInfo":           target.Name,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return addObject(objects, serialized.Object{
+		"isa":         "PBXTargetDependency",
+		"target":      target.ID,
+		"targetProxy": proxyID,
+	})
+}
+
+// addTargetAttributes adds the TargetAttributes entry of a new target,
+// CreatedOnToolsVersion is derived from the project's LastUpgradeCheck attribute.
+// The TestTargetID is set if testTargetID (the host of a test target) is not empty.
+func addTargetAttributes(rawPBXProj serialized.Object, targetID, testTargetID string) error {
+	attributes, err := rawPBXProj.Object("attributes")
+	if err != nil {
+		if !serialized.IsKeyNotFoundError(err) {
+			return fmt.Errorf("failed to access project attributes: %s", err)
+		}
+		attributes = serialized.Object{}
+		rawPBXProj["attributes"] = map[string]interface{}(attributes)
+	}
+
+	targetAttributes, err := attributes.Object("TargetAttributes")
+	if err != nil {
+		if !serialized.IsKeyNotFoundError(err) {
+			return fmt.Errorf("failed to access target attributes: %s", err)
+		}
+		targetAttributes = serialized.Object{}
+		attributes["TargetAttributes"] = map[string]interface{}(targetAttributes)
+	}
+
+	targetAttribute := map[string]interface{}{}
+	if version := toolsVersion(optionalString(attributes, "LastUpgradeCheck")); version != "" {
+		targetAttribute["CreatedOnToolsVersion"] = version
+	}
+	if testTargetID != "" {
+		targetAttribute["TestTargetID"] = testTargetID
+	}
+	targetAttributes[targetID] = targetAttribute
+
+	return nil
+}
+
+// toolsVersion converts a LastUpgradeCheck attribute to an Xcode version: 1240 -> 12.4, 0940 -> 9.4, 1431 -> 14.3.1
+func toolsVersion(lastUpgradeCheck string) string {
+	if len(lastUpgradeCheck) != 4 {
+		return ""
+	}
+
+	major, err := strconv.Atoi(lastUpgradeCheck[:2])
+	if err != nil {
+		return ""
+	}
+	minor, err := strconv.Atoi(lastUpgradeCheck[2:3])
+	if err != nil {
+		return ""
+	}
+	patch, err := strconv.Atoi(lastUpgradeCheck[3:])
+	if err != nil {
+		return ""
+	}
+
+	components := []string{strconv.Itoa(major), strconv.Itoa(minor)}
+	if patch != 0 {
+		components = append(components, strconv.Itoa(patch))
+	}
+	return strings.Join(components, ".")
+}
+
+// RemoveTarget removes the target from the project together with the objects only the target owns:
+// its build configuration list, build phases, build files, target dependencies, product reference
+// (and the build files embedding the product in other targets) and TargetAttributes entry.
+// The target is also removed from the dependencies of the other targets.
+//
+// If updateSchemes is true, the target's BuildableReferences are removed from the project's shared schemes,
+// schemes without a remaining build action entry are deleted. The schemes are written immediately,
+// the project is not saved, call Save to write the project changes.
+func (p *XcodeProj) RemoveTarget(name string, updateSchemes bool) error {
+	target, ok := p.Proj.TargetByName(name)
+	if !ok {
+		return fmt.Errorf("target not found: %s", name)
+	}
+
+	objects, rawPBXProj, err := p.rawObjects()
+	if err != nil {
+		return err
+	}
+
+	rawTarget, err := objects.Object(target.ID)
+	if err != nil {
+		return fmt.Errorf("failed to access target (%s): %s", target.ID, err)
+	}
+
+	if productReferenceID := optionalString(rawTarget, "productReference"); productReferenceID != "" {
+		if err := removeBuildFiles(objects, map[string]bool{productReferenceID: true}); err != nil {
+			return err
+		}
+		if productReference, ok := p.Proj.Element(productReferenceID); ok {
+			parent := productReference.parentGroup()
+			rawParent, err := objects.Object(parent.ID)
+			if err != nil {
+				return fmt.Errorf("failed to access group (%s): %s", parent.ID, err)
+			}
+			if err := removeFromArray(rawParent, "children", productReferenceID); err != nil {
+				return fmt.Errorf("failed to remove product reference from group (%s): %s", parent.ID, err)
+			}
+		}
+		delete(objects, productReferenceID)
+	}
+
+	if err := removeTargetDependencies(objects, target.ID); err != nil {
+		return err
+	}
+
+	if err := removeFromArray(rawPBXProj, "targets", target.ID); err != nil {
+		return fmt.Errorf("failed to remove target from the project: %s", err)
+	}
+
+	if err := removeTargetAttributes(rawPBXProj, target.ID); err != nil {
+		return err
+	}
+
+	owned := reachableObjectIDs(objects, target.ID)
+	for id := range reachableObjectIDs(objects, p.Proj.ID) {
+		delete(owned, id)
+	}
+	for id := range owned {
+		delete(objects, id)
+	}
+
+	if err := p.reloadProj(); err != nil {
+		return err
+	}
+
+	if updateSchemes {
+		return p.removeTargetFromSharedSchemes(target.ID)
+	}
+	return nil
+}
+
+// removeTargetDependencies removes the target dependencies (and their container item proxies) pointing to the target.
+func removeTargetDependencies(objects serialized.Object, targetID string) error {
+	var dependencyIDs []string
+	for id := range objects {
+		object, err := objects.Object(id)
+		if err != nil || optionalString(object, "isa") != "PBXTargetDependency" {
+			continue
+		}
+
+		proxyID := optionalString(object, "targetProxy")
+		dependsOnTarget := optionalString(object, "target") == targetID
+		if proxy, err := objects.Object(proxyID); err == nil && optionalString(proxy, "remoteGlobalIDString") == targetID {
+			dependsOnTarget = true
+		}
+		if !dependsOnTarget {
+			continue
+		}
+
+		dependencyIDs = append(dependencyIDs, id)
+		if proxyID != "" {
+			delete(objects, proxyID)
+		}
+	}
+	if len(dependencyIDs) == 0 {
+		return nil
+	}
+
+	for id := range objects {
+		object, err := objects.Object(id)
+		if err != nil || !strings.HasSuffix(optionalString(object, "isa"), "Target") {
+			continue
+		}
+		if err := removeFromArray(object, "dependencies", dependencyIDs...); err != nil {
+			return fmt.Errorf("failed to remove dependencies of target (%s): %s", id, err)
+		}
+	}
+
+	for _, id := range dependencyIDs {
+		delete(objects, id)
+	}
+	return nil
+}
+
+// removeTargetAttributes removes the target's TargetAttributes entry and the TestTargetID attributes pointing to the target.
+func removeTargetAttributes(rawPBXProj serialized.Object, targetID string) error {
+	attributes, err := rawPBXProj.Object("attributes")
+	if err != nil {
+		if serialized.IsKeyNotFoundError(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to access project attributes: %s", err)
+	}
+
+	targetAttributes, err := attributes.Object("TargetAttributes")
+	if err != nil {
+		if serialized.IsKeyNotFoundError(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to access target attributes: %s", err)
+	}
+
+	delete(targetAttributes, targetID)
+	for id := range targetAttributes {
+		targetAttribute, err := targetAttributes.Object(id)
+		if err != nil {
+			continue
+		}
+		if optionalString(targetAttribute, "TestTargetID") == targetID {
+			delete(targetAttribute, "TestTargetID")
+		}
+	}
+	return nil
+}
+
+// removeTargetFromSharedSchemes removes the target's BuildableReferences from the project's shared schemes.
+func (p XcodeProj) removeTargetFromSharedSchemes(targetID string) error {
+	pths, err := filepath.Glob(filepath.Join(p.Path, "xcshareddata", "xcschemes", "*.xcscheme"))
+	if err != nil {
+		return err
+	}
+
+	for _, pth := range pths {
+		content, err := fileutil.ReadBytesFromFile(pth)
+		if err != nil {
+			return err
+		}
+
+		content, removed, err := xcscheme.RemoveBuildableReferences(content, targetID)
+		if err != nil {
+			return fmt.Errorf("failed to remove target from scheme (%s): %s", pth, err)
+		}
+		if !removed {
+			continue
+		}
+
+		var scheme xcscheme.Scheme
+		if err := xml.Unmarshal(content, &scheme); err != nil {
+			return fmt.Errorf("failed to unmarshal scheme file: %s, error: %s", pth, err)
+		}
+
+		if len(scheme.BuildAction.BuildActionEntries) == 0 {
+			if err := os.Remove(pth); err != nil {
+				return fmt.Errorf("failed to remove scheme (%s): %s", pth, err)
+			}
+			continue
+		}
+
+		if err := fileutil.WriteBytesToFile(pth, content); err != nil {
+			return fmt.Errorf("failed to write scheme (%s): %s", pth, err)
+		}
+	}
+
+	return nil
+}
+
+// RenameChange is a change made by RenameTarget.
+type RenameChange struct {
+	// Path is the path of the changed file: the project.pbxproj or a scheme.
+	Path string
+	// Object is the ID of the changed project object, or the changed scheme element.
+	Object string
+	Key    string
+	Old    string
+	New    string
+}
+
+// String ...
+func (c RenameChange) String() string {
+	return fmt.Sprintf("%s: %s %s: %s -> %s", filepath.Base(c.Path), c.Object, c.Key, c.Old, c.New)
+}
+
+// RenameTarget renames the target and updates the references to its name:
+// - the target's name and productName
+// - the product reference's path, if the product is named after the target
+// - the PRODUCT_NAME build setting, if it is set to the target name
+// - the INFOPLIST_FILE build setting's path components named after the target, if renameInfoPlistPath is set
+// - the TEST_TARGET_NAME and TEST_HOST build settings of the targets testing the renamed target
+// - the remoteInfo of the container item proxies pointing to the target
+// - the BlueprintName and BuildableName of the target's BuildableReferences in the project's schemes
+//
+// The files on the disk are not moved, set renameInfoPlistPath only if the target's Info.plist files are moved
+// to the renamed directories (App/Info.plist -> NewApp/Info.plist) by the caller.
+// The schemes are written immediately, the project is not saved, call Save to write the project changes.
+func (p *XcodeProj) RenameTarget(oldName, newName string, renameInfoPlistPath bool) ([]RenameChange, error) {
+	target, ok := p.Proj.TargetByName(oldName)
+	if !ok {
+		return nil, fmt.Errorf("target not found: %s", oldName)
+	}
+	if newName == "" {
+		return nil, fmt.Errorf("new target name is empty")
+	}
+	if _, ok := p.Proj.TargetByName(newName); ok {
+		return nil, fmt.Errorf("target already exists: %s", newName)
+	}
+
+	objects, _, err := p.rawObjects()
+	if err != nil {
+		return nil, err
+	}
+
+	pbxProjPth := filepath.Join(p.Path, "project.pbxproj")
+	var changes []RenameChange
+	set := func(object serialized.Object, id, key, value string) {
+		changes = append(changes, RenameChange{Path: pbxProjPth, Object: id, Key: key, Old: optionalString(object, key), New: value})
+		object[key] = value
+	}
+
+	rawTarget, err := objects.Object(target.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access target (%s): %s", target.ID, err)
+	}
+	set(rawTarget, target.ID, "name", newName)
+	if optionalString(rawTarget, "productName") == oldName {
+		set(rawTarget, target.ID, "productName", newName)
+	}
+
+	var oldProductPath, newProductPath string
+	if productReferenceID := optionalString(rawTarget, "productReference"); productReferenceID != "" {
+		productReference, err := objects.Object(productReferenceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to access product reference (%s): %s", productReferenceID, err)
+		}
+
+		pth := optionalString(productReference, "path")
+		ext := filepath.Ext(pth)
+		if strings.TrimSuffix(pth, ext) == oldName {
+			oldProductPath, newProductPath = pth, newName+ext
+			set(productReference, productReferenceID, "path", newProductPath)
+		}
+	}
+
+	for _, buildConfiguration := range target.BuildConfigurationList.BuildConfigurations {
+		buildSettings, err := rawBuildSettings(objects, buildConfiguration.ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if optionalString(buildSettings, "PRODUCT_NAME") == oldName {
+			set(buildSettings, buildConfiguration.ID, "PRODUCT_NAME", newName)
+		}
+		if infoPlistPth := optionalString(buildSettings, "INFOPLIST_FILE"); renameInfoPlistPath && infoPlistPth != "" {
+			if renamed := renamePathComponent(infoPlistPth, oldName, newName); renamed != infoPlistPth {
+				set(buildSettings, buildConfiguration.ID, "INFOPLIST_FILE", renamed)
+			}
+		}
+	}
+
+	var oldTestHost, newTestHost string
+	if filepath.Ext(oldProductPath) == ".app" {
+		oldTestHost = "/" + oldProductPath + "/" + oldName
+		newTestHost = "/" + newProductPath + "/" + newName
+	}
+	for _, other := range p.Proj.Targets {
+		for _, buildConfiguration := range other.BuildConfigurationList.BuildConfigurations {
+			buildSettings, err := rawBuildSettings(objects, buildConfiguration.ID)
+			if err != nil {
+				return nil, err
+			}
+
+			if optionalString(buildSettings, "TEST_TARGET_NAME") == oldName {
+				set(buildSettings, buildConfiguration.ID, "TEST_TARGET_NAME", newName)
+			}
+			if testHost := optionalString(buildSettings, "TEST_HOST"); oldTestHost != "" && strings.Contains(testHost, oldTestHost) {
+				set(buildSettings, buildConfiguration.ID, "TEST_HOST", strings.Replace(testHost, oldTestHost, newTestHost, -1))
+			}
+		}
+	}
+
+	for id := range objects {
+		object, err := objects.Object(id)
+		if err != nil || optionalString(object, "isa") != "PBXContainerItemProxy" {
+			continue
+		}
+		if optionalString(object, "remoteGlobalIDString") == target.ID && optionalString(object, "remoteInfo") == oldName {
+			set(object, id, "remoteInfo", newName)
+		}
+	}
+
+	if err := p.reloadProj(); err != nil {
+		return nil, err
+	}
+
+	schemeChanges, err := p.renameTargetInSchemes(target.ID, newName, newProductPath)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(changes, schemeChanges...), nil
+}
+
+// renameTargetInSchemes updates the BlueprintName (and the BuildableName if the product was renamed)
+// of the target's BuildableReferences in the project's schemes.
+func (p XcodeProj) renameTargetInSchemes(targetID, newName, newProductPath string) ([]RenameChange, error) {
+	if p.Path == "" {
+		return nil, nil
+	}
+
+	schemes, err := p.Schemes()
+	if err != nil {
+		return nil, err
+	}
+
+	values := map[string]string{"BlueprintName": newName}
+	if newProductPath != "" {
+		values["BuildableName"] = newProductPath
+	}
+
+	var changes []RenameChange
+	for _, scheme := range schemes {
+		content, err := fileutil.ReadBytesFromFile(scheme.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		content, attributeChanges, err := xcscheme.SetBuildableReferenceAttributes(content, targetID, values)
+		if err != nil {
+			return nil, fmt.Errorf("failed to rename target in scheme (%s): %s", scheme.Path, err)
+		}
+		if len(attributeChanges) == 0 {
+			continue
+		}
+
+		if err := fileutil.WriteBytesToFile(scheme.Path, content); err != nil {
+			return nil, fmt.Errorf("failed to write scheme (%s): %s", scheme.Path, err)
+		}
+
+		for _, change := range attributeChanges {
+			changes = append(changes, RenameChange{Path: scheme.Path, Object: "BuildableReference", Key: change.Name, Old: change.Old, New: change.New})
+		}
+	}
+
+	return changes, nil
+}
+
+func rawBuildSettings(objects serialized.Object, buildConfigurationID string) (serialized.Object, error) {
+	buildConfiguration, err := objects.Object(buildConfigurationID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to access build configuration (%s): %s", buildConfigurationID, err)
+	}
+
+	buildSettings, err := buildConfiguration.Object("buildSettings")
+	if err != nil {
+		return nil, fmt.Errorf("failed to access build settings of build configuration (%s): %s", buildConfigurationID, err)
+	}
+	return buildSettings, nil
+}
+
+// renamePathComponent replaces the path components equal to oldName: App/Info.plist -> NewApp/Info.plist
+func renamePathComponent(pth, oldName, newName string) string {
+	components := strings.Split(pth, "/")
+	for i, component := range components {
+		if component == oldName {
+			components[i] = newName
+		}
+	}
+	return strings.Join(components, "/")
+}
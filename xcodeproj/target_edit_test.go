@@ -0,0 +1,338 @@
+package xcodeproj
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/serialized"
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestXcodeProj_AddTarget(t *testing.T) {
+	pth := createProjectInTmpDir(t, "XcodeProj", testhelper.XcodeProjectTest, nil)
+	project, err := Open(pth)
+	require.NoError(t, err)
+
+	target, err := project.AddTarget("Widget", AppExtensionProductType)
+	require.NoError(t, err)
+	require.Equal(t, 24, len(target.ID))
+	require.Equal(t, strings.ToUpper(target.ID), target.ID)
+	require.Equal(t, NativeTargetType, target.Type)
+	require.Equal(t, "com.apple.product-type.app-extension", target.ProductType)
+	require.Equal(t, "Widget.appex", target.ProductReference.Path)
+	require.True(t, target.IsAppExtensionProduct())
+
+	var buildPhaseTypes []BuildPhaseType
+	for _, buildPhase := range target.BuildPhases {
+		buildPhaseTypes = append(buildPhaseTypes, buildPhase.Type)
+		require.Equal(t, 0, len(buildPhase.Files))
+	}
+	require.Equal(t, []BuildPhaseType{SourcesBuildPhaseType, FrameworksBuildPhaseType, ResourcesBuildPhaseType}, buildPhaseTypes)
+
+	require.Equal(t, "Release", target.BuildConfigurationList.DefaultConfigurationName)
+	var configurationNames []string
+	for _, buildConfiguration := range target.BuildConfigurationList.BuildConfigurations {
+		configurationNames = append(configurationNames, buildConfiguration.Name)
+		require.Equal(t, "$(TARGET_NAME)", buildConfiguration.BuildSettings["PRODUCT_NAME"])
+		require.Equal(t, "YES", buildConfiguration.BuildSettings["SKIP_INSTALL"])
+	}
+	require.Equal(t, []string{"Debug", "Release"}, configurationNames)
+
+	targetAttributes, err := project.TargetAttributes()
+	require.NoError(t, err)
+	targetAttribute, err := targetAttributes.Object(target.ID)
+	require.NoError(t, err)
+	require.Equal(t, serialized.Object{"CreatedOnToolsVersion": "9.4"}, targetAttribute)
+
+	objects, rawTarget := rawTargetObject(t, project, target.ID)
+	productReferenceID, err := rawTarget.String("productReference")
+	require.NoError(t, err)
+	require.Contains(t, objects, productReferenceID)
+
+	productReference, ok := project.Proj.Element(productReferenceID)
+	require.True(t, ok)
+	require.Equal(t, "Products", productReference.(*FileReference).Parent().DisplayName())
+
+	_, err = project.AddTarget("Widget", AppExtensionProductType)
+	require.EqualError(t, err, "target already exists: Widget")
+
+	_, err = project.AddTarget("Static", ProductType("com.apple.product-type.library.static"))
+	require.EqualError(t, err, "unsupported product type: com.apple.product-type.library.static")
+
+	t.Log("saved project")
+	{
+		require.NoError(t, project.Save())
+
+		content, err := ioutil.ReadFile(filepath.Join(pth, "project.pbxproj"))
+		require.NoError(t, err)
+		require.Contains(t, string(content), "\t\t"+target.ID+" /* Widget */ = {\n\t\t\tisa = PBXNativeTarget;\n")
+		require.Contains(t, string(content), productReferenceID+" /* Widget.appex */ = {isa = PBXFileReference; explicitFileType = \"wrapper.app-extension\"; includeInIndex = 0; path = Widget.appex; sourceTree = BUILT_PRODUCTS_DIR; };")
+
+		reopened, err := Open(pth)
+		require.NoError(t, err)
+
+		reopenedTarget, ok := reopened.Proj.TargetByName("Widget")
+		require.True(t, ok)
+		require.Equal(t, target, reopenedTarget)
+	}
+}
+
+func TestXcodeProj_AddTarget_Framework(t *testing.T) {
+	project, err := parsePBXProjContent([]byte(testhelper.XcodeProjectTest))
+	require.NoError(t, err)
+
+	target, err := project.AddTarget("Core", FrameworkProductType)
+	require.NoError(t, err)
+	require.Equal(t, "Core.framework", target.ProductReference.Path)
+	require.Equal(t, HeadersBuildPhaseType, target.BuildPhases[0].Type)
+
+	_, ok := project.Proj.TargetByName("Core")
+	require.True(t, ok)
+}
+
+func TestXcodeProj_AddTestTarget(t *testing.T) {
+	const appID = "7D5B35FB20E28EE80022BAE6"
+
+	project, err := parsePBXProjContent([]byte(testhelper.XcodeProjectTest))
+	require.NoError(t, err)
+
+	t.Log("unit test target")
+	{
+		target, err := project.AddTestTarget("XcodeProjTests", UnitTestBundleProductType, "XcodeProj")
+		require.NoError(t, err)
+		require.Equal(t, "XcodeProjTests.xctest", target.ProductReference.Path)
+		require.Equal(t, 1, len(target.Dependencies))
+		require.Equal(t, appID, target.Dependencies[0].Target.ID)
+
+		for _, buildConfiguration := range target.BuildConfigurationList.BuildConfigurations {
+			require.Equal(t, "$(TEST_HOST)", buildConfiguration.BuildSettings["BUNDLE_LOADER"])
+			require.Equal(t, "$(BUILT_PRODUCTS_DIR)/XcodeProj.app/$(BUNDLE_EXECUTABLE_FOLDER_PATH)/XcodeProj", buildConfiguration.BuildSettings["TEST_HOST"])
+			require.NotContains(t, buildConfiguration.BuildSettings, "TEST_TARGET_NAME")
+		}
+
+		targetAttributes, err := project.TargetAttributes()
+		require.NoError(t, err)
+		targetAttribute, err := targetAttributes.Object(target.ID)
+		require.NoError(t, err)
+		require.Equal(t, serialized.Object{"CreatedOnToolsVersion": "9.4", "TestTargetID": appID}, targetAttribute)
+	}
+
+	t.Log("UI test target")
+	{
+		target, err := project.AddTestTarget("XcodeProjUITests2", UITestBundleProductType, "XcodeProj")
+		require.NoError(t, err)
+		require.Equal(t, appID, target.Dependencies[0].Target.ID)
+
+		for _, buildConfiguration := range target.BuildConfigurationList.BuildConfigurations {
+			require.Equal(t, "XcodeProj", buildConfiguration.BuildSettings["TEST_TARGET_NAME"])
+			require.NotContains(t, buildConfiguration.BuildSettings, "TEST_HOST")
+		}
+	}
+
+	_, err = project.AddTestTarget("Tests", UnitTestBundleProductType, "NotExisting")
+	require.EqualError(t, err, "host target not found: NotExisting")
+
+	_, err = project.AddTestTarget("Core", FrameworkProductType, "XcodeProj")
+	require.EqualError(t, err, "not a test product type: com.apple.product-type.framework")
+}
+
+func rawTargetObject(t *testing.T, project XcodeProj, id string) (serialized.Object, serialized.Object) {
+	objects, err := project.RawProj.Object("objects")
+	require.NoError(t, err)
+	rawTarget, err := objects.Object(id)
+	require.NoError(t, err)
+	return objects, rawTarget
+}
+
+func Test_toolsVersion(t *testing.T) {
+	for lastUpgradeCheck, want := range map[string]string{
+		"1240": "12.4",
+		"0940": "9.4",
+		"1431": "14.3.1",
+		"":     "",
+		"12a0": "",
+	} {
+		require.Equal(t, want, toolsVersion(lastUpgradeCheck), lastUpgradeCheck)
+	}
+}
+
+func TestXcodeProj_RemoveTarget(t *testing.T) {
+	const (
+		appID       = "7D5B35FB20E28EE80022BAE6"
+		extensionID = "7D03430C20F4BB070050B6A6"
+	)
+
+	pth := createProjectInTmpDir(t, "XcodeProj", testhelper.XcodeProjectTest, map[string]string{
+		"XcodeProj.xcodeproj/xcshareddata/xcschemes/XcodeProj.xcscheme":      testSchemeContent(appID, extensionID),
+		"XcodeProj.xcodeproj/xcshareddata/xcschemes/TodayExtension.xcscheme": testSchemeContent(extensionID),
+	})
+	project, err := Open(pth)
+	require.NoError(t, err)
+
+	objects, err := project.RawProj.Object("objects")
+	require.NoError(t, err)
+	objectCount := len(objects)
+
+	require.NoError(t, project.RemoveTarget("TodayExtension", true))
+
+	_, ok := project.Proj.TargetByName("TodayExtension")
+	require.False(t, ok)
+
+	app, ok := project.Proj.Target(appID)
+	require.True(t, ok)
+	require.Equal(t, 0, len(app.Dependencies))
+	for _, buildPhase := range app.BuildPhasesOfType(CopyFilesBuildPhaseType) {
+		require.Equal(t, 0, len(buildPhase.Files))
+	}
+
+	for _, id := range []string{
+		extensionID,
+		"7D03430D20F4BB070050B6A6", // TodayExtension.appex
+		"7D03431A20F4BB070050B6A6", // TodayExtension.appex in Embed App Extensions
+		"7D03431920F4BB070050B6A6", // PBXTargetDependency
+		"7D03431820F4BB070050B6A6", // PBXContainerItemProxy
+		"7D03431320F4BB070050B6A6", // TodayViewController.swift in Sources
+		"7D03431020F4BB070050B6A6", // NotificationCenter.framework in Frameworks
+		"7D03431620F4BB070050B6A6", // MainInterface.storyboard in Resources
+	} {
+		require.NotContains(t, objects, id)
+	}
+	for _, id := range []string{
+		"7D03431220F4BB070050B6A6", // TodayViewController.swift
+		"7D03431420F4BB070050B6A6", // MainInterface.storyboard
+		"7D03430F20F4BB070050B6A6", // NotificationCenter.framework
+	} {
+		require.Contains(t, objects, id)
+	}
+	// target, product, embedding build file, dependency, proxy, 3 build phases, 3 build files, configuration list, 2 configurations
+	require.Equal(t, objectCount-15, len(objects))
+
+	targetAttributes, err := project.TargetAttributes()
+	require.NoError(t, err)
+	require.NotContains(t, targetAttributes, extensionID)
+
+	schemesDir := filepath.Join(pth, "xcshareddata", "xcschemes")
+	require.NoFileExists(t, filepath.Join(schemesDir, "TodayExtension.xcscheme"))
+	scheme, err := ioutil.ReadFile(filepath.Join(schemesDir, "XcodeProj.xcscheme"))
+	require.NoError(t, err)
+	require.Equal(t, testSchemeContent(appID), string(scheme))
+
+	require.NoError(t, project.Save())
+	reopened, err := Open(pth)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(reopened.Proj.Targets))
+
+	require.EqualError(t, project.RemoveTarget("TodayExtension", false), "target not found: TodayExtension")
+}
+
+func testSchemeContent(blueprintIdentifiers ...string) string {
+	var entries strings.Builder
+	for _, id := range blueprintIdentifiers {
+		entries.WriteString(`         <BuildActionEntry
+            buildForRunning = "YES">
+            <BuildableReference
+               BuildableIdentifier = "primary"
+               BlueprintIdentifier = "` + id + `"
+               ReferencedContainer = "container:XcodeProj.xcodeproj">
+            </BuildableReference>
+         </BuildActionEntry>
+`)
+	}
+
+	return `<?xml version="1.0" encoding="UTF-8"?>
+<Scheme
+   version = "1.3">
+   <BuildAction>
+      <BuildActionEntries>
+` + entries.String() + `      </BuildActionEntries>
+   </BuildAction>
+</Scheme>
+`
+}
+
+func TestXcodeProj_RenameTarget(t *testing.T) {
+	const appID = "7D5B35FB20E28EE80022BAE6"
+	scheme := `<?xml version="1.0" encoding="UTF-8"?>
+<Scheme
+   version = "1.3">
+   <BuildAction>
+      <BuildActionEntries>
+         <BuildActionEntry
+            buildForRunning = "YES">
+            <BuildableReference
+               BuildableIdentifier = "primary"
+               BlueprintIdentifier = "` + appID + `"
+               BuildableName = "XcodeProj.app"
+               BlueprintName = "XcodeProj"
+               ReferencedContainer = "container:XcodeProj.xcodeproj">
+            </BuildableReference>
+         </BuildActionEntry>
+      </BuildActionEntries>
+   </BuildAction>
+</Scheme>
+`
+	schemePth := filepath.Join("XcodeProj.xcodeproj", "xcshareddata", "xcschemes", "XcodeProj.xcscheme")
+	pth := createProjectInTmpDir(t, "XcodeProj", testhelper.XcodeProjectTest, map[string]string{schemePth: scheme})
+	project, err := Open(pth)
+	require.NoError(t, err)
+
+	changes, err := project.RenameTarget("XcodeProj", "WhiteLabel", false)
+	require.NoError(t, err)
+
+	pbxProjPth := filepath.Join(pth, "project.pbxproj")
+	schemeAbsPth := filepath.Join(filepath.Dir(pth), schemePth)
+	require.Equal(t, []RenameChange{
+		{Path: pbxProjPth, Object: appID, Key: "name", Old: "XcodeProj", New: "WhiteLabel"},
+		{Path: pbxProjPth, Object: appID, Key: "productName", Old: "XcodeProj", New: "WhiteLabel"},
+		{Path: pbxProjPth, Object: "7D5B35FC20E28EE80022BAE6", Key: "path", Old: "XcodeProj.app", New: "WhiteLabel.app"},
+		{Path: pbxProjPth, Object: "7D0342F820F4BA280050B6A6", Key: "TEST_TARGET_NAME", Old: "XcodeProj", New: "WhiteLabel"},
+		{Path: pbxProjPth, Object: "7D0342F920F4BA280050B6A6", Key: "TEST_TARGET_NAME", Old: "XcodeProj", New: "WhiteLabel"},
+		{Path: pbxProjPth, Object: "7D0342F620F4BA280050B6A6", Key: "remoteInfo", Old: "XcodeProj", New: "WhiteLabel"},
+		{Path: schemeAbsPth, Object: "BuildableReference", Key: "BlueprintName", Old: "XcodeProj", New: "WhiteLabel"},
+		{Path: schemeAbsPth, Object: "BuildableReference", Key: "BuildableName", Old: "XcodeProj.app", New: "WhiteLabel.app"},
+	}, changes)
+	require.Equal(t, "project.pbxproj: "+appID+" name: XcodeProj -> WhiteLabel", changes[0].String())
+
+	target, ok := project.Proj.TargetByName("WhiteLabel")
+	require.True(t, ok)
+	require.Equal(t, "WhiteLabel.app", target.ProductReference.Path)
+	for _, buildConfiguration := range target.BuildConfigurationList.BuildConfigurations {
+		require.Equal(t, "XcodeProj/Info.plist", buildConfiguration.BuildSettings["INFOPLIST_FILE"])
+	}
+
+	content, err := ioutil.ReadFile(schemeAbsPth)
+	require.NoError(t, err)
+	require.Equal(t, strings.Replace(strings.Replace(scheme, `"XcodeProj"`, `"WhiteLabel"`, 1), `"XcodeProj.app"`, `"WhiteLabel.app"`, 1), string(content))
+
+	_, err = project.RenameTarget("XcodeProj", "WhiteLabel", false)
+	require.EqualError(t, err, "target not found: XcodeProj")
+
+	_, err = project.RenameTarget("WhiteLabel", "TodayExtension", false)
+	require.EqualError(t, err, "target already exists: TodayExtension")
+
+	t.Log("rename Info.plist path")
+	{
+		project, err := parsePBXProjContent([]byte(testhelper.XcodeProjectTest))
+		require.NoError(t, err)
+
+		changes, err := project.RenameTarget("XcodeProj", "WhiteLabel", true)
+		require.NoError(t, err)
+		require.Contains(t, changes, RenameChange{Path: "project.pbxproj", Object: "7D5B360F20E28EEA0022BAE6", Key: "INFOPLIST_FILE", Old: "XcodeProj/Info.plist", New: "WhiteLabel/Info.plist"})
+		require.Contains(t, changes, RenameChange{Path: "project.pbxproj", Object: "7D5B361020E28EEA0022BAE6", Key: "INFOPLIST_FILE", Old: "XcodeProj/Info.plist", New: "WhiteLabel/Info.plist"})
+
+		target, ok := project.Proj.TargetByName("WhiteLabel")
+		require.True(t, ok)
+		for _, buildConfiguration := range target.BuildConfigurationList.BuildConfigurations {
+			require.Equal(t, "WhiteLabel/Info.plist", buildConfiguration.BuildSettings["INFOPLIST_FILE"])
+		}
+	}
+}
+
+func Test_renamePathComponent(t *testing.T) {
+	require.Equal(t, "WhiteLabel/Info.plist", renamePathComponent("XcodeProj/Info.plist", "XcodeProj", "WhiteLabel"))
+	require.Equal(t, "$(SRCROOT)/WhiteLabel/Info.plist", renamePathComponent("$(SRCROOT)/XcodeProj/Info.plist", "XcodeProj", "WhiteLabel"))
+	require.Equal(t, "XcodeProjTests/Info.plist", renamePathComponent("XcodeProjTests/Info.plist", "XcodeProj", "WhiteLabel"))
+}
@@ -2,11 +2,13 @@ package xcodeproj
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/bitrise-io/go-plist"
 	"github.com/bitrise-io/go-utils/pretty"
 	"github.com/bitrise-io/xcode-project/serialized"
+	"github.com/bitrise-io/xcode-project/testhelper"
 	"github.com/stretchr/testify/require"
 )
 
@@ -156,6 +158,55 @@ func TestParseTarget(t *testing.T) {
 	}
 }
 
+func TestTargetParser_DependencyCycle(t *testing.T) {
+	// XcodeProj depends on TodayExtension and TodayExtension depends on XcodeProj
+	content := strings.Replace(testhelper.XcodeProjectTest, `			dependencies = (
+			);
+			name = TodayExtension;`, `			dependencies = (
+				7D0342F720F4BA280050B6A6 /* PBXTargetDependency */,
+			);
+			name = TodayExtension;`, 1)
+	var raw serialized.Object
+	_, err := plist.Unmarshal([]byte(content), &raw)
+	require.NoError(t, err)
+	objects, err := raw.Object("objects")
+	require.NoError(t, err)
+
+	const (
+		xcodeProjID      = "7D5B35FB20E28EE80022BAE6"
+		todayExtensionID = "7D03430C20F4BB070050B6A6"
+	)
+	dependencyNames := func(target Target) []string {
+		var names []string
+		for _, dependency := range target.Dependencies {
+			names = append(names, dependency.Target.Name)
+		}
+		return names
+	}
+
+	for _, ids := range [][]string{{xcodeProjID, todayExtensionID}, {todayExtensionID, xcodeProjID}} {
+		t.Logf("parse starting from %s", ids[0])
+		{
+			parser := newTargetParser(objects)
+			for _, id := range ids {
+				_, err := parser.parse(id)
+				require.NoError(t, err)
+			}
+
+			xcodeProj, err := parser.parse(xcodeProjID)
+			require.NoError(t, err)
+			require.Equal(t, []string{"TodayExtension"}, dependencyNames(xcodeProj))
+			// the cycle is cut at the dependency leading back to the parsed target
+			require.Equal(t, 0, len(xcodeProj.Dependencies[0].Target.Dependencies))
+
+			todayExtension, err := parser.parse(todayExtensionID)
+			require.NoError(t, err)
+			require.Equal(t, []string{"XcodeProj"}, dependencyNames(todayExtension))
+			require.Equal(t, 0, len(todayExtension.Dependencies[0].Target.Dependencies))
+		}
+	}
+}
+
 const rawLegacyTarget = `{
 	407952600CEA391500E202DC /* build */ = {
 		isa = PBXLegacyTarget;
@@ -204,7 +255,9 @@ const expectedLegacyTarget = `{
 				"Name": "Release",
 				"BuildSettings": {
 					"PATH": "$(PATH):/usr/local/CrossPack-AVR/bin"
-				}
+				},
+				"BaseConfigurationReference": "",
+				"BaseConfiguration": null
 			}
 		]
 	},
@@ -212,7 +265,9 @@ const expectedLegacyTarget = `{
 	"ProductReference": {
 		"Path": ""
 	},
-	"ProductType": "com.apple.product-type.application"
+	"ProductType": "com.apple.product-type.application",
+	"BuildPhases": null,
+	"PackageProductDependencies": null
 }`
 
 const rawAggregateTarget = `{
@@ -263,7 +318,9 @@ const expectedAggregateTarget = `{
 					"INSTALLHDRS_SCRIPT_PHASE": "YES",
 					"PRODUCT_BUNDLE_IDENTIFIER": "Bitrise.$(PRODUCT_NAME:rfc1034identifier).watch",
 					"PRODUCT_NAME": "$(TARGET_NAME)"
-				}
+				},
+				"BaseConfigurationReference": "",
+				"BaseConfiguration": null
 			}
 		]
 	},
@@ -271,7 +328,9 @@ const expectedAggregateTarget = `{
 	"ProductReference": {
 		"Path": ""
 	},
-	"ProductType": ""
+	"ProductType": "",
+	"BuildPhases": null,
+	"PackageProductDependencies": null
 }`
 
 const rawNativeTarget = `{
@@ -393,7 +452,9 @@ const expectedNativeTarget = `{
 					"PROVISIONING_PROFILE": "",
 					"PROVISIONING_PROFILE_SPECIFIER": "",
 					"TARGETED_DEVICE_FAMILY": "1,2"
-				}
+				},
+				"BaseConfigurationReference": "",
+				"BaseConfiguration": null
 			},
 			{
 				"ID": "13E76E3C1F4AC90A0028096E",
@@ -410,7 +471,9 @@ const expectedNativeTarget = `{
 					"PROVISIONING_PROFILE": "",
 					"PROVISIONING_PROFILE_SPECIFIER": "",
 					"TARGETED_DEVICE_FAMILY": "1,2"
-				}
+				},
+				"BaseConfigurationReference": "",
+				"BaseConfiguration": null
 			}
 		]
 	},
@@ -440,7 +503,9 @@ const expectedNativeTarget = `{
 								"PROVISIONING_PROFILE": "",
 								"PROVISIONING_PROFILE_SPECIFIER": "",
 								"TARGETED_DEVICE_FAMILY": "1,2"
-							}
+							},
+							"BaseConfigurationReference": "",
+							"BaseConfiguration": null
 						},
 						{
 							"ID": "13E76E3C1F4AC90A0028096E",
@@ -457,7 +522,9 @@ const expectedNativeTarget = `{
 								"PROVISIONING_PROFILE": "",
 								"PROVISIONING_PROFILE_SPECIFIER": "",
 								"TARGETED_DEVICE_FAMILY": "1,2"
-							}
+							},
+							"BaseConfigurationReference": "",
+							"BaseConfiguration": null
 						}
 					]
 				},
@@ -465,12 +532,16 @@ const expectedNativeTarget = `{
 				"ProductReference": {
 					"Path": "share-extension.appex"
 				},
-				"ProductType": "com.apple.product-type.app-extension"
+				"ProductType": "com.apple.product-type.app-extension",
+				"BuildPhases": null,
+				"PackageProductDependencies": null
 			}
 		}
 	],
 	"ProductReference": {
 		"Path": "code-sign-test.app"
 	},
-	"ProductType": "com.apple.product-type.application"
+	"ProductType": "com.apple.product-type.application",
+	"BuildPhases": null,
+	"PackageProductDependencies": null
 }`
@@ -1,6 +1,7 @@
 package xcodeproj
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io/ioutil"
@@ -27,6 +28,10 @@ type XcodeProj struct {
 	Proj    Proj
 	RawProj serialized.Object
 	Format  int
+	// OfflineBuildSettings makes the build settings based methods (like TargetBuildSettings and TargetBundleID)
+	// evaluate the build settings from the project files instead of calling xcodebuild,
+	// so they can be used without Xcode installed.
+	OfflineBuildSettings bool
 	// Used to replace project in-place. This leaves the order of objects and comments for unchanged objects unchanged.
 	// It allows better compatibility with Cordova and the Xcode agvtool
 	originalContents                  []byte
@@ -187,97 +192,21 @@ func (p XcodeProj) TargetBundleID(target, configuration string) (string, error)
 //**Example:**
 //BundleID in the .pbxproj: Bitrise.Test.$(PRODUCT_NAME:rfc1034identifier).Suffix
 //BundleID after the env is expanded: Bitrise.Test.Sample.Suffix
+//
+// Resolve is a shorthand for ExpandStrict, see Expand for the supported macros.
+// Unlike Xcode, it returns an error if the bundleID references an undefined build setting,
+// instead of resolving an incomplete bundle ID.
 func Resolve(bundleID string, buildSettings serialized.Object) (string, error) {
-	resolvedBundleIDs := map[string]bool{}
-	resolved := bundleID
-	for true {
-		if !strings.Contains(resolved, "$") {
-			return resolved, nil
-		}
-
-		var err error
-		resolved, err = expand(resolved, buildSettings)
-		if err != nil {
-			return "", err
-		}
-
-		_, ok := resolvedBundleIDs[resolved]
-		if ok {
-			return "", fmt.Errorf("bundle id reference cycle found")
-		}
-		resolvedBundleIDs[resolved] = true
-	}
-	return "", fmt.Errorf("failed to resolve bundle id: %s", bundleID)
-}
-
-func expand(bundleID string, buildSettings serialized.Object) (string, error) {
-	r, err := regexp.Compile("[$][{(][^$]*?[)}]")
-	if err != nil {
-		return "", err
-	}
-	if r.MatchString(bundleID) {
-		// envs like:  $(PRODUCT_NAME:rfc1034identifier) || $(PRODUCT_NAME) || ${PRODUCT_NAME:rfc1034identifier} || ${PRODUCT_NAME}
-		return expandComplexEnv(bundleID, buildSettings)
-	}
-	// envs like: $PRODUCT_NAME
-	return expandSimpleEnv(bundleID, buildSettings)
-}
-
-// expandComplexEnv expands the env with the "[$][{(][^$]*?[)}]" regex
-// **Example:** `prefix.$(ENV_KEY:rfc1034identifier).suffix.$(ENV_KEY:rfc1034identifier)` **=>** `auto_provision.ios-simple-objc.suffix.ios-simple-objc`
-func expandComplexEnv(bundleID string, buildSettings serialized.Object) (string, error) {
-	r, err := regexp.Compile("[$][{(][^$]*?[)}]")
-	if err != nil {
-		return "", err
-	}
-	rawEnvKey := r.FindString(bundleID)
-
-	replacer := strings.NewReplacer("$", "", "(", "", ")", "", "{", "", "}", "")
-	envKey := strings.Split(replacer.Replace(rawEnvKey), ":")[0]
-
-	envValue, ok := envInBuildSettings(envKey, buildSettings)
-	if !ok {
-		return "", fmt.Errorf("failed to find env in build settings: %s", envKey)
-	}
-	return strings.Replace(bundleID, rawEnvKey, envValue, -1), nil
-}
-
-// expandSimpleEnv expands the env with the "[$][^$]*" regex
-// **Example:** `prefix.$ENV_KEY.suffix.$ENV_KEY` **=>** `auto_provision.ios-simple-objc.suffix.ios-simple-objc`
-func expandSimpleEnv(bundleID string, buildSettings serialized.Object) (string, error) {
-	r, err := regexp.Compile("[$][^$]*")
-	if err != nil {
-		return "", err
-	}
-	if !r.MatchString(bundleID) {
-		return "", fmt.Errorf("failed to match regex [$][^$]* for %s", bundleID)
-	}
-	envKey := r.FindString(bundleID)
-
-	var envValue string
-	for len(envKey) > 1 {
-		var ok bool
-		envValue, ok = envInBuildSettings(strings.Replace(envKey, "$", "", 1), buildSettings)
-		if ok {
-			break
-		}
-
-		envKey = envKey[:len(envKey)-1]
-	}
-	return strings.Replace(bundleID, envKey, envValue, -1), nil
-
-}
-
-func envInBuildSettings(envKey string, buildSettings serialized.Object) (string, bool) {
-	envValue, err := buildSettings.String(envKey)
-	if err != nil {
-		return "", false
-	}
-	return envValue, true
+	return ExpandStrict(bundleID, buildSettings)
 }
 
 // TargetBuildSettings ...
+//
+// If OfflineBuildSettings is set the settings are resolved by ResolveTargetBuildSettings and customOptions are ignored.
 func (p XcodeProj) TargetBuildSettings(target, configuration string, customOptions ...string) (serialized.Object, error) {
+	if p.OfflineBuildSettings {
+		return p.ResolveTargetBuildSettings(target, configuration)
+	}
 	return xcodebuild.ShowProjectBuildSettings(p.Path, target, configuration, customOptions...)
 }
 
@@ -324,6 +253,7 @@ func Open(pth string) (XcodeProj, error) {
 
 	p.Path = absPth
 	p.Name = strings.TrimSuffix(filepath.Base(absPth), filepath.Ext(absPth))
+	p.loadBaseConfigurations()
 
 	return *p, nil
 }
@@ -509,7 +439,7 @@ func (p XcodeProj) savePBXProj() error {
 	// merr != nil
 	log.Warnf("failed to modify project in-place: %v", merr)
 
-	newContent, err := plist.MarshalIndent(p.RawProj, p.Format, "\t")
+	newContent, err := p.Marshal()
 	if err != nil {
 		return fmt.Errorf("failed to marshal .pbxproj: %v", err)
 	}
@@ -592,6 +522,8 @@ func deepCopy(o interface{}) interface{} {
 type change struct {
 	start, end int
 	rawObject  []byte
+	// key orders the changes made at the same position
+	key string
 }
 
 func (p XcodeProj) perObjectModify() ([]byte, error) {
@@ -608,21 +540,24 @@ func (p XcodeProj) perObjectModify() ([]byte, error) {
 		return nil, fmt.Errorf("failed to parse project: %v", err)
 	}
 
+	encoder := newPBXProjEncoder(objectsMod, p.Name)
+
 	var mods []change
+	var newIDs []string
 	for keyMod := range objectsMod {
 		objectMod, err := objectsMod.Object(keyMod)
 		if err != nil {
 			return nil, fmt.Errorf("%s", err)
 		}
 
-		objectOrig, err := objectsOrig.Object(keyMod)
-		if err != nil {
-			return nil, fmt.Errorf("new object added, not in original project: %v", err)
+		if _, ok := objectsOrig[keyMod]; !ok {
+			newIDs = append(newIDs, keyMod)
+			continue
 		}
 
-		objectsAnnotated, err := objectsAnnotated.Object(keyMod)
+		objectOrig, err := objectsOrig.Object(keyMod)
 		if err != nil {
-			return nil, fmt.Errorf("new object added, not in original annotated project: %v", err)
+			return nil, fmt.Errorf("failed to access original object: %v", err)
 		}
 
 		// If object did not change do nothing
@@ -630,37 +565,60 @@ func (p XcodeProj) perObjectModify() ([]byte, error) {
 			continue
 		}
 
-		customPosDict, err := objectsAnnotated.Object(customAnnotationKey)
+		startPos, endPos, err := objectPosition(objectsAnnotated, keyMod)
 		if err != nil {
-			return nil, fmt.Errorf("no raw object position available: %v", err)
+			return nil, err
 		}
-		startPos, err := customPosDict.Int64(startKey)
+
+		contentMod, err := encoder.objectValue(keyMod)
 		if err != nil {
-			return nil, fmt.Errorf("no raw object start position available: %v", err)
+			return nil, fmt.Errorf("could not marshal object (%s): %v", keyMod, err)
+		}
+
+		mods = append(mods, change{
+			start:     startPos,
+			end:       endPos,
+			rawObject: []byte(contentMod),
+		})
+	}
+
+	// removed objects
+	for keyOrig := range objectsOrig {
+		if _, ok := objectsMod[keyOrig]; ok {
+			continue
 		}
-		endPos, err := customPosDict.Int64(endKey)
+
+		startPos, endPos, err := objectPosition(objectsAnnotated, keyOrig)
 		if err != nil {
-			return nil, fmt.Errorf("no raw end position availbale: %v", err)
+			return nil, err
 		}
 
-		contentMod, err := plist.MarshalIndent(objectMod, p.Format, "\t")
+		lineStart, lineEnd, err := p.objectLines(keyOrig, startPos, endPos)
 		if err != nil {
-			return nil, fmt.Errorf("could not marshal object (%s): %v", objectsMod, err)
+			return nil, err
 		}
 
 		mods = append(mods, change{
-			start:     int(startPos),
-			end:       int(endPos),
-			rawObject: contentMod,
+			start: lineStart,
+			end:   lineEnd,
 		})
 	}
 
+	insertions, err := p.newObjectChanges(encoder, newIDs, objectsMod, objectsOrig, objectsAnnotated)
+	if err != nil {
+		return nil, err
+	}
+	mods = append(mods, insertions...)
+
 	if len(mods) == 0 {
 		return p.originalContents, nil
 	}
 
 	sort.Slice(mods, func(i, j int) bool {
 		if mods[i].start == mods[j].start {
+			if mods[i].end == mods[j].end {
+				return mods[i].key < mods[j].key
+			}
 			return mods[i].end < mods[j].end
 		}
 		return mods[i].start < mods[j].start
@@ -669,7 +627,7 @@ func (p XcodeProj) perObjectModify() ([]byte, error) {
 	var contentsMod []byte
 	previousEndPos := 0
 	for i, mod := range mods {
-		if i < len(mods)-1 && mod.end >= mods[i+1].start {
+		if i < len(mods)-1 && mod.end > mods[i+1].start {
 			return nil, fmt.Errorf("overlapping changes: %d, %d", mods[i].end, mods[i+1].start)
 		}
 
@@ -684,3 +642,212 @@ func (p XcodeProj) perObjectModify() ([]byte, error) {
 
 	return contentsMod, nil
 }
+
+// objectPosition returns the position of the object's value (from `{` to `}`) in the original contents.
+func objectPosition(objectsAnnotated serialized.Object, id string) (int, int, error) {
+	objectAnnotated, err := objectsAnnotated.Object(id)
+	if err != nil {
+		return 0, 0, fmt.Errorf("object not in original annotated project: %v", err)
+	}
+
+	customPosDict, err := objectAnnotated.Object(customAnnotationKey)
+	if err != nil {
+		return 0, 0, fmt.Errorf("no raw object position available: %v", err)
+	}
+	startPos, err := customPosDict.Int64(startKey)
+	if err != nil {
+		return 0, 0, fmt.Errorf("no raw object start position available: %v", err)
+	}
+	endPos, err := customPosDict.Int64(endKey)
+	if err != nil {
+		return 0, 0, fmt.Errorf("no raw end position availbale: %v", err)
+	}
+
+	return int(startPos), int(endPos), nil
+}
+
+// objectLines returns the range of the lines holding the object's entry (`ID /* comment */ = {...};`) in the original contents.
+func (p XcodeProj) objectLines(id string, startPos, endPos int) (int, int, error) {
+	lineStart := bytes.LastIndexByte(p.originalContents[:startPos], '\n') + 1
+	if !bytes.HasPrefix(bytes.TrimLeft(p.originalContents[lineStart:startPos], " \t\""), []byte(id)) {
+		return 0, 0, fmt.Errorf("object (%s) does not start on its own line", id)
+	}
+
+	lineEnd := len(p.originalContents)
+	if idx := bytes.IndexByte(p.originalContents[endPos:], '\n'); idx != -1 {
+		lineEnd = endPos + idx + 1
+	}
+	if string(bytes.TrimSpace(p.originalContents[endPos:lineEnd])) != ";" {
+		return 0, 0, fmt.Errorf("object (%s) does not end on its own line", id)
+	}
+
+	return lineStart, lineEnd, nil
+}
+
+var objectSectionPattern = regexp.MustCompile(`(?m)^/\* (Begin|End) (\w+) section \*/[ \t]*\n`)
+
+// objectSection is an `/* Begin PBXBuildFile section */ ... /* End PBXBuildFile section */` block of the objects.
+type objectSection struct {
+	isa string
+	// begin is the start of the Begin line, end is the start of the End line, after is the end of the End line.
+	begin, end, after int
+}
+
+func parseObjectSections(content []byte) ([]objectSection, error) {
+	var sections []objectSection
+	var current *objectSection
+	for _, match := range objectSectionPattern.FindAllSubmatchIndex(content, -1) {
+		kind := string(content[match[2]:match[3]])
+		isa := string(content[match[4]:match[5]])
+
+		if kind == "Begin" {
+			if current != nil {
+				return nil, fmt.Errorf("section %s is not closed", current.isa)
+			}
+			current = &objectSection{isa: isa, begin: match[0]}
+			continue
+		}
+
+		if current == nil || current.isa != isa {
+			return nil, fmt.Errorf("unexpected end of section %s", isa)
+		}
+		current.end = match[0]
+		current.after = match[1]
+		sections = append(sections, *current)
+		current = nil
+	}
+
+	if current != nil {
+		return nil, fmt.Errorf("section %s is not closed", current.isa)
+	}
+	return sections, nil
+}
+
+// newObjectChanges inserts the new objects into the section of their isa, keeping the section sorted by object ID.
+// Missing sections are created, the sections are kept in alphabetical order like Xcode does.
+func (p XcodeProj) newObjectChanges(encoder pbxprojEncoder, ids []string, objectsMod, objectsOrig, objectsAnnotated serialized.Object) ([]change, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	sections, err := parseObjectSections(p.originalContents)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse object sections: %v", err)
+	}
+	if len(sections) == 0 {
+		return nil, fmt.Errorf("no object sections found")
+	}
+
+	idsByISA := map[string][]string{}
+	for _, id := range ids {
+		object, err := objectsMod.Object(id)
+		if err != nil {
+			return nil, err
+		}
+
+		isa, err := object.String("isa")
+		if err != nil {
+			return nil, fmt.Errorf("failed to read isa of new object (%s): %v", id, err)
+		}
+
+		idsByISA[isa] = append(idsByISA[isa], id)
+	}
+
+	var changes []change
+	for isa, newIDs := range idsByISA {
+		sort.Strings(newIDs)
+
+		entries := map[string][]byte{}
+		for _, id := range newIDs {
+			entry, err := encoder.objectEntry(id)
+			if err != nil {
+				return nil, fmt.Errorf("could not marshal object (%s): %v", id, err)
+			}
+			entries[id] = []byte(entry)
+		}
+
+		sectionIdx := -1
+		for i, section := range sections {
+			if section.isa == isa {
+				sectionIdx = i
+				break
+			}
+		}
+
+		if sectionIdx == -1 {
+			var content []byte
+			for _, id := range newIDs {
+				content = append(content, entries[id]...)
+			}
+
+			pos := -1
+			for _, section := range sections {
+				if section.isa > isa {
+					pos = section.begin
+					break
+				}
+			}
+
+			var rawSection string
+			if pos != -1 {
+				rawSection = fmt.Sprintf("/* Begin %s section */\n%s/* End %s section */\n\n", isa, content, isa)
+			} else {
+				pos = sections[len(sections)-1].after
+				rawSection = fmt.Sprintf("\n/* Begin %s section */\n%s/* End %s section */\n", isa, content, isa)
+			}
+
+			changes = append(changes, change{
+				start:     pos,
+				end:       pos,
+				rawObject: []byte(rawSection),
+				key:       isa,
+			})
+			continue
+		}
+
+		section := sections[sectionIdx]
+
+		type sectionEntry struct {
+			id        string
+			lineStart int
+		}
+		var sectionEntries []sectionEntry
+		for id := range objectsOrig {
+			startPos, endPos, err := objectPosition(objectsAnnotated, id)
+			if err != nil {
+				return nil, err
+			}
+			if startPos < section.begin || endPos > section.end {
+				continue
+			}
+
+			lineStart, _, err := p.objectLines(id, startPos, endPos)
+			if err != nil {
+				return nil, err
+			}
+			sectionEntries = append(sectionEntries, sectionEntry{id: id, lineStart: lineStart})
+		}
+		sort.Slice(sectionEntries, func(i, j int) bool {
+			return sectionEntries[i].lineStart < sectionEntries[j].lineStart
+		})
+
+		for _, id := range newIDs {
+			pos := section.end
+			for _, entry := range sectionEntries {
+				if entry.id > id {
+					pos = entry.lineStart
+					break
+				}
+			}
+
+			changes = append(changes, change{
+				start:     pos,
+				end:       pos,
+				rawObject: entries[id],
+				key:       id,
+			})
+		}
+	}
+
+	return changes, nil
+}
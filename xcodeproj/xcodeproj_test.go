@@ -2,6 +2,7 @@ package xcodeproj
 
 import (
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/bitrise-io/xcode-project/serialized"
@@ -123,10 +124,30 @@ func TestResolve(t *testing.T) {
 			"BUNDLE_ID":    "$(PRODUCT_NAME:rfc1034identifier)",
 		}
 		resolved, err := Resolve(bundleID, buildSettings)
-		require.EqualError(t, err, "bundle id reference cycle found")
+		require.EqualError(t, err, "build setting reference cycle found: BUNDLE_ID -> PRODUCT_NAME -> BUNDLE_ID")
 		require.Equal(t, "", resolved)
 	}
 
+	t.Log("fails on undefined env reference")
+	{
+		bundleID := `com.example.$(PRODUCT_NAME:rfc1034identifier)`
+		resolved, err := Resolve(bundleID, serialized.Object{})
+		require.EqualError(t, err, "failed to find env in build settings: PRODUCT_NAME")
+		require.Equal(t, "", resolved)
+
+		resolved, err = Resolve(`com.example.$PRODUCT_NAME`, serialized.Object{})
+		require.EqualError(t, err, "failed to find env in build settings: PRODUCT_NAME")
+		require.Equal(t, "", resolved)
+	}
+
+	t.Log("resolves undefined env reference with default value")
+	{
+		bundleID := `com.example.$(PRODUCT_NAME:default=app)`
+		resolved, err := Resolve(bundleID, serialized.Object{})
+		require.NoError(t, err)
+		require.Equal(t, "com.example.app", resolved)
+	}
+
 	t.Log("resolves bundle id in format: prefix.$(ENV_KEY:rfc1034identifier).suffix")
 	{
 		bundleID := `auto_provision.$(PRODUCT_NAME:rfc1034identifier).suffix`
@@ -180,7 +201,7 @@ func TestResolve(t *testing.T) {
 			"BUNDLE_ID":    "${PRODUCT_NAME:rfc1034identifier}",
 		}
 		resolved, err := Resolve(bundleID, buildSettings)
-		require.EqualError(t, err, "bundle id reference cycle found")
+		require.EqualError(t, err, "build setting reference cycle found: BUNDLE_ID -> PRODUCT_NAME -> BUNDLE_ID")
 		require.Equal(t, "", resolved)
 	}
 
@@ -271,114 +292,6 @@ func TestResolve(t *testing.T) {
 	}
 }
 
-func TestExpand(t *testing.T) {
-
-	// Complex env
-	t.Log("resolves bundle id in format: prefix.$(ENV_KEY:rfc1034identifier).suffix.$(ENV_KEY:rfc1034identifier)")
-	{
-		bundleID := `auto_provision.$(PRODUCT_NAME:rfc1034identifier).suffix.$(PRODUCT_NAME:rfc1034identifier)`
-		buildSettings := serialized.Object{
-			"PRODUCT_NAME": "ios-simple-objc",
-		}
-		resolved, err := expand(bundleID, buildSettings)
-		require.NoError(t, err)
-		require.Equal(t, "auto_provision.ios-simple-objc.suffix.ios-simple-objc", resolved)
-	}
-
-	t.Log("resolves bundle id in format: prefix.$(ENV_KEY:rfc1034identifier).suffix")
-	{
-		bundleID := `auto_provision.$(PRODUCT_NAME:rfc1034identifier).suffix`
-		buildSettings := serialized.Object{
-			"PRODUCT_NAME": "ios-simple-objc",
-		}
-		resolved, err := expand(bundleID, buildSettings)
-		require.NoError(t, err)
-		require.Equal(t, "auto_provision.ios-simple-objc.suffix", resolved)
-	}
-
-	// Simple env
-	t.Log("resolves bundle id in format: prefix.$ENV_KEY")
-	{
-		bundleID := `auto_provision.$PRODUCT_NAME`
-		buildSettings := serialized.Object{
-			"PRODUCT_NAME": "ios-simple-objc",
-		}
-		resolved, err := expand(bundleID, buildSettings)
-		require.NoError(t, err)
-		require.Equal(t, "auto_provision.ios-simple-objc", resolved)
-	}
-
-	t.Log("resolves bundle id in format: prefix.$ENV_KEYsuffix")
-	{
-		bundleID := `auto_provision.$PRODUCT_NAMEsuffix`
-		buildSettings := serialized.Object{
-			"PRODUCT_NAME": "ios-simple-objc",
-		}
-		resolved, err := expand(bundleID, buildSettings)
-		require.NoError(t, err)
-		require.Equal(t, "auto_provision.ios-simple-objcsuffix", resolved)
-	}
-}
-
-func TestExpandComplexEnv(t *testing.T) {
-	t.Log("resolves bundle id in format: prefix.{text.${ENV_KEY}.text}")
-	{
-		bundleID := `prefix.{text.${PRODUCT_NAME}.text}`
-		buildSettings := serialized.Object{
-			"PRODUCT_NAME": "ios-simple-objc",
-		}
-		resolved, err := expandComplexEnv(bundleID, buildSettings)
-		require.NoError(t, err)
-		require.Equal(t, "prefix.{text.ios-simple-objc.text}", resolved)
-	}
-
-	t.Log("resolves bundle id in format: prefix.$(ENV_KEY:rfc1034identifier).suffix.$(ENV_KEY:rfc1034identifier)")
-	{
-		bundleID := `auto_provision.$(PRODUCT_NAME:rfc1034identifier).suffix.$(PRODUCT_NAME:rfc1034identifier)`
-		buildSettings := serialized.Object{
-			"PRODUCT_NAME": "ios-simple-objc",
-		}
-		resolved, err := expandComplexEnv(bundleID, buildSettings)
-		require.NoError(t, err)
-		require.Equal(t, "auto_provision.ios-simple-objc.suffix.ios-simple-objc", resolved)
-	}
-
-	t.Log("resolves bundle id in format: prefix.$(ENV_KEY:rfc1034identifier).suffix")
-	{
-		bundleID := `auto_provision.$(PRODUCT_NAME:rfc1034identifier).suffix`
-		buildSettings := serialized.Object{
-			"PRODUCT_NAME": "ios-simple-objc",
-		}
-		resolved, err := expandComplexEnv(bundleID, buildSettings)
-		require.NoError(t, err)
-		require.Equal(t, "auto_provision.ios-simple-objc.suffix", resolved)
-	}
-}
-
-func TestExpandSimpleEnv(t *testing.T) {
-	t.Log("resolves bundle id in format: prefix.$ENV_KEY")
-	{
-		bundleID := `auto_provision.$PRODUCT_NAME`
-		buildSettings := serialized.Object{
-			"PRODUCT_NAME": "ios-simple-objc",
-		}
-		resolved, err := expandSimpleEnv(bundleID, buildSettings)
-		require.NoError(t, err)
-		require.Equal(t, "auto_provision.ios-simple-objc", resolved)
-	}
-
-	t.Log("resolves bundle id in format: prefix.$ENV_KEYsuffix")
-	{
-		bundleID := `auto_provision.$PRODUCT_NAMEsuffix`
-		buildSettings := serialized.Object{
-			"PRODUCT_NAME": "ios-simple-objc",
-		}
-		resolved, err := expandSimpleEnv(bundleID, buildSettings)
-		require.NoError(t, err)
-		require.Equal(t, "auto_provision.ios-simple-objcsuffix", resolved)
-	}
-}
-
 func TestTargets(t *testing.T) {
 	dir := testhelper.GitCloneIntoTmpDir(t, "https://github.com/bitrise-io/xcode-project-test.git")
 	project, err := Open(filepath.Join(dir, "Group/SubProject/SubProject.xcodeproj"))
@@ -790,6 +703,7 @@ func TestXcodeProj_perObjectModify(t *testing.T) {
 	tests := []struct {
 		name                  string
 		projContent           string
+		projectName           string
 		configuration, target string
 		want                  []byte
 		wantErr               bool
@@ -797,6 +711,7 @@ func TestXcodeProj_perObjectModify(t *testing.T) {
 		{
 			name:          "No target attributes",
 			projContent:   pbxprojWithouthTargetAttributes,
+			projectName:   "Target",
 			configuration: "Debug",
 			target:        "TargetWithouthTargetAttributes",
 			want:          []byte(pbxprojWTAafterPerObjectModify),
@@ -804,6 +719,7 @@ func TestXcodeProj_perObjectModify(t *testing.T) {
 		{
 			name:          "Will change 2 objects (as Target attributes is included in the project)",
 			projContent:   testhelper.XcodeProjectTest,
+			projectName:   "XcodeProj",
 			configuration: "Debug",
 			target:        "XcodeProj",
 			want:          []byte(testhelper.XcodeProjectTestChanged),
@@ -813,6 +729,7 @@ func TestXcodeProj_perObjectModify(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			proj, err := parsePBXProjContent([]byte(tt.projContent))
 			require.NoError(t, err)
+			proj.Name = tt.projectName
 
 			team := "ABCD1234"
 			signingIdentity := "Apple Development: John Doe (ASDF1234)"
@@ -831,6 +748,75 @@ func TestXcodeProj_perObjectModify(t *testing.T) {
 	}
 }
 
+func TestXcodeProj_perObjectModify_AddAndRemoveObjects(t *testing.T) {
+	proj, err := parsePBXProjContent([]byte(testhelper.XcodeProjectTest))
+	require.NoError(t, err)
+	proj.Name = "XcodeProj"
+
+	objects, err := proj.RawProj.Object("objects")
+	require.NoError(t, err)
+
+	// remove the TodayExtension dependency of the XcodeProj target
+	target, err := objects.Object("7D5B35FB20E28EE80022BAE6")
+	require.NoError(t, err)
+	target["dependencies"] = []interface{}{}
+	delete(objects, "7D03431920F4BB070050B6A6")
+
+	// existing section
+	objects["7D03431700000000000000AA"] = map[string]interface{}{
+		"isa":     "PBXBuildFile",
+		"fileRef": "7D03431420F4BB070050B6A6",
+	}
+	// new section between existing sections
+	objects["7D03431800000000000000AA"] = map[string]interface{}{
+		"isa":         "PBXShellScriptBuildPhase",
+		"shellPath":   "/bin/sh",
+		"shellScript": "swiftlint",
+	}
+	// new section after the last section
+	objects["7D03431900000000000000AA"] = map[string]interface{}{
+		"isa":         "XCSwiftPackageProductDependency",
+		"productName": "Alamofire",
+	}
+
+	got, err := proj.perObjectModify()
+	require.NoError(t, err)
+
+	reopened, err := parsePBXProjContent(got)
+	require.NoError(t, err)
+	reopenedObjects, err := reopened.RawProj.Object("objects")
+	require.NoError(t, err)
+	require.Equal(t, objects, reopenedObjects)
+
+	want := testhelper.XcodeProjectTest
+	for _, r := range []struct{ old, new string }{
+		{
+			old: "\t\t7D03431A20F4BB070050B6A6 /* TodayExtension.appex in Embed App Extensions */ = ",
+			new: "\t\t7D03431700000000000000AA /* MainInterface.storyboard */ = {isa = PBXBuildFile; fileRef = 7D03431420F4BB070050B6A6 /* MainInterface.storyboard */; };\n\t\t7D03431A20F4BB070050B6A6 /* TodayExtension.appex in Embed App Extensions */ = ",
+		},
+		{
+			old: "\t\t\t\t7D03431920F4BB070050B6A6 /* PBXTargetDependency */,\n",
+			new: "",
+		},
+		{
+			old: "\t\t7D03431920F4BB070050B6A6 /* PBXTargetDependency */ = {\n\t\t\tisa = PBXTargetDependency;\n\t\t\ttarget = 7D03430C20F4BB070050B6A6 /* TodayExtension */;\n\t\t\ttargetProxy = 7D03431820F4BB070050B6A6 /* PBXContainerItemProxy */;\n\t\t};\n",
+			new: "",
+		},
+		{
+			old: "/* Begin PBXSourcesBuildPhase section */",
+			new: "/* Begin PBXShellScriptBuildPhase section */\n\t\t7D03431800000000000000AA /* ShellScript */ = {\n\t\t\tisa = PBXShellScriptBuildPhase;\n\t\t\tshellPath = /bin/sh;\n\t\t\tshellScript = swiftlint;\n\t\t};\n/* End PBXShellScriptBuildPhase section */\n\n/* Begin PBXSourcesBuildPhase section */",
+		},
+		{
+			old: "/* End XCConfigurationList section */\n",
+			new: "/* End XCConfigurationList section */\n\n/* Begin XCSwiftPackageProductDependency section */\n\t\t7D03431900000000000000AA /* Alamofire */ = {\n\t\t\tisa = XCSwiftPackageProductDependency;\n\t\t\tproductName = Alamofire;\n\t\t};\n/* End XCSwiftPackageProductDependency section */\n",
+		},
+	} {
+		require.Equal(t, 1, strings.Count(want, r.old), r.old)
+		want = strings.Replace(want, r.old, r.new, 1)
+	}
+	require.Equal(t, want, string(got))
+}
+
 func Test_removeCustomInfo(t *testing.T) {
 	tests := []struct {
 		o    interface{}
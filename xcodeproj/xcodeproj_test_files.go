@@ -386,30 +386,30 @@ const pbxprojWTAafterPerObjectModify = `// !$*UTF8*$!
 			name = Debug;
 		};
 		13BD633B256BE7BF00F72361 /* Debug */ = {
-	buildSettings = {
-		"ASSETCATALOG_COMPILER_APPICON_NAME" = AppIcon;
-		"ASSETCATALOG_COMPILER_GLOBAL_ACCENT_COLOR_NAME" = AccentColor;
-		"CODE_SIGN_IDENTITY" = "Apple Development: John Doe (ASDF1234)";
-		"CODE_SIGN_STYLE" = Manual;
-		"DEVELOPMENT_ASSET_PATHS" = "\"Target/Preview Content\"";
-		"DEVELOPMENT_TEAM" = ABCD1234;
-		"ENABLE_PREVIEWS" = YES;
-		"INFOPLIST_FILE" = "Target copy-Info.plist";
-		"IPHONEOS_DEPLOYMENT_TARGET" = "14.0";
-		"LD_RUNPATH_SEARCH_PATHS" = (
-			"$(inherited)",
-			"@executable_path/Frameworks",
-		);
-		"PRODUCT_BUNDLE_IDENTIFIER" = "io.bitrise.target.Target";
-		"PRODUCT_NAME" = "$(TARGET_NAME)";
-		"PROVISIONING_PROFILE" = "asdf56b6-e75a-4f86-bf25-101bfc2fasdf";
-		"PROVISIONING_PROFILE_SPECIFIER" = "";
-		"SWIFT_VERSION" = "5.0";
-		"TARGETED_DEVICE_FAMILY" = "1,2";
-	};
-	isa = XCBuildConfiguration;
-	name = Debug;
-};
+			isa = XCBuildConfiguration;
+			buildSettings = {
+				ASSETCATALOG_COMPILER_APPICON_NAME = AppIcon;
+				ASSETCATALOG_COMPILER_GLOBAL_ACCENT_COLOR_NAME = AccentColor;
+				CODE_SIGN_IDENTITY = "Apple Development: John Doe (ASDF1234)";
+				CODE_SIGN_STYLE = Manual;
+				DEVELOPMENT_ASSET_PATHS = "\"Target/Preview Content\"";
+				DEVELOPMENT_TEAM = ABCD1234;
+				ENABLE_PREVIEWS = YES;
+				INFOPLIST_FILE = "Target copy-Info.plist";
+				IPHONEOS_DEPLOYMENT_TARGET = 14.0;
+				LD_RUNPATH_SEARCH_PATHS = (
+					"$(inherited)",
+					"@executable_path/Frameworks",
+				);
+				PRODUCT_BUNDLE_IDENTIFIER = io.bitrise.target.Target;
+				PRODUCT_NAME = "$(TARGET_NAME)";
+				PROVISIONING_PROFILE = "asdf56b6-e75a-4f86-bf25-101bfc2fasdf";
+				PROVISIONING_PROFILE_SPECIFIER = "";
+				SWIFT_VERSION = 5.0;
+				TARGETED_DEVICE_FAMILY = "1,2";
+			};
+			name = Debug;
+		};
 /* End XCBuildConfiguration section */
 
 /* Begin XCConfigurationList section */
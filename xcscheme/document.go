@@ -0,0 +1,230 @@
+package xcscheme
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/bitrise-io/go-utils/fileutil"
+)
+
+// Attribute ...
+type Attribute struct {
+	Name  string
+	Value string
+}
+
+// Element is an element of a scheme file.
+// Unlike Scheme, it keeps every element and attribute, including the ones not modelled by Scheme,
+// so a scheme can be edited without losing the settings written by newer Xcode versions.
+type Element struct {
+	Name       string
+	Attributes []Attribute
+	Children   []*Element
+	// Text is the character data of the element without the surrounding whitespace, Xcode does not write character data.
+	Text string
+}
+
+// NewElement ...
+func NewElement(name string, attributes ...Attribute) *Element {
+	return &Element{Name: name, Attributes: attributes}
+}
+
+// Attribute returns the value of the attribute and whether the element has the attribute.
+func (e *Element) Attribute(name string) (string, bool) {
+	for _, attribute := range e.Attributes {
+		if attribute.Name == name {
+			return attribute.Value, true
+		}
+	}
+	return "", false
+}
+
+// SetAttribute sets the value of the attribute, a missing attribute is appended to the attributes.
+func (e *Element) SetAttribute(name, value string) {
+	for i, attribute := range e.Attributes {
+		if attribute.Name == name {
+			e.Attributes[i].Value = value
+			return
+		}
+	}
+	e.Attributes = append(e.Attributes, Attribute{Name: name, Value: value})
+}
+
+// RemoveAttribute removes the attribute, returns false if the element has no such attribute.
+func (e *Element) RemoveAttribute(name string) bool {
+	for i, attribute := range e.Attributes {
+		if attribute.Name == name {
+			e.Attributes = append(e.Attributes[:i], e.Attributes[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// Child returns the first child element with the given name, nil if the element has no such child.
+func (e *Element) Child(name string) *Element {
+	for _, child := range e.Children {
+		if child.Name == name {
+			return child
+		}
+	}
+	return nil
+}
+
+// Find returns the descendant elements on the given path of element names,
+// like Find("TestAction", "Testables", "TestableReference").
+func (e *Element) Find(path ...string) []*Element {
+	elements := []*Element{e}
+	for _, name := range path {
+		var children []*Element
+		for _, element := range elements {
+			for _, child := range element.Children {
+				if child.Name == name {
+					children = append(children, child)
+				}
+			}
+		}
+		elements = children
+	}
+	return elements
+}
+
+// EnsureChild returns the first child element with the given name, the child is appended to the children if it is missing.
+func (e *Element) EnsureChild(name string) *Element {
+	if child := e.Child(name); child != nil {
+		return child
+	}
+	child := NewElement(name)
+	e.AppendChild(child)
+	return child
+}
+
+// AppendChild ...
+func (e *Element) AppendChild(children ...*Element) {
+	e.Children = append(e.Children, children...)
+}
+
+// RemoveChildren removes the child elements matching the filter, returns the number of removed elements.
+func (e *Element) RemoveChildren(filter func(child *Element) bool) int {
+	var kept []*Element
+	for _, child := range e.Children {
+		if !filter(child) {
+			kept = append(kept, child)
+		}
+	}
+	removed := len(e.Children) - len(kept)
+	e.Children = kept
+	return removed
+}
+
+// Document is an editable scheme file.
+type Document struct {
+	Root *Element
+}
+
+// ParseDocument parses the content of a scheme file.
+// Comments are not kept and the whitespace between the elements is replaced by Xcode's indentation when writing the document.
+func ParseDocument(content []byte) (*Document, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	var root *Element
+	var stack []*Element
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse scheme: %s", err)
+		}
+
+		switch token := token.(type) {
+		case xml.StartElement:
+			element := NewElement(token.Name.Local)
+			for _, attr := range token.Attr {
+				element.Attributes = append(element.Attributes, Attribute{Name: attr.Name.Local, Value: attr.Value})
+			}
+
+			if len(stack) == 0 {
+				if root != nil {
+					return nil, fmt.Errorf("failed to parse scheme: multiple root elements")
+				}
+				root = element
+			} else {
+				stack[len(stack)-1].AppendChild(element)
+			}
+			stack = append(stack, element)
+		case xml.EndElement:
+			stack = stack[:len(stack)-1]
+		case xml.CharData:
+			if text := strings.TrimSpace(string(token)); text != "" && len(stack) > 0 {
+				stack[len(stack)-1].Text += text
+			}
+		}
+	}
+
+	if root == nil {
+		return nil, fmt.Errorf("failed to parse scheme: no root element")
+	}
+
+	return &Document{Root: root}, nil
+}
+
+// OpenDocument reads and parses the scheme file.
+func OpenDocument(pth string) (*Document, error) {
+	b, err := fileutil.ReadBytesFromFile(pth)
+	if err != nil {
+		return nil, err
+	}
+	return ParseDocument(b)
+}
+
+// Find returns the elements on the given path of element names, starting with the root's children.
+func (d Document) Find(path ...string) []*Element {
+	return d.Root.Find(path...)
+}
+
+// Marshal returns the document in the format Xcode writes scheme files:
+// every attribute is on its own line and every element has an end tag.
+func (d Document) Marshal() []byte {
+	var b bytes.Buffer
+	b.WriteString(xml.Header)
+	writeElement(&b, d.Root, 0)
+	return b.Bytes()
+}
+
+func writeElement(b *bytes.Buffer, e *Element, depth int) {
+	indent := strings.Repeat("   ", depth)
+
+	b.WriteString(indent + "<" + e.Name)
+	for _, attribute := range e.Attributes {
+		b.WriteString("\n" + indent + "   " + attribute.Name + ` = "` + escapeAttribute(attribute.Value) + `"`)
+	}
+	b.WriteString(">\n")
+
+	if e.Text != "" {
+		b.WriteString(indent + "   " + escapeAttribute(e.Text) + "\n")
+	}
+	for _, child := range e.Children {
+		writeElement(b, child, depth+1)
+	}
+
+	b.WriteString(indent + "</" + e.Name + ">\n")
+}
+
+// Write writes the document to the given path.
+func (d Document) Write(pth string) error {
+	return fileutil.WriteBytesToFile(pth, d.Marshal())
+}
+
+// Scheme returns the model of the document, its Name and Path are not set.
+func (d Document) Scheme() (Scheme, error) {
+	var scheme Scheme
+	if err := xml.Unmarshal(d.Marshal(), &scheme); err != nil {
+		return Scheme{}, fmt.Errorf("failed to unmarshal scheme: %s", err)
+	}
+	return scheme, nil
+}
@@ -0,0 +1,103 @@
+package xcscheme
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDocument(t *testing.T) {
+	t.Log("keeps Xcode's format")
+	{
+		for _, content := range []string{schemeContent, actionsSchemeContent} {
+			document, err := ParseDocument([]byte(content))
+			require.NoError(t, err)
+			require.Equal(t, content, string(document.Marshal()))
+		}
+	}
+
+	t.Log("keeps the unknown elements and attributes")
+	{
+		content := strings.Replace(schemeContent, `   <AnalyzeAction
+      buildConfiguration = "Debug">
+   </AnalyzeAction>`, `   <AnalyzeAction
+      buildConfiguration = "Debug"
+      futureAttribute = "1">
+      <FutureElement
+         value = "&lt;a&gt; &amp; &apos;b&apos;">
+      </FutureElement>
+   </AnalyzeAction>`, 1)
+
+		document, err := ParseDocument([]byte(content))
+		require.NoError(t, err)
+
+		analyzeAction := document.Find("AnalyzeAction")[0]
+		value, ok := analyzeAction.Attribute("futureAttribute")
+		require.True(t, ok)
+		require.Equal(t, "1", value)
+		value, ok = analyzeAction.Child("FutureElement").Attribute("value")
+		require.True(t, ok)
+		require.Equal(t, "<a> & 'b'", value)
+
+		analyzeAction.SetAttribute("buildConfiguration", "Release")
+		require.Equal(t, strings.Replace(content, `      buildConfiguration = "Debug"
+      futureAttribute`, `      buildConfiguration = "Release"
+      futureAttribute`, 1), string(document.Marshal()))
+	}
+
+	t.Log("not a scheme")
+	{
+		_, err := ParseDocument([]byte(`<?xml version="1.0" encoding="UTF-8"?>`))
+		require.EqualError(t, err, "failed to parse scheme: no root element")
+	}
+}
+
+func TestDocument_edit(t *testing.T) {
+	pth := testhelper.CreateTmpFile(t, "ios-simple-objc.xcscheme", schemeContent)
+	document, err := OpenDocument(pth)
+	require.NoError(t, err)
+
+	testAction := document.Find("TestAction")[0]
+	testAction.SetAttribute("codeCoverageEnabled", "YES")
+	require.True(t, testAction.RemoveAttribute("selectedLauncherIdentifier"))
+	require.False(t, testAction.RemoveAttribute("selectedLauncherIdentifier"))
+
+	require.Equal(t, 1, testAction.EnsureChild("Testables").RemoveChildren(func(child *Element) bool {
+		skipped, _ := child.Attribute("skipped")
+		return skipped == "YES"
+	}))
+
+	variable := NewElement("EnvironmentVariable",
+		Attribute{Name: "key", Value: "CI"},
+		Attribute{Name: "value", Value: "true"},
+		Attribute{Name: "isEnabled", Value: "YES"},
+	)
+	testAction.EnsureChild("EnvironmentVariables").AppendChild(variable)
+
+	require.NoError(t, document.Write(pth))
+	content, err := fileutil.ReadStringFromFile(pth)
+	require.NoError(t, err)
+	require.Contains(t, content, `      shouldUseLaunchSchemeArgsEnv = "YES"
+      codeCoverageEnabled = "YES">
+`)
+	require.Contains(t, content, `      <EnvironmentVariables>
+         <EnvironmentVariable
+            key = "CI"
+            value = "true"
+            isEnabled = "YES">
+         </EnvironmentVariable>
+      </EnvironmentVariables>
+   </TestAction>
+`)
+
+	scheme, err := Open(pth)
+	require.NoError(t, err)
+	require.Equal(t, "YES", scheme.TestAction.CodeCoverageEnabled)
+	require.Equal(t, "", scheme.TestAction.SelectedLauncherIdentifier)
+	require.Equal(t, 1, len(scheme.TestAction.Testables))
+	require.Equal(t, []EnvironmentVariable{{Key: "CI", Value: "true", IsEnabled: "YES"}}, scheme.TestAction.EnvironmentVariables)
+	require.Equal(t, "ios-simple-objc", scheme.Name)
+}
@@ -0,0 +1,263 @@
+package xcscheme
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// buildableReferenceOwners are the elements, which exist only to wrap a BuildableReference,
+// they are removed together with the reference.
+var buildableReferenceOwners = map[string]bool{
+	"BuildActionEntry":         true,
+	"TestableReference":        true,
+	"BuildableProductRunnable": true,
+	"RemoteRunnable":           true,
+	"MacroExpansion":           true,
+}
+
+type byteRange struct {
+	start, end int
+}
+
+// RemoveBuildableReferences removes the BuildableReferences with the given BlueprintIdentifier from the scheme content,
+// together with their wrapping elements (BuildActionEntry, TestableReference, BuildableProductRunnable, ...).
+// The rest of the content is kept byte by byte. Returns false if the content does not reference the blueprint.
+func RemoveBuildableReferences(content []byte, blueprintIdentifier string) ([]byte, bool, error) {
+	type element struct {
+		name  string
+		start int
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	var stack []element
+	var removed []byteRange
+	removeParent := map[int]bool{}
+
+	for {
+		start := int(decoder.InputOffset())
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to parse scheme: %s", err)
+		}
+
+		switch token := token.(type) {
+		case xml.StartElement:
+			stack = append(stack, element{name: token.Name.Local, start: start})
+
+			if token.Name.Local != "BuildableReference" || attributeValue(token, "BlueprintIdentifier") != blueprintIdentifier {
+				continue
+			}
+
+			if len(stack) > 1 && buildableReferenceOwners[stack[len(stack)-2].name] {
+				removeParent[len(stack)-2] = true
+			} else {
+				removeParent[len(stack)-1] = true
+			}
+		case xml.EndElement:
+			if len(stack) == 0 {
+				return nil, false, fmt.Errorf("failed to parse scheme: unexpected end element: %s", token.Name.Local)
+			}
+
+			depth := len(stack) - 1
+			if removeParent[depth] {
+				delete(removeParent, depth)
+				removed = append(removed, lineRange(content, stack[depth].start, int(decoder.InputOffset())))
+			}
+			stack = stack[:depth]
+		}
+	}
+
+	if len(removed) == 0 {
+		return content, false, nil
+	}
+
+	sort.Slice(removed, func(i, j int) bool { return removed[i].start < removed[j].start })
+
+	var b bytes.Buffer
+	last := 0
+	for _, r := range removed {
+		if r.start < last {
+			// nested in an already removed element
+			continue
+		}
+		b.Write(content[last:r.start])
+		last = r.end
+	}
+	b.Write(content[last:])
+
+	return b.Bytes(), true, nil
+}
+
+// lineRange extends the range to whole lines, if the range is on its own lines.
+func lineRange(content []byte, start, end int) byteRange {
+	lineStart := start
+	for lineStart > 0 && (content[lineStart-1] == ' ' || content[lineStart-1] == '\t') {
+		lineStart--
+	}
+	if lineStart > 0 && content[lineStart-1] != '\n' {
+		return byteRange{start: start, end: end}
+	}
+
+	lineEnd := end
+	for lineEnd < len(content) && (content[lineEnd] == ' ' || content[lineEnd] == '\t') {
+		lineEnd++
+	}
+	if lineEnd < len(content) && content[lineEnd] != '\n' {
+		return byteRange{start: start, end: end}
+	}
+	if lineEnd < len(content) {
+		lineEnd++
+	}
+
+	return byteRange{start: lineStart, end: lineEnd}
+}
+
+func attributeValue(element xml.StartElement, name string) string {
+	for _, attr := range element.Attr {
+		if attr.Name.Local == name {
+			return attr.Value
+		}
+	}
+	return ""
+}
+
+// AttributeChange is a changed attribute value.
+type AttributeChange struct {
+	Name string
+	Old  string
+	New  string
+}
+
+var attributePatternFormat = `(\s%s\s*=\s*")([^"]*)(")`
+
+// SetBuildableReferenceAttributes sets the given attributes of the BuildableReferences with the given BlueprintIdentifier.
+// Only the attribute values are replaced, the rest of the content is kept byte by byte.
+// Missing attributes are not added. Returns the changed attribute values.
+func SetBuildableReferenceAttributes(content []byte, blueprintIdentifier string, values map[string]string) ([]byte, []AttributeChange, error) {
+	return setAttributes(content, func(element xml.StartElement) map[string]string {
+		if element.Name.Local == "BuildableReference" && attributeValue(element, "BlueprintIdentifier") == blueprintIdentifier {
+			return values
+		}
+		return nil
+	})
+}
+
+// ReplaceBlueprintIdentifiers replaces the BlueprintIdentifier of the BuildableReferences
+// with the new identifier mapped to the old one, identifiers missing from the map are kept.
+// The blueprint identifiers are unique only within a project: if isContainer is not nil, only the BuildableReferences
+// are updated for which it returns true (called with the ReferencedContainer, like `container:App.xcodeproj`).
+// Only the attribute values are replaced, the rest of the content is kept byte by byte. Returns the changed attribute values.
+func ReplaceBlueprintIdentifiers(content []byte, identifiers map[string]string, isContainer func(referencedContainer string) bool) ([]byte, []AttributeChange, error) {
+	return setAttributes(content, func(element xml.StartElement) map[string]string {
+		if element.Name.Local != "BuildableReference" {
+			return nil
+		}
+		if isContainer != nil && !isContainer(attributeValue(element, "ReferencedContainer")) {
+			return nil
+		}
+		if newIdentifier, ok := identifiers[attributeValue(element, "BlueprintIdentifier")]; ok {
+			return map[string]string{"BlueprintIdentifier": newIdentifier}
+		}
+		return nil
+	})
+}
+
+// RenameBuildConfiguration replaces the buildConfiguration attribute of the scheme actions using the oldName configuration.
+// Only the attribute values are replaced, the rest of the content is kept byte by byte. Returns the changed attribute values.
+func RenameBuildConfiguration(content []byte, oldName, newName string) ([]byte, []AttributeChange, error) {
+	return setAttributes(content, func(element xml.StartElement) map[string]string {
+		if attributeValue(element, "buildConfiguration") == oldName {
+			return map[string]string{"buildConfiguration": newName}
+		}
+		return nil
+	})
+}
+
+// setAttributes sets the attribute values returned by valuesOf for the elements, valuesOf returns nil for the elements to keep.
+func setAttributes(content []byte, valuesOf func(element xml.StartElement) map[string]string) ([]byte, []AttributeChange, error) {
+	type tag struct {
+		byteRange
+		values map[string]string
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	var tags []tag
+
+	for {
+		start := int(decoder.InputOffset())
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse scheme: %s", err)
+		}
+
+		element, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		values := valuesOf(element)
+		if values == nil {
+			continue
+		}
+		tags = append(tags, tag{byteRange: byteRange{start: start, end: int(decoder.InputOffset())}, values: values})
+	}
+
+	var changes []AttributeChange
+	var b bytes.Buffer
+	last := 0
+	for _, tag := range tags {
+		b.Write(content[last:tag.start])
+
+		var names []string
+		for name := range tag.values {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		tagContent := content[tag.start:tag.end]
+		for _, name := range names {
+			value := tag.values[name]
+			pattern := regexp.MustCompile(fmt.Sprintf(attributePatternFormat, regexp.QuoteMeta(name)))
+			match := pattern.FindSubmatch(tagContent)
+			if match == nil || string(match[2]) == escapeAttribute(value) {
+				continue
+			}
+
+			changes = append(changes, AttributeChange{Name: name, Old: string(match[2]), New: value})
+			replacement := append(append(append([]byte{}, match[1]...), escapeAttribute(value)...), match[3]...)
+			tagContent = pattern.ReplaceAllLiteral(tagContent, replacement)
+		}
+
+		b.Write(tagContent)
+		last = tag.end
+	}
+	b.Write(content[last:])
+
+	return b.Bytes(), changes, nil
+}
+
+// attributeEscaper escapes the attribute values the same way as Xcode does.
+var attributeEscaper = strings.NewReplacer(
+	"&", "&amp;",
+	"<", "&lt;",
+	">", "&gt;",
+	`"`, "&quot;",
+	"'", "&apos;",
+	"\n", "&#10;",
+	"\r", "&#13;",
+	"\t", "&#9;",
+)
+
+func escapeAttribute(value string) string {
+	return attributeEscaper.Replace(value)
+}
@@ -0,0 +1,106 @@
+package xcscheme
+
+import (
+	"encoding/xml"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRemoveBuildableReferences(t *testing.T) {
+	t.Log("removes the test target")
+	{
+		content, removed, err := RemoveBuildableReferences([]byte(schemeContent), "BA3CBE9019F7A93900CED4D5")
+		require.NoError(t, err)
+		require.True(t, removed)
+
+		var scheme Scheme
+		require.NoError(t, xml.Unmarshal(content, &scheme))
+		require.Equal(t, 1, len(scheme.BuildAction.BuildActionEntries))
+		require.Equal(t, "BA3CBE7419F7A93800CED4D5", scheme.BuildAction.BuildActionEntries[0].BuildableReference.BlueprintIdentifier)
+		require.Equal(t, 1, len(scheme.TestAction.Testables))
+		require.Equal(t, "BA4CBE9019F7A93900CED4D5", scheme.TestAction.Testables[0].BuildableReference.BlueprintIdentifier)
+
+		require.NotContains(t, string(content), "ios-simple-objcTests.xctest")
+		require.Contains(t, string(content), "      </BuildActionEntries>\n")
+		require.Equal(t, strings.Count(schemeContent, "\n")-2*12, strings.Count(string(content), "\n"))
+	}
+
+	t.Log("removes the app target with its runnables and macro expansion")
+	{
+		content, removed, err := RemoveBuildableReferences([]byte(schemeContent), "BA3CBE7419F7A93800CED4D5")
+		require.NoError(t, err)
+		require.True(t, removed)
+		require.NotContains(t, string(content), "BA3CBE7419F7A93800CED4D5")
+		require.NotContains(t, string(content), "BuildableProductRunnable")
+		require.NotContains(t, string(content), "MacroExpansion")
+		require.Contains(t, string(content), "   <LaunchAction\n")
+	}
+
+	t.Log("not referenced blueprint")
+	{
+		content, removed, err := RemoveBuildableReferences([]byte(schemeContent), "NOT_EXISTING")
+		require.NoError(t, err)
+		require.False(t, removed)
+		require.Equal(t, schemeContent, string(content))
+	}
+}
+
+func TestSetBuildableReferenceAttributes(t *testing.T) {
+	content, changes, err := SetBuildableReferenceAttributes([]byte(schemeContent), "BA3CBE7419F7A93800CED4D5", map[string]string{
+		"BlueprintName": "white-label",
+		"BuildableName": "white-label.app",
+		"NotExisting":   "value",
+	})
+	require.NoError(t, err)
+	require.Equal(t, []AttributeChange{
+		{Name: "BlueprintName", Old: "ios-simple-objc", New: "white-label"},
+		{Name: "BuildableName", Old: "ios-simple-objc.app", New: "white-label.app"},
+	}, changes[:2])
+	require.Equal(t, 8, len(changes))
+
+	expected := strings.Replace(schemeContent, `BuildableName = "ios-simple-objc.app"`, `BuildableName = "white-label.app"`, -1)
+	expected = strings.Replace(expected, `BlueprintName = "ios-simple-objc"`, `BlueprintName = "white-label"`, -1)
+	require.Equal(t, expected, string(content))
+}
+
+func TestRenameBuildConfiguration(t *testing.T) {
+	content, changes, err := RenameBuildConfiguration([]byte(schemeContent), "Release", "Staging")
+	require.NoError(t, err)
+	require.Equal(t, []AttributeChange{
+		{Name: "buildConfiguration", Old: "Release", New: "Staging"},
+		{Name: "buildConfiguration", Old: "Release", New: "Staging"},
+	}, changes)
+	require.Equal(t, strings.Replace(schemeContent, `buildConfiguration = "Release"`, `buildConfiguration = "Staging"`, -1), string(content))
+}
+
+func TestReplaceBlueprintIdentifiers(t *testing.T) {
+	content, changes, err := ReplaceBlueprintIdentifiers([]byte(schemeContent), map[string]string{
+		"BA3CBE7419F7A93800CED4D5": "2A6F1C0E7B6D4E5F9A8B7C6D",
+		"BA3CBE9019F7A93900CED4D5": "BA3CBE7419F7A93800CED4D5",
+	}, nil)
+	require.NoError(t, err)
+	require.Equal(t, strings.Count(schemeContent, "BA3CBE7419F7A93800CED4D5")+strings.Count(schemeContent, "BA3CBE9019F7A93900CED4D5"), len(changes))
+
+	expected := strings.Replace(schemeContent, `"BA3CBE7419F7A93800CED4D5"`, `"2A6F1C0E7B6D4E5F9A8B7C6D"`, -1)
+	expected = strings.Replace(expected, `"BA3CBE9019F7A93900CED4D5"`, `"BA3CBE7419F7A93800CED4D5"`, -1)
+	require.Equal(t, expected, string(content))
+}
+
+func TestReplaceBlueprintIdentifiers_OfContainer(t *testing.T) {
+	identifiers := map[string]string{"BA3CBE7419F7A93800CED4D5": "2A6F1C0E7B6D4E5F9A8B7C6D"}
+
+	content, changes, err := ReplaceBlueprintIdentifiers([]byte(schemeContent), identifiers, func(referencedContainer string) bool {
+		return referencedContainer == "container:Other.xcodeproj"
+	})
+	require.NoError(t, err)
+	require.Equal(t, 0, len(changes))
+	require.Equal(t, schemeContent, string(content))
+
+	_, changes, err = ReplaceBlueprintIdentifiers([]byte(schemeContent), identifiers, func(referencedContainer string) bool {
+		return referencedContainer == "container:ios-simple-objc.xcodeproj"
+	})
+	require.NoError(t, err)
+	require.Equal(t, strings.Count(schemeContent, "BA3CBE7419F7A93800CED4D5"), len(changes))
+}
@@ -0,0 +1,151 @@
+package xcscheme
+
+// The scheme format versions written by Xcode 15.
+const (
+	DefaultLastUpgradeVersion = "1500"
+	DefaultVersion            = "1.7"
+)
+
+const (
+	lldbDebuggerIdentifier = "Xcode.DebuggerFoundation.Debugger.LLDB"
+	lldbLauncherIdentifier = "Xcode.DebuggerFoundation.Launcher.LLDB"
+)
+
+// SchemeTemplate describes the scheme generated by NewSchemeDocument.
+type SchemeTemplate struct {
+	// Target is built by every action, launched, profiled and archived.
+	Target BuildableReference
+	// Runnable is true if the target's product can be launched (like an app),
+	// the launch and profile actions of a not runnable target (like a framework) use it for macro expansion only.
+	Runnable bool
+	// TestTargets are the testables of the test action.
+	TestTargets []BuildableReference
+	// DebugConfiguration is used by the test, launch and analyze actions, defaults to Debug.
+	DebugConfiguration string
+	// ReleaseConfiguration is used by the profile and archive actions, defaults to Release.
+	ReleaseConfiguration string
+	// LastUpgradeVersion is the version of the Xcode writing the scheme, like 1500 for Xcode 15.0, defaults to DefaultLastUpgradeVersion.
+	LastUpgradeVersion string
+}
+
+// NewSchemeDocument returns a scheme with build, test, launch, profile, analyze and archive actions,
+// the same way as Xcode creates the scheme of a new target.
+func NewSchemeDocument(template SchemeTemplate) *Document {
+	debugConfiguration := template.DebugConfiguration
+	if debugConfiguration == "" {
+		debugConfiguration = "Debug"
+	}
+	releaseConfiguration := template.ReleaseConfiguration
+	if releaseConfiguration == "" {
+		releaseConfiguration = "Release"
+	}
+	lastUpgradeVersion := template.LastUpgradeVersion
+	if lastUpgradeVersion == "" {
+		lastUpgradeVersion = DefaultLastUpgradeVersion
+	}
+
+	root := NewElement("Scheme",
+		Attribute{Name: "LastUpgradeVersion", Value: lastUpgradeVersion},
+		Attribute{Name: "version", Value: DefaultVersion},
+	)
+
+	buildActionEntry := NewElement("BuildActionEntry",
+		Attribute{Name: "buildForTesting", Value: "YES"},
+		Attribute{Name: "buildForRunning", Value: "YES"},
+		Attribute{Name: "buildForProfiling", Value: "YES"},
+		Attribute{Name: "buildForArchiving", Value: "YES"},
+		Attribute{Name: "buildForAnalyzing", Value: "YES"},
+	)
+	buildActionEntry.AppendChild(buildableReferenceElement(template.Target))
+	buildActionEntries := NewElement("BuildActionEntries")
+	buildActionEntries.AppendChild(buildActionEntry)
+	buildAction := NewElement("BuildAction",
+		Attribute{Name: "parallelizeBuildables", Value: "YES"},
+		Attribute{Name: "buildImplicitDependencies", Value: "YES"},
+	)
+	buildAction.AppendChild(buildActionEntries)
+
+	testAction := NewElement("TestAction",
+		Attribute{Name: "buildConfiguration", Value: debugConfiguration},
+		Attribute{Name: "selectedDebuggerIdentifier", Value: lldbDebuggerIdentifier},
+		Attribute{Name: "selectedLauncherIdentifier", Value: lldbLauncherIdentifier},
+		Attribute{Name: "shouldUseLaunchSchemeArgsEnv", Value: "YES"},
+	)
+	if len(template.TestTargets) > 0 {
+		testables := NewElement("Testables")
+		for _, testTarget := range template.TestTargets {
+			testable := NewElement("TestableReference",
+				Attribute{Name: "skipped", Value: "NO"},
+				Attribute{Name: "parallelizable", Value: "YES"},
+			)
+			testable.AppendChild(buildableReferenceElement(testTarget))
+			testables.AppendChild(testable)
+		}
+		testAction.AppendChild(testables)
+	}
+
+	launchAction := NewElement("LaunchAction",
+		Attribute{Name: "buildConfiguration", Value: debugConfiguration},
+		Attribute{Name: "selectedDebuggerIdentifier", Value: lldbDebuggerIdentifier},
+		Attribute{Name: "selectedLauncherIdentifier", Value: lldbLauncherIdentifier},
+		Attribute{Name: "launchStyle", Value: string(AutomaticallyLaunchStyle)},
+		Attribute{Name: "useCustomWorkingDirectory", Value: "NO"},
+		Attribute{Name: "ignoresPersistentStateOnLaunch", Value: "NO"},
+		Attribute{Name: "debugDocumentVersioning", Value: "YES"},
+		Attribute{Name: "debugServiceExtension", Value: "internal"},
+		Attribute{Name: "allowLocationSimulation", Value: "YES"},
+	)
+	launchAction.AppendChild(runnableElement(template.Target, template.Runnable))
+
+	profileAction := NewElement("ProfileAction",
+		Attribute{Name: "buildConfiguration", Value: releaseConfiguration},
+		Attribute{Name: "shouldUseLaunchSchemeArgsEnv", Value: "YES"},
+		Attribute{Name: "savedToolIdentifier", Value: ""},
+		Attribute{Name: "useCustomWorkingDirectory", Value: "NO"},
+		Attribute{Name: "debugDocumentVersioning", Value: "YES"},
+	)
+	profileAction.AppendChild(runnableElement(template.Target, template.Runnable))
+
+	analyzeAction := NewElement("AnalyzeAction",
+		Attribute{Name: "buildConfiguration", Value: debugConfiguration},
+	)
+
+	archiveAction := NewElement("ArchiveAction",
+		Attribute{Name: "buildConfiguration", Value: releaseConfiguration},
+		Attribute{Name: "revealArchiveInOrganizer", Value: "YES"},
+	)
+
+	root.AppendChild(buildAction, testAction, launchAction, profileAction, analyzeAction, archiveAction)
+
+	return &Document{Root: root}
+}
+
+// runnableElement returns the BuildableProductRunnable of a runnable target, otherwise the MacroExpansion.
+func runnableElement(target BuildableReference, runnable bool) *Element {
+	if !runnable {
+		macroExpansion := NewElement("MacroExpansion")
+		macroExpansion.AppendChild(buildableReferenceElement(target))
+		return macroExpansion
+	}
+
+	buildableProductRunnable := NewElement("BuildableProductRunnable",
+		Attribute{Name: "runnableDebuggingMode", Value: "0"},
+	)
+	buildableProductRunnable.AppendChild(buildableReferenceElement(target))
+	return buildableProductRunnable
+}
+
+func buildableReferenceElement(reference BuildableReference) *Element {
+	buildableIdentifier := reference.BuildableIdentifier
+	if buildableIdentifier == "" {
+		buildableIdentifier = "primary"
+	}
+
+	return NewElement("BuildableReference",
+		Attribute{Name: "BuildableIdentifier", Value: buildableIdentifier},
+		Attribute{Name: "BlueprintIdentifier", Value: reference.BlueprintIdentifier},
+		Attribute{Name: "BuildableName", Value: reference.BuildableName},
+		Attribute{Name: "BlueprintName", Value: reference.BlueprintName},
+		Attribute{Name: "ReferencedContainer", Value: reference.ReferencedContainer},
+	)
+}
@@ -0,0 +1,132 @@
+package xcscheme
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSchemeDocument(t *testing.T) {
+	app := BuildableReference{
+		BlueprintIdentifier: "BA3CBE7419F7A93800CED4D5",
+		BuildableName:       "ios-simple-objc.app",
+		BlueprintName:       "ios-simple-objc",
+		ReferencedContainer: "container:ios-simple-objc.xcodeproj",
+	}
+	tests := BuildableReference{
+		BlueprintIdentifier: "BA3CBE9019F7A93900CED4D5",
+		BuildableName:       "ios-simple-objcTests.xctest",
+		BlueprintName:       "ios-simple-objcTests",
+		ReferencedContainer: "container:ios-simple-objc.xcodeproj",
+	}
+
+	document := NewSchemeDocument(SchemeTemplate{Target: app, Runnable: true, TestTargets: []BuildableReference{tests}})
+	require.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<Scheme
+   LastUpgradeVersion = "1500"
+   version = "1.7">
+   <BuildAction
+      parallelizeBuildables = "YES"
+      buildImplicitDependencies = "YES">
+      <BuildActionEntries>
+         <BuildActionEntry
+            buildForTesting = "YES"
+            buildForRunning = "YES"
+            buildForProfiling = "YES"
+            buildForArchiving = "YES"
+            buildForAnalyzing = "YES">
+            <BuildableReference
+               BuildableIdentifier = "primary"
+               BlueprintIdentifier = "BA3CBE7419F7A93800CED4D5"
+               BuildableName = "ios-simple-objc.app"
+               BlueprintName = "ios-simple-objc"
+               ReferencedContainer = "container:ios-simple-objc.xcodeproj">
+            </BuildableReference>
+         </BuildActionEntry>
+      </BuildActionEntries>
+   </BuildAction>
+   <TestAction
+      buildConfiguration = "Debug"
+      selectedDebuggerIdentifier = "Xcode.DebuggerFoundation.Debugger.LLDB"
+      selectedLauncherIdentifier = "Xcode.DebuggerFoundation.Launcher.LLDB"
+      shouldUseLaunchSchemeArgsEnv = "YES">
+      <Testables>
+         <TestableReference
+            skipped = "NO"
+            parallelizable = "YES">
+            <BuildableReference
+               BuildableIdentifier = "primary"
+               BlueprintIdentifier = "BA3CBE9019F7A93900CED4D5"
+               BuildableName = "ios-simple-objcTests.xctest"
+               BlueprintName = "ios-simple-objcTests"
+               ReferencedContainer = "container:ios-simple-objc.xcodeproj">
+            </BuildableReference>
+         </TestableReference>
+      </Testables>
+   </TestAction>
+   <LaunchAction
+      buildConfiguration = "Debug"
+      selectedDebuggerIdentifier = "Xcode.DebuggerFoundation.Debugger.LLDB"
+      selectedLauncherIdentifier = "Xcode.DebuggerFoundation.Launcher.LLDB"
+      launchStyle = "0"
+      useCustomWorkingDirectory = "NO"
+      ignoresPersistentStateOnLaunch = "NO"
+      debugDocumentVersioning = "YES"
+      debugServiceExtension = "internal"
+      allowLocationSimulation = "YES">
+      <BuildableProductRunnable
+         runnableDebuggingMode = "0">
+         <BuildableReference
+            BuildableIdentifier = "primary"
+            BlueprintIdentifier = "BA3CBE7419F7A93800CED4D5"
+            BuildableName = "ios-simple-objc.app"
+            BlueprintName = "ios-simple-objc"
+            ReferencedContainer = "container:ios-simple-objc.xcodeproj">
+         </BuildableReference>
+      </BuildableProductRunnable>
+   </LaunchAction>
+   <ProfileAction
+      buildConfiguration = "Release"
+      shouldUseLaunchSchemeArgsEnv = "YES"
+      savedToolIdentifier = ""
+      useCustomWorkingDirectory = "NO"
+      debugDocumentVersioning = "YES">
+      <BuildableProductRunnable
+         runnableDebuggingMode = "0">
+         <BuildableReference
+            BuildableIdentifier = "primary"
+            BlueprintIdentifier = "BA3CBE7419F7A93800CED4D5"
+            BuildableName = "ios-simple-objc.app"
+            BlueprintName = "ios-simple-objc"
+            ReferencedContainer = "container:ios-simple-objc.xcodeproj">
+         </BuildableReference>
+      </BuildableProductRunnable>
+   </ProfileAction>
+   <AnalyzeAction
+      buildConfiguration = "Debug">
+   </AnalyzeAction>
+   <ArchiveAction
+      buildConfiguration = "Release"
+      revealArchiveInOrganizer = "YES">
+   </ArchiveAction>
+</Scheme>
+`, string(document.Marshal()))
+
+	t.Log("not runnable target")
+	{
+		framework := BuildableReference{BlueprintIdentifier: "BA3CBE7419F7A93800CED4D6", BuildableName: "Core.framework", BlueprintName: "Core", ReferencedContainer: "container:Core.xcodeproj"}
+		document := NewSchemeDocument(SchemeTemplate{Target: framework, DebugConfiguration: "Development", ReleaseConfiguration: "Production", LastUpgradeVersion: "1430"})
+
+		scheme, err := document.Scheme()
+		require.NoError(t, err)
+		require.Equal(t, "1430", scheme.LastUpgradeVersion)
+		require.Equal(t, "Development", scheme.LaunchAction.BuildConfiguration)
+		require.Equal(t, "Production", scheme.ArchiveAction.BuildConfiguration)
+		require.Nil(t, scheme.LaunchAction.BuildableProductRunnable)
+		require.Equal(t, framework.BlueprintIdentifier, scheme.LaunchAction.MacroExpansion.BuildableReference.BlueprintIdentifier)
+		require.Equal(t, "primary", scheme.ProfileAction.MacroExpansion.BuildableReference.BuildableIdentifier)
+		require.Equal(t, 0, len(scheme.TestAction.Testables))
+		require.False(t, strings.Contains(string(document.Marshal()), "<Testables>"))
+	}
+}
@@ -0,0 +1,86 @@
+package xcscheme
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+)
+
+// Share converts the user scheme into a shared scheme: moves the scheme file from the user's xcschemes directory
+// into the xcshareddata/xcschemes directory of its project or workspace,
+// and updates the scheme's SchemeUserState key in the user's xcschememanagement.plist, if the file exists.
+// The xcschememanagement.plist is updated first, it is restored if the scheme can not be moved.
+// Returns the shared scheme.
+func (s Scheme) Share() (Scheme, error) {
+	if s.IsShared {
+		return Scheme{}, fmt.Errorf("scheme is already shared: %s", s.Path)
+	}
+	if s.User == "" {
+		return Scheme{}, fmt.Errorf("not a user scheme: %s", s.Path)
+	}
+
+	// <container>/xcuserdata/<user>.xcuserdatad/xcschemes/<name>.xcscheme
+	containerPath := filepath.Dir(filepath.Dir(filepath.Dir(filepath.Dir(s.Path))))
+	sharedPth := filepath.Join(containerPath, "xcshareddata", "xcschemes", filepath.Base(s.Path))
+	if exist, err := pathutil.IsPathExists(sharedPth); err != nil {
+		return Scheme{}, err
+	} else if exist {
+		return Scheme{}, fmt.Errorf("shared scheme already exists: %s", sharedPth)
+	}
+
+	managementPth := SchemeManagementPath(containerPath, s.User)
+	originalManagement, err := s.shareInSchemeManagement(managementPth)
+	if err != nil {
+		return Scheme{}, err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(sharedPth), 0755); err == nil {
+		err = os.Rename(s.Path, sharedPth)
+	}
+	if err != nil {
+		if originalManagement != nil {
+			if restoreErr := fileutil.WriteBytesToFile(managementPth, originalManagement); restoreErr != nil {
+				return Scheme{}, fmt.Errorf("failed to move scheme (%s): %s, and failed to restore scheme management (%s): %s", s.Path, err, managementPth, restoreErr)
+			}
+		}
+		return Scheme{}, fmt.Errorf("failed to move scheme (%s): %s", s.Path, err)
+	}
+
+	return Open(sharedPth)
+}
+
+// shareInSchemeManagement replaces the scheme's user SchemeUserState key with the shared key in the xcschememanagement.plist,
+// if the file exists and has the key. Returns the original content of the file, nil if it was not changed.
+func (s Scheme) shareInSchemeManagement(managementPth string) ([]byte, error) {
+	if exist, err := pathutil.IsPathExists(managementPth); err != nil {
+		return nil, err
+	} else if !exist {
+		return nil, nil
+	}
+
+	original, err := fileutil.ReadBytesFromFile(managementPth)
+	if err != nil {
+		return nil, err
+	}
+
+	management, err := OpenSchemeManagement(managementPth)
+	if err != nil {
+		return nil, err
+	}
+
+	userKey := SchemeUserStateKey(s.Name, false)
+	state, ok := management.SchemeUserState[userKey]
+	if !ok {
+		return nil, nil
+	}
+	delete(management.SchemeUserState, userKey)
+	management.SchemeUserState[SchemeUserStateKey(s.Name, true)] = state
+
+	if err := management.Write(managementPth); err != nil {
+		return nil, err
+	}
+	return original, nil
+}
@@ -0,0 +1,95 @@
+package xcscheme
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScheme_Share(t *testing.T) {
+	tmpDir, err := pathutil.NormalizedOSTempDirPath("__xcode-proj__")
+	require.NoError(t, err)
+	projectPth := filepath.Join(tmpDir, "App.xcodeproj")
+
+	sharedPth := filepath.Join(projectPth, "xcshareddata", "xcschemes", "App.xcscheme")
+	userSchemesDir := filepath.Join(projectPth, "xcuserdata", "john.xcuserdatad", "xcschemes")
+	for pth, content := range map[string]string{
+		sharedPth: schemeContent,
+		filepath.Join(userSchemesDir, "App-Staging.xcscheme"):   schemeContent,
+		filepath.Join(userSchemesDir, SchemeManagementFileName): schemeManagementContent,
+	} {
+		require.NoError(t, pathutil.EnsureDirExist(filepath.Dir(pth)))
+		require.NoError(t, fileutil.WriteStringToFile(pth, content))
+	}
+
+	schemes, err := FindSchemesIn(projectPth)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(schemes))
+	require.True(t, schemes[0].IsShared)
+	require.Equal(t, "", schemes[0].User)
+	require.False(t, schemes[1].IsShared)
+	require.Equal(t, "john", schemes[1].User)
+
+	_, err = schemes[0].Share()
+	require.EqualError(t, err, "scheme is already shared: "+sharedPth)
+
+	scheme, err := schemes[1].Share()
+	require.NoError(t, err)
+	require.True(t, scheme.IsShared)
+	require.Equal(t, "", scheme.User)
+	require.Equal(t, filepath.Join(projectPth, "xcshareddata", "xcschemes", "App-Staging.xcscheme"), scheme.Path)
+	require.Equal(t, "Release", scheme.ArchiveAction.BuildConfiguration)
+
+	exist, err := pathutil.IsPathExists(schemes[1].Path)
+	require.NoError(t, err)
+	require.False(t, exist)
+
+	management, err := OpenSchemeManagement(SchemeManagementPath(projectPth, "john"))
+	require.NoError(t, err)
+	_, ok := management.SchemeUserState[SchemeUserStateKey("App-Staging", false)]
+	require.False(t, ok)
+	state, ok := management.SchemeUserState[SchemeUserStateKey("App-Staging", true)]
+	require.True(t, ok)
+	require.Equal(t, 1, state.OrderHint)
+	require.False(t, state.Visible())
+
+	schemes, err = FindSchemesIn(projectPth)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(schemes))
+	require.True(t, schemes[0].IsShared && schemes[1].IsShared)
+}
+
+func TestScheme_Share_RestoresSchemeManagement(t *testing.T) {
+	tmpDir, err := pathutil.NormalizedOSTempDirPath("__xcode-proj__")
+	require.NoError(t, err)
+	projectPth := filepath.Join(tmpDir, "App.xcodeproj")
+
+	userSchemesDir := filepath.Join(projectPth, "xcuserdata", "john.xcuserdatad", "xcschemes")
+	for pth, content := range map[string]string{
+		// the shared schemes directory can not be created
+		filepath.Join(projectPth, "xcshareddata"):               "",
+		filepath.Join(userSchemesDir, "App-Staging.xcscheme"):   schemeContent,
+		filepath.Join(userSchemesDir, SchemeManagementFileName): schemeManagementContent,
+	} {
+		require.NoError(t, pathutil.EnsureDirExist(filepath.Dir(pth)))
+		require.NoError(t, fileutil.WriteStringToFile(pth, content))
+	}
+
+	schemes, err := FindSchemesIn(projectPth)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(schemes))
+
+	_, err = schemes[0].Share()
+	require.Error(t, err)
+
+	exist, err := pathutil.IsPathExists(schemes[0].Path)
+	require.NoError(t, err)
+	require.True(t, exist)
+
+	content, err := fileutil.ReadStringFromFile(SchemeManagementPath(projectPth, "john"))
+	require.NoError(t, err)
+	require.Equal(t, schemeManagementContent, content)
+}
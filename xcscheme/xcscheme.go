@@ -12,6 +12,7 @@ import (
 
 // BuildableReference ...
 type BuildableReference struct {
+	BuildableIdentifier string `xml:"BuildableIdentifier,attr"`
 	BlueprintIdentifier string `xml:"BlueprintIdentifier,attr"`
 	BlueprintName       string `xml:"BlueprintName,attr"`
 	BuildableName       string `xml:"BuildableName,attr"`
@@ -35,43 +36,234 @@ func (r BuildableReference) ReferencedContainerAbsPath(schemeContainerDir string
 	return pathutil.AbsPath(absPth)
 }
 
+// EnvironmentVariable ...
+type EnvironmentVariable struct {
+	Key       string `xml:"key,attr"`
+	Value     string `xml:"value,attr"`
+	IsEnabled string `xml:"isEnabled,attr"`
+}
+
+// CommandLineArgument ...
+type CommandLineArgument struct {
+	Argument  string `xml:"argument,attr"`
+	IsEnabled string `xml:"isEnabled,attr"`
+}
+
+// ExecutionActionType ...
+type ExecutionActionType string
+
+// ExecutionActionTypes
+const (
+	ShellScriptExecutionActionType ExecutionActionType = "Xcode.IDEStandardExecutionActionsCore.ExecutionActionType.ShellScriptAction"
+	SendEmailExecutionActionType   ExecutionActionType = "Xcode.IDEStandardExecutionActionsCore.ExecutionActionType.SendEmailAction"
+)
+
+// ActionContent is the content of an ExecutionAction,
+// the script fields are set for shell script actions.
+type ActionContent struct {
+	Title         string `xml:"title,attr"`
+	ScriptText    string `xml:"scriptText,attr"`
+	ShellToInvoke string `xml:"shellToInvoke,attr"`
+	// EnvironmentBuildable is the target providing the build settings for the script's environment, nil if not set.
+	EnvironmentBuildable *BuildableReference `xml:"EnvironmentBuildable>BuildableReference"`
+}
+
+// ExecutionAction is a pre- or post-action of a scheme action.
+type ExecutionAction struct {
+	ActionType    ExecutionActionType `xml:"ActionType,attr"`
+	ActionContent ActionContent
+}
+
+// BuildableProductRunnable is the target run by the launch and profile actions.
+type BuildableProductRunnable struct {
+	RunnableDebuggingMode string `xml:"runnableDebuggingMode,attr"`
+	BuildableReference    BuildableReference
+}
+
+// MacroExpansion is the target used for expanding the build setting references of the action's
+// environment variables and arguments, if the action has no runnable.
+type MacroExpansion struct {
+	BuildableReference BuildableReference
+}
+
 // BuildActionEntry ...
 type BuildActionEntry struct {
 	BuildForTesting    string `xml:"buildForTesting,attr"`
+	BuildForRunning    string `xml:"buildForRunning,attr"`
+	BuildForProfiling  string `xml:"buildForProfiling,attr"`
 	BuildForArchiving  string `xml:"buildForArchiving,attr"`
+	BuildForAnalyzing  string `xml:"buildForAnalyzing,attr"`
 	BuildableReference BuildableReference
 }
 
 // BuildAction ...
 type BuildAction struct {
-	BuildActionEntries []BuildActionEntry `xml:"BuildActionEntries>BuildActionEntry"`
+	ParallelizeBuildables     string             `xml:"parallelizeBuildables,attr"`
+	BuildImplicitDependencies string             `xml:"buildImplicitDependencies,attr"`
+	RunPostActionsOnFailure   string             `xml:"runPostActionsOnFailure,attr"`
+	PreActions                []ExecutionAction  `xml:"PreActions>ExecutionAction"`
+	PostActions               []ExecutionAction  `xml:"PostActions>ExecutionAction"`
+	BuildActionEntries        []BuildActionEntry `xml:"BuildActionEntries>BuildActionEntry"`
+}
+
+// TestIdentifier is a test class or test method of a testable, like `AppTests` or `AppTests/testLogin()`.
+type TestIdentifier struct {
+	Identifier string `xml:"Identifier,attr"`
 }
 
 // TestableReference ...
 type TestableReference struct {
-	Skipped            string `xml:"skipped,attr"`
-	BuildableReference BuildableReference
+	Skipped        string `xml:"skipped,attr"`
+	Parallelizable string `xml:"parallelizable,attr"`
+	// TestExecutionOrdering is `random` if the tests run in random order, empty for the default (alphabetical) order.
+	TestExecutionOrdering string `xml:"testExecutionOrdering,attr"`
+	// UseTestSelectionWhitelist is YES if only the SelectedTests run, otherwise all tests run except the SkippedTests.
+	UseTestSelectionWhitelist string `xml:"useTestSelectionWhitelist,attr"`
+	BuildableReference        BuildableReference
+	SkippedTests              []TestIdentifier `xml:"SkippedTests>Test"`
+	SelectedTests             []TestIdentifier `xml:"SelectedTests>Test"`
+}
+
+// TestPlanReference ...
+type TestPlanReference struct {
+	// Reference is the test plan's path, like `container:App.xctestplan`.
+	Reference string `xml:"reference,attr"`
+	Default   string `xml:"default,attr"`
 }
 
 // TestAction ...
 type TestAction struct {
-	Testables          []TestableReference `xml:"Testables>TestableReference"`
-	BuildConfiguration string              `xml:"buildConfiguration,attr"`
+	BuildConfiguration           string `xml:"buildConfiguration,attr"`
+	SelectedDebuggerIdentifier   string `xml:"selectedDebuggerIdentifier,attr"`
+	SelectedLauncherIdentifier   string `xml:"selectedLauncherIdentifier,attr"`
+	ShouldUseLaunchSchemeArgsEnv string `xml:"shouldUseLaunchSchemeArgsEnv,attr"`
+	CodeCoverageEnabled          string `xml:"codeCoverageEnabled,attr"`
+	// OnlyGenerateCoverageForSpecifiedTargets is YES if the code coverage is gathered only for the CodeCoverageTargets.
+	OnlyGenerateCoverageForSpecifiedTargets string `xml:"onlyGenerateCoverageForSpecifiedTargets,attr"`
+	Language                                string `xml:"language,attr"`
+	Region                                  string `xml:"region,attr"`
+
+	PreActions           []ExecutionAction     `xml:"PreActions>ExecutionAction"`
+	PostActions          []ExecutionAction     `xml:"PostActions>ExecutionAction"`
+	TestPlans            []TestPlanReference   `xml:"TestPlans>TestPlanReference"`
+	Testables            []TestableReference   `xml:"Testables>TestableReference"`
+	MacroExpansion       *MacroExpansion       `xml:"MacroExpansion"`
+	CommandLineArguments []CommandLineArgument `xml:"CommandLineArguments>CommandLineArgument"`
+	EnvironmentVariables []EnvironmentVariable `xml:"EnvironmentVariables>EnvironmentVariable"`
+	CodeCoverageTargets  []BuildableReference  `xml:"CodeCoverageTargets>BuildableReference"`
+}
+
+// DefaultTestPlan returns the test plan used when testing the scheme without specifying a test plan.
+func (a TestAction) DefaultTestPlan() (TestPlanReference, bool) {
+	for _, testPlan := range a.TestPlans {
+		if testPlan.Default == "YES" {
+			return testPlan, true
+		}
+	}
+	return TestPlanReference{}, false
+}
+
+// LaunchStyle ...
+type LaunchStyle string
+
+// LaunchStyles
+const (
+	AutomaticallyLaunchStyle     LaunchStyle = "0"
+	WaitForExecutableLaunchStyle LaunchStyle = "1"
+)
+
+// StoreKitConfigurationFileReference ...
+type StoreKitConfigurationFileReference struct {
+	// Identifier is the path of the .storekit file, relative to the scheme's container.
+	Identifier string `xml:"identifier,attr"`
+}
+
+// LocationScenarioReference is the simulated location of the launch action.
+type LocationScenarioReference struct {
+	// Identifier is the name of a built-in location (like `London, England`) or the path of a .gpx file.
+	Identifier    string `xml:"identifier,attr"`
+	ReferenceType string `xml:"referenceType,attr"`
+}
+
+// LaunchAction ...
+type LaunchAction struct {
+	BuildConfiguration             string      `xml:"buildConfiguration,attr"`
+	SelectedDebuggerIdentifier     string      `xml:"selectedDebuggerIdentifier,attr"`
+	SelectedLauncherIdentifier     string      `xml:"selectedLauncherIdentifier,attr"`
+	LaunchStyle                    LaunchStyle `xml:"launchStyle,attr"`
+	UseCustomWorkingDirectory      string      `xml:"useCustomWorkingDirectory,attr"`
+	CustomWorkingDirectory         string      `xml:"customWorkingDirectory,attr"`
+	IgnoresPersistentStateOnLaunch string      `xml:"ignoresPersistentStateOnLaunch,attr"`
+	DebugDocumentVersioning        string      `xml:"debugDocumentVersioning,attr"`
+	DebugServiceExtension          string      `xml:"debugServiceExtension,attr"`
+	AllowLocationSimulation        string      `xml:"allowLocationSimulation,attr"`
+	EnableAddressSanitizer         string      `xml:"enableAddressSanitizer,attr"`
+	EnableThreadSanitizer          string      `xml:"enableThreadSanitizer,attr"`
+	EnableUBSanitizer              string      `xml:"enableUBSanitizer,attr"`
+	DisableMainThreadChecker       string      `xml:"disableMainThreadChecker,attr"`
+	Language                       string      `xml:"language,attr"`
+	Region                         string      `xml:"region,attr"`
+
+	PreActions                         []ExecutionAction                   `xml:"PreActions>ExecutionAction"`
+	PostActions                        []ExecutionAction                   `xml:"PostActions>ExecutionAction"`
+	BuildableProductRunnable           *BuildableProductRunnable           `xml:"BuildableProductRunnable"`
+	MacroExpansion                     *MacroExpansion                     `xml:"MacroExpansion"`
+	CommandLineArguments               []CommandLineArgument               `xml:"CommandLineArguments>CommandLineArgument"`
+	EnvironmentVariables               []EnvironmentVariable               `xml:"EnvironmentVariables>EnvironmentVariable"`
+	LocationScenarioReference          *LocationScenarioReference          `xml:"LocationScenarioReference"`
+	StoreKitConfigurationFileReference *StoreKitConfigurationFileReference `xml:"StoreKitConfigurationFileReference"`
+}
+
+// ProfileAction ...
+type ProfileAction struct {
+	BuildConfiguration           string `xml:"buildConfiguration,attr"`
+	ShouldUseLaunchSchemeArgsEnv string `xml:"shouldUseLaunchSchemeArgsEnv,attr"`
+	SavedToolIdentifier          string `xml:"savedToolIdentifier,attr"`
+	UseCustomWorkingDirectory    string `xml:"useCustomWorkingDirectory,attr"`
+	DebugDocumentVersioning      string `xml:"debugDocumentVersioning,attr"`
+
+	PreActions               []ExecutionAction         `xml:"PreActions>ExecutionAction"`
+	PostActions              []ExecutionAction         `xml:"PostActions>ExecutionAction"`
+	BuildableProductRunnable *BuildableProductRunnable `xml:"BuildableProductRunnable"`
+	MacroExpansion           *MacroExpansion           `xml:"MacroExpansion"`
+	CommandLineArguments     []CommandLineArgument     `xml:"CommandLineArguments>CommandLineArgument"`
+	EnvironmentVariables     []EnvironmentVariable     `xml:"EnvironmentVariables>EnvironmentVariable"`
+}
+
+// AnalyzeAction ...
+type AnalyzeAction struct {
+	BuildConfiguration string            `xml:"buildConfiguration,attr"`
+	PreActions         []ExecutionAction `xml:"PreActions>ExecutionAction"`
+	PostActions        []ExecutionAction `xml:"PostActions>ExecutionAction"`
 }
 
 // ArchiveAction ...
 type ArchiveAction struct {
-	BuildConfiguration string `xml:"buildConfiguration,attr"`
+	BuildConfiguration       string            `xml:"buildConfiguration,attr"`
+	RevealArchiveInOrganizer string            `xml:"revealArchiveInOrganizer,attr"`
+	CustomArchiveName        string            `xml:"customArchiveName,attr"`
+	PreActions               []ExecutionAction `xml:"PreActions>ExecutionAction"`
+	PostActions              []ExecutionAction `xml:"PostActions>ExecutionAction"`
 }
 
 // Scheme ...
 type Scheme struct {
+	LastUpgradeVersion string `xml:"LastUpgradeVersion,attr"`
+	Version            string `xml:"version,attr"`
+
 	BuildAction   BuildAction
-	ArchiveAction ArchiveAction
 	TestAction    TestAction
+	LaunchAction  LaunchAction
+	ProfileAction ProfileAction
+	AnalyzeAction AnalyzeAction
+	ArchiveAction ArchiveAction
 
-	Name string
-	Path string
+	Name string `xml:"-"`
+	Path string `xml:"-"`
+	// IsShared is true for the schemes in the xcshareddata directory of a project or workspace.
+	IsShared bool `xml:"-"`
+	// User is the owner of a user scheme (the name of its xcuserdatad directory), empty for shared schemes.
+	User string `xml:"-"`
 }
 
 // Open ...
@@ -88,10 +280,28 @@ func Open(pth string) (Scheme, error) {
 
 	scheme.Name = strings.TrimSuffix(filepath.Base(pth), filepath.Ext(pth))
 	scheme.Path = pth
+	scheme.IsShared, scheme.User = schemeOwner(pth)
 
 	return scheme, nil
 }
 
+// schemeOwner returns whether the scheme file is a shared scheme, or the owner user of a user scheme.
+func schemeOwner(pth string) (bool, string) {
+	schemesDir := filepath.Dir(pth)
+	if filepath.Base(schemesDir) != "xcschemes" {
+		return false, ""
+	}
+
+	dataDir := filepath.Dir(schemesDir)
+	if filepath.Base(dataDir) == "xcshareddata" {
+		return true, ""
+	}
+	if filepath.Ext(dataDir) == ".xcuserdatad" {
+		return false, strings.TrimSuffix(filepath.Base(dataDir), ".xcuserdatad")
+	}
+	return false, ""
+}
+
 // AppBuildActionEntry ...
 func (s Scheme) AppBuildActionEntry() (BuildActionEntry, bool) {
 	var entry BuildActionEntry
@@ -69,6 +69,93 @@ func TestAppTestActionEntry(t *testing.T) {
 	require.False(t, scheme.TestAction.Testables[1].BuildableReference.IsAppReference())
 }
 
+func TestScheme_Actions(t *testing.T) {
+	var scheme Scheme
+	require.NoError(t, xml.Unmarshal([]byte(actionsSchemeContent), &scheme))
+
+	require.Equal(t, "1400", scheme.LastUpgradeVersion)
+	require.Equal(t, "1.7", scheme.Version)
+
+	t.Log("build action")
+	{
+		action := scheme.BuildAction
+		require.Equal(t, "YES", action.ParallelizeBuildables)
+		require.Equal(t, "YES", action.RunPostActionsOnFailure)
+		require.Equal(t, []ExecutionAction{{
+			ActionType: ShellScriptExecutionActionType,
+			ActionContent: ActionContent{
+				Title:         "Run Script",
+				ScriptText:    "echo \"build started\"\n",
+				ShellToInvoke: "/bin/sh",
+				EnvironmentBuildable: &BuildableReference{
+					BuildableIdentifier: "primary",
+					BlueprintIdentifier: "BA3CBE7419F7A93800CED4D5",
+					BuildableName:       "App.app",
+					BlueprintName:       "App",
+					ReferencedContainer: "container:App.xcodeproj",
+				},
+			},
+		}}, action.PreActions)
+		require.Equal(t, 0, len(action.PostActions))
+		require.Equal(t, "NO", action.BuildActionEntries[0].BuildForProfiling)
+	}
+
+	t.Log("test action")
+	{
+		action := scheme.TestAction
+		require.Equal(t, "YES", action.CodeCoverageEnabled)
+		require.Equal(t, "YES", action.OnlyGenerateCoverageForSpecifiedTargets)
+		require.Equal(t, "de", action.Language)
+		require.Equal(t, "DE", action.Region)
+		require.Equal(t, 1, len(action.PostActions))
+		require.Equal(t, "Collect Results", action.PostActions[0].ActionContent.Title)
+		require.Nil(t, action.PostActions[0].ActionContent.EnvironmentBuildable)
+
+		require.Equal(t, 2, len(action.TestPlans))
+		testPlan, ok := action.DefaultTestPlan()
+		require.True(t, ok)
+		require.Equal(t, "container:UnitTests.xctestplan", testPlan.Reference)
+
+		require.Equal(t, 2, len(action.Testables))
+		require.Equal(t, "YES", action.Testables[0].Parallelizable)
+		require.Equal(t, "random", action.Testables[0].TestExecutionOrdering)
+		require.Equal(t, []TestIdentifier{{Identifier: "AppTests/testSlow()"}, {Identifier: "NetworkTests"}}, action.Testables[0].SkippedTests)
+		require.Equal(t, 0, len(action.Testables[0].SelectedTests))
+		require.Equal(t, "YES", action.Testables[1].UseTestSelectionWhitelist)
+		require.Equal(t, []TestIdentifier{{Identifier: "AppUITests/testLogin()"}}, action.Testables[1].SelectedTests)
+
+		require.Equal(t, "App", action.MacroExpansion.BuildableReference.BlueprintName)
+		require.Equal(t, []CommandLineArgument{{Argument: "-UITesting", IsEnabled: "YES"}}, action.CommandLineArguments)
+		require.Equal(t, []EnvironmentVariable{{Key: "API_URL", Value: "https://staging.example.com", IsEnabled: "NO"}}, action.EnvironmentVariables)
+		require.Equal(t, 1, len(action.CodeCoverageTargets))
+		require.Equal(t, "App", action.CodeCoverageTargets[0].BlueprintName)
+	}
+
+	t.Log("launch action")
+	{
+		action := scheme.LaunchAction
+		require.Equal(t, "Debug", action.BuildConfiguration)
+		require.Equal(t, WaitForExecutableLaunchStyle, action.LaunchStyle)
+		require.Equal(t, "Xcode.DebuggerFoundation.Debugger.LLDB", action.SelectedDebuggerIdentifier)
+		require.Equal(t, "YES", action.EnableThreadSanitizer)
+		require.Equal(t, "YES", action.DisableMainThreadChecker)
+		require.Equal(t, "0", action.BuildableProductRunnable.RunnableDebuggingMode)
+		require.True(t, action.BuildableProductRunnable.BuildableReference.IsAppReference())
+		require.Nil(t, action.MacroExpansion)
+		require.Equal(t, []CommandLineArgument{{Argument: "-com.apple.CoreData.SQLDebug 1", IsEnabled: "YES"}, {Argument: "-FIRDebugEnabled", IsEnabled: "NO"}}, action.CommandLineArguments)
+		require.Equal(t, []EnvironmentVariable{{Key: "OS_ACTIVITY_MODE", Value: "disable", IsEnabled: "YES"}}, action.EnvironmentVariables)
+		require.Equal(t, &LocationScenarioReference{Identifier: "London, England", ReferenceType: "1"}, action.LocationScenarioReference)
+		require.Equal(t, &StoreKitConfigurationFileReference{Identifier: "../App/Products.storekit"}, action.StoreKitConfigurationFileReference)
+	}
+
+	require.Equal(t, "Release", scheme.ProfileAction.BuildConfiguration)
+	require.Equal(t, "App.app", scheme.ProfileAction.BuildableProductRunnable.BuildableReference.BuildableName)
+	require.Equal(t, "Debug", scheme.AnalyzeAction.BuildConfiguration)
+	require.Equal(t, "Release", scheme.ArchiveAction.BuildConfiguration)
+	require.Equal(t, "App Store", scheme.ArchiveAction.CustomArchiveName)
+	require.Equal(t, 1, len(scheme.ArchiveAction.PostActions))
+}
+
 const schemeContent = `<?xml version="1.0" encoding="UTF-8"?>
 <Scheme
    LastUpgradeVersion = "0800"
@@ -195,3 +282,228 @@ const schemeContent = `<?xml version="1.0" encoding="UTF-8"?>
    </ArchiveAction>
 </Scheme>
 `
+
+const actionsSchemeContent = `<?xml version="1.0" encoding="UTF-8"?>
+<Scheme
+   LastUpgradeVersion = "1400"
+   version = "1.7">
+   <BuildAction
+      parallelizeBuildables = "YES"
+      buildImplicitDependencies = "YES"
+      runPostActionsOnFailure = "YES">
+      <PreActions>
+         <ExecutionAction
+            ActionType = "Xcode.IDEStandardExecutionActionsCore.ExecutionActionType.ShellScriptAction">
+            <ActionContent
+               title = "Run Script"
+               scriptText = "echo &quot;build started&quot;&#10;"
+               shellToInvoke = "/bin/sh">
+               <EnvironmentBuildable>
+                  <BuildableReference
+                     BuildableIdentifier = "primary"
+                     BlueprintIdentifier = "BA3CBE7419F7A93800CED4D5"
+                     BuildableName = "App.app"
+                     BlueprintName = "App"
+                     ReferencedContainer = "container:App.xcodeproj">
+                  </BuildableReference>
+               </EnvironmentBuildable>
+            </ActionContent>
+         </ExecutionAction>
+      </PreActions>
+      <BuildActionEntries>
+         <BuildActionEntry
+            buildForTesting = "YES"
+            buildForRunning = "YES"
+            buildForProfiling = "NO"
+            buildForArchiving = "YES"
+            buildForAnalyzing = "YES">
+            <BuildableReference
+               BuildableIdentifier = "primary"
+               BlueprintIdentifier = "BA3CBE7419F7A93800CED4D5"
+               BuildableName = "App.app"
+               BlueprintName = "App"
+               ReferencedContainer = "container:App.xcodeproj">
+            </BuildableReference>
+         </BuildActionEntry>
+      </BuildActionEntries>
+   </BuildAction>
+   <TestAction
+      buildConfiguration = "Debug"
+      selectedDebuggerIdentifier = "Xcode.DebuggerFoundation.Debugger.LLDB"
+      selectedLauncherIdentifier = "Xcode.DebuggerFoundation.Launcher.LLDB"
+      shouldUseLaunchSchemeArgsEnv = "NO"
+      language = "de"
+      region = "DE"
+      codeCoverageEnabled = "YES"
+      onlyGenerateCoverageForSpecifiedTargets = "YES">
+      <PostActions>
+         <ExecutionAction
+            ActionType = "Xcode.IDEStandardExecutionActionsCore.ExecutionActionType.ShellScriptAction">
+            <ActionContent
+               title = "Collect Results"
+               scriptText = "./collect.sh&#10;">
+            </ActionContent>
+         </ExecutionAction>
+      </PostActions>
+      <TestPlans>
+         <TestPlanReference
+            reference = "container:UnitTests.xctestplan"
+            default = "YES">
+         </TestPlanReference>
+         <TestPlanReference
+            reference = "container:UITests.xctestplan">
+         </TestPlanReference>
+      </TestPlans>
+      <MacroExpansion>
+         <BuildableReference
+            BuildableIdentifier = "primary"
+            BlueprintIdentifier = "BA3CBE7419F7A93800CED4D5"
+            BuildableName = "App.app"
+            BlueprintName = "App"
+            ReferencedContainer = "container:App.xcodeproj">
+         </BuildableReference>
+      </MacroExpansion>
+      <CommandLineArguments>
+         <CommandLineArgument
+            argument = "-UITesting"
+            isEnabled = "YES">
+         </CommandLineArgument>
+      </CommandLineArguments>
+      <EnvironmentVariables>
+         <EnvironmentVariable
+            key = "API_URL"
+            value = "https://staging.example.com"
+            isEnabled = "NO">
+         </EnvironmentVariable>
+      </EnvironmentVariables>
+      <CodeCoverageTargets>
+         <BuildableReference
+            BuildableIdentifier = "primary"
+            BlueprintIdentifier = "BA3CBE7419F7A93800CED4D5"
+            BuildableName = "App.app"
+            BlueprintName = "App"
+            ReferencedContainer = "container:App.xcodeproj">
+         </BuildableReference>
+      </CodeCoverageTargets>
+      <Testables>
+         <TestableReference
+            skipped = "NO"
+            parallelizable = "YES"
+            testExecutionOrdering = "random">
+            <BuildableReference
+               BuildableIdentifier = "primary"
+               BlueprintIdentifier = "BA3CBE9019F7A93900CED4D5"
+               BuildableName = "AppTests.xctest"
+               BlueprintName = "AppTests"
+               ReferencedContainer = "container:App.xcodeproj">
+            </BuildableReference>
+            <SkippedTests>
+               <Test
+                  Identifier = "AppTests/testSlow()">
+               </Test>
+               <Test
+                  Identifier = "NetworkTests">
+               </Test>
+            </SkippedTests>
+         </TestableReference>
+         <TestableReference
+            skipped = "NO"
+            useTestSelectionWhitelist = "YES">
+            <BuildableReference
+               BuildableIdentifier = "primary"
+               BlueprintIdentifier = "BA4CBE9019F7A93900CED4D5"
+               BuildableName = "AppUITests.xctest"
+               BlueprintName = "AppUITests"
+               ReferencedContainer = "container:App.xcodeproj">
+            </BuildableReference>
+            <SelectedTests>
+               <Test
+                  Identifier = "AppUITests/testLogin()">
+               </Test>
+            </SelectedTests>
+         </TestableReference>
+      </Testables>
+   </TestAction>
+   <LaunchAction
+      buildConfiguration = "Debug"
+      selectedDebuggerIdentifier = "Xcode.DebuggerFoundation.Debugger.LLDB"
+      selectedLauncherIdentifier = "Xcode.DebuggerFoundation.Launcher.LLDB"
+      enableThreadSanitizer = "YES"
+      disableMainThreadChecker = "YES"
+      launchStyle = "1"
+      useCustomWorkingDirectory = "NO"
+      ignoresPersistentStateOnLaunch = "NO"
+      debugDocumentVersioning = "YES"
+      debugServiceExtension = "internal"
+      allowLocationSimulation = "YES">
+      <BuildableProductRunnable
+         runnableDebuggingMode = "0">
+         <BuildableReference
+            BuildableIdentifier = "primary"
+            BlueprintIdentifier = "BA3CBE7419F7A93800CED4D5"
+            BuildableName = "App.app"
+            BlueprintName = "App"
+            ReferencedContainer = "container:App.xcodeproj">
+         </BuildableReference>
+      </BuildableProductRunnable>
+      <CommandLineArguments>
+         <CommandLineArgument
+            argument = "-com.apple.CoreData.SQLDebug 1"
+            isEnabled = "YES">
+         </CommandLineArgument>
+         <CommandLineArgument
+            argument = "-FIRDebugEnabled"
+            isEnabled = "NO">
+         </CommandLineArgument>
+      </CommandLineArguments>
+      <EnvironmentVariables>
+         <EnvironmentVariable
+            key = "OS_ACTIVITY_MODE"
+            value = "disable"
+            isEnabled = "YES">
+         </EnvironmentVariable>
+      </EnvironmentVariables>
+      <LocationScenarioReference
+         identifier = "London, England"
+         referenceType = "1">
+      </LocationScenarioReference>
+      <StoreKitConfigurationFileReference
+         identifier = "../App/Products.storekit">
+      </StoreKitConfigurationFileReference>
+   </LaunchAction>
+   <ProfileAction
+      buildConfiguration = "Release"
+      shouldUseLaunchSchemeArgsEnv = "YES"
+      savedToolIdentifier = ""
+      useCustomWorkingDirectory = "NO"
+      debugDocumentVersioning = "YES">
+      <BuildableProductRunnable
+         runnableDebuggingMode = "0">
+         <BuildableReference
+            BuildableIdentifier = "primary"
+            BlueprintIdentifier = "BA3CBE7419F7A93800CED4D5"
+            BuildableName = "App.app"
+            BlueprintName = "App"
+            ReferencedContainer = "container:App.xcodeproj">
+         </BuildableReference>
+      </BuildableProductRunnable>
+   </ProfileAction>
+   <AnalyzeAction
+      buildConfiguration = "Debug">
+   </AnalyzeAction>
+   <ArchiveAction
+      buildConfiguration = "Release"
+      customArchiveName = "App Store"
+      revealArchiveInOrganizer = "YES">
+      <PostActions>
+         <ExecutionAction
+            ActionType = "Xcode.IDEStandardExecutionActionsCore.ExecutionActionType.ShellScriptAction">
+            <ActionContent
+               title = "Upload Symbols"
+               scriptText = "./upload-symbols.sh&#10;">
+            </ActionContent>
+         </ExecutionAction>
+      </PostActions>
+   </ArchiveAction>
+</Scheme>
+`
@@ -0,0 +1,145 @@
+package xcscheme
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/bitrise-io/go-plist"
+	"github.com/bitrise-io/go-utils/fileutil"
+)
+
+// SchemeManagementFileName is the name of the file storing the scheme list settings, next to the schemes.
+const SchemeManagementFileName = "xcschememanagement.plist"
+
+// sharedSchemeUserStateSuffix marks the shared schemes in the SchemeUserState keys.
+const sharedSchemeUserStateSuffix = "_^#shared#^_"
+
+// SchemeUserState is the state of a scheme in Xcode's scheme list.
+type SchemeUserState struct {
+	// IsShown is nil if it is not set, a scheme is shown by default.
+	IsShown   *bool `plist:"isShown,omitempty"`
+	OrderHint int   `plist:"orderHint"`
+}
+
+// Visible reports whether the scheme is shown in Xcode's scheme list.
+func (s SchemeUserState) Visible() bool {
+	return s.IsShown == nil || *s.IsShown
+}
+
+// BuildableAutocreation ...
+type BuildableAutocreation struct {
+	Primary bool `plist:"primary"`
+}
+
+// SchemeManagement is the content of an xcschememanagement.plist file.
+// The keys not modelled by SchemeManagement are kept as they were in the opened file, when it is written.
+type SchemeManagement struct {
+	// SchemeUserState is the state of the schemes by their SchemeUserStateKey.
+	SchemeUserState map[string]SchemeUserState `plist:"SchemeUserState,omitempty"`
+	// SuppressBuildableAutocreation are the targets (by their BlueprintIdentifier) Xcode does not create a scheme for automatically.
+	SuppressBuildableAutocreation map[string]BuildableAutocreation `plist:"SuppressBuildableAutocreation,omitempty"`
+
+	// raw is the content of the opened file.
+	raw map[string]interface{}
+}
+
+// SchemeUserStateKey returns the SchemeUserState key of the scheme, like `App.xcscheme_^#shared#^_` for a shared scheme
+// and `App.xcscheme` for a user scheme.
+func SchemeUserStateKey(name string, shared bool) string {
+	key := name + ".xcscheme"
+	if shared {
+		key += sharedSchemeUserStateSuffix
+	}
+	return key
+}
+
+// SchemeManagementPath returns the path of the user's xcschememanagement.plist in the project or workspace,
+// the path of the shared xcschememanagement.plist if the user is empty.
+func SchemeManagementPath(containerPath, user string) string {
+	if user == "" {
+		return filepath.Join(containerPath, "xcshareddata", "xcschemes", SchemeManagementFileName)
+	}
+	return filepath.Join(containerPath, "xcuserdata", user+".xcuserdatad", "xcschemes", SchemeManagementFileName)
+}
+
+// OpenSchemeManagement reads and parses the xcschememanagement.plist file.
+func OpenSchemeManagement(pth string) (SchemeManagement, error) {
+	b, err := fileutil.ReadBytesFromFile(pth)
+	if err != nil {
+		return SchemeManagement{}, err
+	}
+
+	var management SchemeManagement
+	if _, err := plist.Unmarshal(b, &management); err != nil {
+		return SchemeManagement{}, fmt.Errorf("failed to unmarshal scheme management file: %s, error: %s", pth, err)
+	}
+	if _, err := plist.Unmarshal(b, &management.raw); err != nil {
+		return SchemeManagement{}, fmt.Errorf("failed to unmarshal scheme management file: %s, error: %s", pth, err)
+	}
+	return management, nil
+}
+
+// Marshal returns the scheme management in XML plist format.
+func (m SchemeManagement) Marshal() ([]byte, error) {
+	b, err := plist.MarshalIndent(m.content(), plist.XMLFormat, "\t")
+	if err != nil {
+		return nil, err
+	}
+	return append(b, '\n'), nil
+}
+
+// content returns the raw content of the opened file updated with the scheme management's fields,
+// the unknown keys of the file and of its entries are kept.
+func (m SchemeManagement) content() map[string]interface{} {
+	content := copyDict(m.raw)
+
+	delete(content, "SchemeUserState")
+	if len(m.SchemeUserState) > 0 {
+		rawStates := copyDict(m.raw["SchemeUserState"])
+		states := map[string]interface{}{}
+		for key, state := range m.SchemeUserState {
+			entry := copyDict(rawStates[key])
+			delete(entry, "isShown")
+			if state.IsShown != nil {
+				entry["isShown"] = *state.IsShown
+			}
+			entry["orderHint"] = state.OrderHint
+			states[key] = entry
+		}
+		content["SchemeUserState"] = states
+	}
+
+	delete(content, "SuppressBuildableAutocreation")
+	if len(m.SuppressBuildableAutocreation) > 0 {
+		rawAutocreations := copyDict(m.raw["SuppressBuildableAutocreation"])
+		autocreations := map[string]interface{}{}
+		for key, autocreation := range m.SuppressBuildableAutocreation {
+			entry := copyDict(rawAutocreations[key])
+			entry["primary"] = autocreation.Primary
+			autocreations[key] = entry
+		}
+		content["SuppressBuildableAutocreation"] = autocreations
+	}
+
+	return content
+}
+
+// copyDict returns a shallow copy of the plist dictionary, an empty dictionary if the value is not a dictionary.
+func copyDict(value interface{}) map[string]interface{} {
+	dict := map[string]interface{}{}
+	if m, ok := value.(map[string]interface{}); ok {
+		for key, value := range m {
+			dict[key] = value
+		}
+	}
+	return dict
+}
+
+// Write writes the scheme management to the given path.
+func (m SchemeManagement) Write(pth string) error {
+	b, err := m.Marshal()
+	if err != nil {
+		return fmt.Errorf("failed to marshal scheme management: %s", err)
+	}
+	return fileutil.WriteBytesToFile(pth, b)
+}
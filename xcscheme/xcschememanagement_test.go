@@ -0,0 +1,93 @@
+package xcscheme
+
+import (
+	"testing"
+
+	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenSchemeManagement(t *testing.T) {
+	pth := testhelper.CreateTmpFile(t, SchemeManagementFileName, schemeManagementContent)
+	management, err := OpenSchemeManagement(pth)
+	require.NoError(t, err)
+
+	require.Equal(t, 2, len(management.SchemeUserState))
+	shared := management.SchemeUserState[SchemeUserStateKey("App", true)]
+	require.Equal(t, 0, shared.OrderHint)
+	require.True(t, shared.Visible())
+	user := management.SchemeUserState[SchemeUserStateKey("App-Staging", false)]
+	require.Equal(t, 1, user.OrderHint)
+	require.False(t, user.Visible())
+	require.Equal(t, map[string]BuildableAutocreation{"BA3CBE7419F7A93800CED4D5": {Primary: true}}, management.SuppressBuildableAutocreation)
+
+	shown := true
+	management.SchemeUserState[SchemeUserStateKey("App-Staging", false)] = SchemeUserState{IsShown: &shown, OrderHint: 1}
+	require.NoError(t, management.Write(pth))
+
+	management, err = OpenSchemeManagement(pth)
+	require.NoError(t, err)
+	require.True(t, management.SchemeUserState[SchemeUserStateKey("App-Staging", false)].Visible())
+	require.Equal(t, 1, len(management.SuppressBuildableAutocreation))
+
+	t.Log("unknown keys are kept")
+	{
+		require.Equal(t, "custom", management.raw["CustomKey"])
+		entry := management.raw["SchemeUserState"].(map[string]interface{})[SchemeUserStateKey("App-Staging", false)].(map[string]interface{})
+		require.Equal(t, true, entry["customKey"])
+		require.Equal(t, true, entry["isShown"])
+	}
+
+	t.Log("removed entries are not written")
+	{
+		delete(management.SchemeUserState, SchemeUserStateKey("App-Staging", false))
+		management.SuppressBuildableAutocreation = nil
+		require.NoError(t, management.Write(pth))
+
+		management, err := OpenSchemeManagement(pth)
+		require.NoError(t, err)
+		require.Equal(t, 1, len(management.SchemeUserState))
+		require.Equal(t, 0, len(management.SuppressBuildableAutocreation))
+		require.Equal(t, "custom", management.raw["CustomKey"])
+	}
+}
+
+func TestSchemeManagementPath(t *testing.T) {
+	require.Equal(t, "/App.xcodeproj/xcshareddata/xcschemes/xcschememanagement.plist", SchemeManagementPath("/App.xcodeproj", ""))
+	require.Equal(t, "/App.xcodeproj/xcuserdata/john.xcuserdatad/xcschemes/xcschememanagement.plist", SchemeManagementPath("/App.xcodeproj", "john"))
+}
+
+const schemeManagementContent = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<plist version="1.0">
+<dict>
+	<key>CustomKey</key>
+	<string>custom</string>
+	<key>SchemeUserState</key>
+	<dict>
+		<key>App-Staging.xcscheme</key>
+		<dict>
+			<key>customKey</key>
+			<true/>
+			<key>isShown</key>
+			<false/>
+			<key>orderHint</key>
+			<integer>1</integer>
+		</dict>
+		<key>App.xcscheme_^#shared#^_</key>
+		<dict>
+			<key>orderHint</key>
+			<integer>0</integer>
+		</dict>
+	</dict>
+	<key>SuppressBuildableAutocreation</key>
+	<dict>
+		<key>BA3CBE7419F7A93800CED4D5</key>
+		<dict>
+			<key>primary</key>
+			<true/>
+		</dict>
+	</dict>
+</dict>
+</plist>
+`
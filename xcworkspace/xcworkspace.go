@@ -9,6 +9,7 @@ import (
 	"github.com/bitrise-io/go-utils/fileutil"
 	"github.com/bitrise-io/go-utils/pathutil"
 	"github.com/bitrise-io/xcode-project/serialized"
+	"github.com/bitrise-io/xcode-project/swiftpm"
 	"github.com/bitrise-io/xcode-project/xcodebuild"
 	"github.com/bitrise-io/xcode-project/xcodeproj"
 	"github.com/bitrise-io/xcode-project/xcscheme"
@@ -129,6 +130,136 @@ func (w Workspace) ProjectFileLocations() ([]string, error) {
 	return projectLocations, nil
 }
 
+// PackageResolvedPath returns the path of the workspace's Package.resolved file,
+// it pins the Swift packages of every project in the workspace.
+func (w Workspace) PackageResolvedPath() string {
+	return filepath.Join(w.Path, "xcshareddata", "swiftpm", swiftpm.ResolvedFileName)
+}
+
+// CheckPackageResolution compares the Swift package references of the workspace's projects with the workspace's Package.resolved file,
+// see xcodeproj.XcodeProj.CheckPackageResolution for sourcePackagesDir.
+func (w Workspace) CheckPackageResolution(sourcePackagesDir string) ([]xcodeproj.PackageResolutionIssue, error) {
+	projectLocations, err := w.ProjectFileLocations()
+	if err != nil {
+		return nil, err
+	}
+
+	var references []xcodeproj.SwiftPackageReference
+	for _, projectLocation := range projectLocations {
+		if exist, err := pathutil.IsPathExists(projectLocation); err != nil {
+			return nil, fmt.Errorf("failed to check if project exist at: %s, error: %s", projectLocation, err)
+		} else if !exist {
+			continue
+		}
+
+		project, err := xcodeproj.Open(projectLocation)
+		if err != nil {
+			return nil, err
+		}
+
+		references = append(references, project.Proj.SwiftPackageReferences...)
+	}
+
+	return xcodeproj.CheckPackageResolutionAt(w.PackageResolvedPath(), references, sourcePackagesDir)
+}
+
+// NormalizeObjectIDs normalizes the object IDs of the workspace's projects (see xcodeproj.XcodeProj.NormalizeObjectIDs),
+// the BlueprintIdentifiers of the workspace's schemes and of the other projects' schemes,
+// and the cross-project references of the other projects are updated too.
+func (w Workspace) NormalizeObjectIDs(sortLists bool) error {
+	projectLocations, err := w.ProjectFileLocations()
+	if err != nil {
+		return err
+	}
+
+	var existingLocations []string
+	for _, projectLocation := range projectLocations {
+		if exist, err := pathutil.IsPathExists(projectLocation); err != nil {
+			return fmt.Errorf("failed to check if project exist at: %s, error: %s", projectLocation, err)
+		} else if exist {
+			existingLocations = append(existingLocations, projectLocation)
+		}
+	}
+
+	for i, projectLocation := range existingLocations {
+		project, err := xcodeproj.Open(projectLocation)
+		if err != nil {
+			return err
+		}
+
+		containers := []string{w.Path}
+		containers = append(containers, existingLocations[:i]...)
+		containers = append(containers, existingLocations[i+1:]...)
+		if err := project.NormalizeObjectIDs(sortLists, containers...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DependencyGraph returns the target dependency graph of the workspace's projects.
+// Dependencies on the targets of other projects are resolved through the workspace:
+// the referenced projects, which are not part of the workspace (like subprojects), are added to the graph too.
+// Dependencies on the targets of not existing projects stay unresolved.
+// The graph can be exported with its DOT, Mermaid and JSON methods.
+func (w Workspace) DependencyGraph() (xcodeproj.DependencyGraph, error) {
+	projectLocations, err := w.ProjectFileLocations()
+	if err != nil {
+		return xcodeproj.DependencyGraph{}, err
+	}
+
+	graph, err := xcodeproj.NewDependencyGraph()
+	if err != nil {
+		return xcodeproj.DependencyGraph{}, err
+	}
+
+	// visited are the cleaned absolute paths of the projects already added to the graph or not existing
+	visited := map[string]bool{}
+	add := func(projectLocation string) (bool, error) {
+		pth, err := pathutil.AbsPath(projectLocation)
+		if err != nil {
+			return false, err
+		}
+		if visited[pth] {
+			return false, nil
+		}
+		visited[pth] = true
+
+		if exist, err := pathutil.IsPathExists(pth); err != nil {
+			return false, fmt.Errorf("failed to check if project exist at: %s, error: %s", pth, err)
+		} else if !exist {
+			return false, nil
+		}
+
+		project, err := xcodeproj.Open(pth)
+		if err != nil {
+			return false, err
+		}
+		return true, graph.AddProject(project)
+	}
+
+	for _, projectLocation := range projectLocations {
+		if _, err := add(projectLocation); err != nil {
+			return xcodeproj.DependencyGraph{}, err
+		}
+	}
+
+	for {
+		added := false
+		for _, target := range graph.Unresolved() {
+			ok, err := add(target.ProjectPath)
+			if err != nil {
+				return xcodeproj.DependencyGraph{}, err
+			}
+			added = added || ok
+		}
+
+		if !added {
+			return graph, nil
+		}
+	}
+}
+
 // Open ...
 func Open(pth string) (Workspace, error) {
 	contentsPth := filepath.Join(pth, "contents.xcworkspacedata")
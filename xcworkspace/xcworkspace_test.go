@@ -1,10 +1,14 @@
 package xcworkspace
 
 import (
+	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/bitrise-io/go-utils/fileutil"
+	"github.com/bitrise-io/go-utils/pathutil"
 	"github.com/bitrise-io/xcode-project/testhelper"
+	"github.com/bitrise-io/xcode-project/xcodeproj"
 	"github.com/bitrise-io/xcode-project/xcscheme"
 	"github.com/stretchr/testify/require"
 	"golang.org/x/text/unicode/norm"
@@ -145,6 +149,167 @@ func TestOpen(t *testing.T) {
 	require.Equal(t, "group:Group", workspace.Groups[0].Location)
 }
 
+func TestWorkspace_DependencyGraph(t *testing.T) {
+	dir, err := pathutil.NormalizedOSTempDirPath("__xcode-proj__")
+	require.NoError(t, err)
+
+	// App/XcodeProj.xcodeproj is part of the workspace, it depends on Lib/Lib.xcodeproj, which is not
+	files := map[string]string{
+		filepath.Join("XcodeProj.xcworkspace", "contents.xcworkspacedata"): `<?xml version="1.0" encoding="UTF-8"?>
+<Workspace
+   version = "1.0">
+   <FileRef
+      location = "group:App/XcodeProj.xcodeproj">
+   </FileRef>
+   <FileRef
+      location = "group:Missing/Missing.xcodeproj">
+   </FileRef>
+</Workspace>
+`,
+		filepath.Join("App", "XcodeProj.xcodeproj", "project.pbxproj"): testhelper.XcodeProjectTestWithProjectDependency("../Lib/Lib.xcodeproj"),
+		filepath.Join("Lib", "Lib.xcodeproj", "project.pbxproj"):       testhelper.XcodeProjectTest,
+	}
+	for pth, content := range files {
+		pth = filepath.Join(dir, pth)
+		require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0755))
+		require.NoError(t, fileutil.WriteStringToFile(pth, content))
+	}
+
+	workspace, err := Open(filepath.Join(dir, "XcodeProj.xcworkspace"))
+	require.NoError(t, err)
+
+	graph, err := workspace.DependencyGraph()
+	require.NoError(t, err)
+	require.Equal(t, 0, len(graph.Unresolved()))
+	require.Equal(t, 6, len(graph.Targets()))
+
+	target, ok := graph.TargetByName("XcodeProjUITests")
+	require.True(t, ok)
+	require.Equal(t, filepath.Join(dir, "App", "XcodeProj.xcodeproj"), target.ProjectPath)
+
+	var names []string
+	for _, dependency := range graph.TransitiveDependencies(target) {
+		names = append(names, dependency.String())
+	}
+	require.Equal(t, []string{"XcodeProj/XcodeProj", "XcodeProj/TodayExtension", "Lib/TodayExtension"}, names)
+}
+
+func TestWorkspace_NormalizeObjectIDs(t *testing.T) {
+	const appTargetID = "7D5B35FB20E28EE80022BAE6"
+
+	dir, err := pathutil.NormalizedOSTempDirPath("__xcode-proj__")
+	require.NoError(t, err)
+
+	// the workspace scheme builds the app of App/XcodeProj.xcodeproj and the app of Lib/Lib.xcodeproj, which is not part of the workspace
+	schemePth := filepath.Join(dir, "XcodeProj.xcworkspace", "xcshareddata", "xcschemes", "XcodeProj.xcscheme")
+	files := map[string]string{
+		filepath.Join("XcodeProj.xcworkspace", "contents.xcworkspacedata"): `<?xml version="1.0" encoding="UTF-8"?>
+<Workspace
+   version = "1.0">
+   <FileRef
+      location = "group:App/XcodeProj.xcodeproj">
+   </FileRef>
+</Workspace>
+`,
+		filepath.Join("XcodeProj.xcworkspace", "xcshareddata", "xcschemes", "XcodeProj.xcscheme"): `<?xml version="1.0" encoding="UTF-8"?>
+<Scheme
+   LastUpgradeVersion = "1000"
+   version = "1.3">
+   <BuildAction>
+      <BuildActionEntries>
+         <BuildActionEntry
+            buildForRunning = "YES">
+            <BuildableReference
+               BuildableIdentifier = "primary"
+               BlueprintIdentifier = "` + appTargetID + `"
+               ReferencedContainer = "container:App/XcodeProj.xcodeproj">
+            </BuildableReference>
+         </BuildActionEntry>
+         <BuildActionEntry
+            buildForRunning = "YES">
+            <BuildableReference
+               BuildableIdentifier = "primary"
+               BlueprintIdentifier = "` + appTargetID + `"
+               ReferencedContainer = "container:Lib/Lib.xcodeproj">
+            </BuildableReference>
+         </BuildActionEntry>
+      </BuildActionEntries>
+   </BuildAction>
+</Scheme>
+`,
+		filepath.Join("App", "XcodeProj.xcodeproj", "project.pbxproj"): testhelper.XcodeProjectTest,
+		filepath.Join("Lib", "Lib.xcodeproj", "project.pbxproj"):       testhelper.XcodeProjectTest,
+	}
+	for pth, content := range files {
+		pth = filepath.Join(dir, pth)
+		require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0755))
+		require.NoError(t, fileutil.WriteStringToFile(pth, content))
+	}
+
+	workspace, err := Open(filepath.Join(dir, "XcodeProj.xcworkspace"))
+	require.NoError(t, err)
+	require.NoError(t, workspace.NormalizeObjectIDs(false))
+
+	scheme, err := xcscheme.Open(schemePth)
+	require.NoError(t, err)
+	entries := scheme.BuildAction.BuildActionEntries
+	require.Equal(t, 2, len(entries))
+	require.NotEqual(t, appTargetID, entries[0].BuildableReference.BlueprintIdentifier)
+	require.Equal(t, appTargetID, entries[1].BuildableReference.BlueprintIdentifier)
+}
+
+func TestWorkspace_NormalizeObjectIDs_ProjectReferences(t *testing.T) {
+	dir, err := pathutil.NormalizedOSTempDirPath("__xcode-proj__")
+	require.NoError(t, err)
+
+	// App/XcodeProj.xcodeproj depends on the TodayExtension of Lib/Lib.xcodeproj, both are part of the workspace
+	files := map[string]string{
+		filepath.Join("XcodeProj.xcworkspace", "contents.xcworkspacedata"): `<?xml version="1.0" encoding="UTF-8"?>
+<Workspace
+   version = "1.0">
+   <FileRef
+      location = "group:App/XcodeProj.xcodeproj">
+   </FileRef>
+   <FileRef
+      location = "group:Lib/Lib.xcodeproj">
+   </FileRef>
+</Workspace>
+`,
+		filepath.Join("App", "XcodeProj.xcodeproj", "project.pbxproj"): testhelper.XcodeProjectTestWithProjectDependency("../Lib/Lib.xcodeproj"),
+		filepath.Join("Lib", "Lib.xcodeproj", "project.pbxproj"):       testhelper.XcodeProjectTest,
+	}
+	for pth, content := range files {
+		pth = filepath.Join(dir, pth)
+		require.NoError(t, os.MkdirAll(filepath.Dir(pth), 0755))
+		require.NoError(t, fileutil.WriteStringToFile(pth, content))
+	}
+
+	workspace, err := Open(filepath.Join(dir, "XcodeProj.xcworkspace"))
+	require.NoError(t, err)
+	require.NoError(t, workspace.NormalizeObjectIDs(false))
+
+	lib, err := xcodeproj.Open(filepath.Join(dir, "Lib", "Lib.xcodeproj"))
+	require.NoError(t, err)
+	todayExtension, ok := lib.Proj.TargetByName("TodayExtension")
+	require.True(t, ok)
+	require.NotEqual(t, "7D03430C20F4BB070050B6A6", todayExtension.ID)
+
+	graph, err := workspace.DependencyGraph()
+	require.NoError(t, err)
+	require.Equal(t, 0, len(graph.Unresolved()))
+	require.Equal(t, 6, len(graph.Targets()))
+
+	target, ok := graph.TargetByName("XcodeProj")
+	require.True(t, ok)
+	var ids []string
+	for _, dependency := range graph.Dependencies(target) {
+		if dependency.ProjectPath == lib.Path {
+			ids = append(ids, dependency.ID)
+		}
+	}
+	require.Equal(t, []string{todayExtension.ID}, ids)
+}
+
 func TestIsWorkspace(t *testing.T) {
 	require.True(t, IsWorkspace("./BitriseSample.xcworkspace"))
 	require.False(t, IsWorkspace("./BitriseSample.xcodeproj"))